@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleKubernetesVersion string
+	bundleImageRepository   string
+	bundleISOURL            string
+)
+
+// exportCacheCmd represents the cache export command
+var exportCacheCmd = &cobra.Command{
+	Use:   "export FILE",
+	Short: "Export the cached files required to start a cluster offline.",
+	Long:  "Bundles the cached images, Kubernetes binaries and VM boot image required to start a cluster into a tar file, along with a manifest of their checksums. Missing files are downloaded first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube cache export FILE")
+		}
+		bs := viper.GetString(cmdcfg.Bootstrapper)
+
+		out.T(out.Caching, "Caching files for Kubernetes {{.version}} ...", out.V{"version": bundleKubernetesVersion})
+		if err := machine.CacheImagesForBootstrapper(bundleImageRepository, bundleKubernetesVersion, bs); err != nil {
+			exit.WithError("Failed to cache images", err)
+		}
+		if err := machine.CacheBinariesForBootstrapper(bundleKubernetesVersion, bs); err != nil {
+			exit.WithError("Failed to cache binaries", err)
+		}
+		if err := (util.DefaultDownloader{}).CacheMinikubeISOFromURL(bundleISOURL); err != nil {
+			exit.WithError("Failed to cache ISO", err)
+		}
+
+		files, err := machine.BundleFiles(bundleKubernetesVersion, bundleImageRepository, bs, bundleISOURL)
+		if err != nil {
+			exit.WithError("Failed to list cached files", err)
+		}
+		m := &machine.BundleManifest{MinikubeVersion: version.GetVersion(), KubernetesVersion: bundleKubernetesVersion, Created: time.Now()}
+		if err := machine.ExportCache(args[0], m, files); err != nil {
+			exit.WithError("Failed to export cache", err)
+		}
+		out.SuccessT("Exported {{.count}} files to {{.path}}", out.V{"count": len(files), "path": args[0]})
+	},
+}
+
+// importCacheCmd represents the cache import command
+var importCacheCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import the cached files from a bundle created by 'minikube cache export'.",
+	Long:  "Verifies the checksums of the files within a bundle created by 'minikube cache export', and adds them to the local cache so that 'minikube start' works offline.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube cache import FILE")
+		}
+		m, err := machine.ImportCache(args[0])
+		if err != nil {
+			exit.WithError("Failed to import cache", err)
+		}
+		out.SuccessT("Imported {{.count}} files for Kubernetes {{.version}}", out.V{"count": len(m.Files), "version": m.KubernetesVersion})
+		out.T(out.Tip, "To start a cluster from the imported files, run: minikube start --kubernetes-version={{.version}}", out.V{"version": m.KubernetesVersion})
+	},
+}
+
+func init() {
+	exportCacheCmd.Flags().StringVar(&bundleKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version to export cached files for")
+	exportCacheCmd.Flags().StringVar(&bundleImageRepository, "image-repository", "", "Alternative image repository the cached images were pulled from")
+	exportCacheCmd.Flags().StringVar(&bundleISOURL, "iso-url", constants.DefaultISOURL, "Location of the minikube iso to export")
+	cacheCmd.AddCommand(exportCacheCmd)
+	cacheCmd.AddCommand(importCacheCmd)
+}
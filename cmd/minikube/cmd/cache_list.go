@@ -18,15 +18,21 @@ package cmd
 
 import (
 	"os"
+	"strings"
 	"text/template"
 
+	units "github.com/docker/go-units"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
 )
 
 var cacheListFormat string
+var cacheListAll bool
 
 // CacheListTemplate represents the cache list template
 type CacheListTemplate struct {
@@ -39,6 +45,12 @@ var listCacheCmd = &cobra.Command{
 	Short: "List all available images from the local cache.",
 	Long:  "List all available images from the local cache.",
 	Run: func(cmd *cobra.Command, args []string) {
+		if cacheListAll {
+			if err := cacheListAllImages(); err != nil {
+				exit.WithError("Failed to list cached images", err)
+			}
+			return
+		}
 		images, err := cmdConfig.ListConfigMap(constants.Cache)
 		if err != nil {
 			exit.WithError("Failed to get image map", err)
@@ -53,6 +65,7 @@ func init() {
 	listCacheCmd.Flags().StringVar(&cacheListFormat, "format", constants.DefaultCacheListFormat,
 		`Go template format string for the cache list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#CacheListTemplate`)
+	listCacheCmd.Flags().BoolVar(&cacheListAll, "all", false, "List every image tarball in the cache, including the images required by Kubernetes, along with its size and what references it")
 	cacheCmd.AddCommand(listCacheCmd)
 }
 
@@ -70,3 +83,48 @@ func cacheList(images []string) error {
 	}
 	return nil
 }
+
+// cachedImageReferences returns the images referenced by the Kubernetes version of each profile, and by the cache config
+func cachedImageReferences() (machine.ImageReferences, error) {
+	refs := machine.ImageReferences{}
+	profiles, _, err := config.ListProfiles()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	refs.AddProfiles(profiles, viper.GetString(cmdConfig.Bootstrapper))
+
+	images, err := cmdConfig.ListConfigMap(constants.Cache)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		refs.Add(image, "cache add")
+	}
+	return refs, nil
+}
+
+// cacheListAllImages lists every image tarball in the cache, along with its size and references
+func cacheListAllImages() error {
+	refs, err := cachedImageReferences()
+	if err != nil {
+		return err
+	}
+	images, err := machine.ListCachedImages(refs, nil)
+	if err != nil {
+		return err
+	}
+
+	table := cmdConfig.NewTable(os.Stdout, []string{"Image", "Size", "Last Used", "Referenced By"})
+	var total int64
+	for _, ci := range images {
+		referencedBy := strings.Join(ci.References, ", ")
+		if referencedBy == "" {
+			referencedBy = "-"
+		}
+		table.Append([]string{ci.Name, units.HumanSize(float64(ci.Size)), ci.LastUsed.Format("2006-01-02 15:04"), referencedBy})
+		total += ci.Size
+	}
+	table.SetFooter([]string{"", units.HumanSize(float64(total)), "", ""})
+	table.Render()
+	return nil
+}
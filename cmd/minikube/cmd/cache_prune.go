@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var (
+	cachePruneKeep    []string
+	cachePruneMaxSize string
+)
+
+// pruneCacheCmd represents the cache prune command
+var pruneCacheCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unused images from the local cache.",
+	Long: `Removes the cached images which are not required by the Kubernetes version of any profile, added with 'minikube cache add', or listed with --keep.
+If --max-size is set, the least recently used images are then removed until the cache fits within it. Images required by a running profile,
+added with 'minikube cache add', or listed with --keep are never removed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var maxSize int64
+		if cachePruneMaxSize != "" {
+			var err error
+			maxSize, err = units.FromHumanSize(cachePruneMaxSize)
+			if err != nil {
+				exit.UsageT("Invalid size passed in argument: {{.error}}", out.V{"error": err})
+			}
+		}
+
+		refs, err := cachedImageReferences()
+		if err != nil {
+			exit.WithError("Failed to find the images in use", err)
+		}
+		keep, err := pinnedImages()
+		if err != nil {
+			exit.WithError("Failed to find the images in use", err)
+		}
+		images, err := machine.ListCachedImages(refs, append(keep, cachePruneKeep...))
+		if err != nil {
+			exit.WithError("Failed to list cached images", err)
+		}
+		prune := machine.ImagesToPrune(images, maxSize)
+		if len(prune) == 0 {
+			out.T(out.ThumbsUp, "No images to prune")
+			return
+		}
+
+		var freed int64
+		for _, ci := range prune {
+			out.T(out.DeletingHost, "Removing {{.image}} ({{.size}})", out.V{"image": ci.Name, "size": units.HumanSize(float64(ci.Size))})
+			freed += ci.Size
+		}
+		if err := machine.RemoveCachedImages(prune); err != nil {
+			exit.WithError("Failed to remove cached images", err)
+		}
+		out.SuccessT("Removed {{.count}} images, freeing {{.size}}", out.V{"count": len(prune), "size": units.HumanSize(float64(freed))})
+	},
+}
+
+// pinnedImages returns the images which may never be pruned: those added with 'minikube cache add', and those required by running profiles
+func pinnedImages() ([]string, error) {
+	images, err := cmdConfig.ListConfigMap(constants.Cache)
+	if err != nil {
+		return nil, err
+	}
+	profiles, _, err := config.ListProfiles()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return images, nil
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+	for _, p := range profiles {
+		st, err := cluster.GetHostStatus(api, p.Name)
+		if err != nil {
+			glog.Warningf("unable to get the status of %s, keeping its images: %v", p.Name, err)
+		} else if st != state.Running.String() {
+			continue
+		}
+		images = append(images, machine.ProfileImages(p, viper.GetString(cmdConfig.Bootstrapper))...)
+	}
+	return images, nil
+}
+
+func init() {
+	pruneCacheCmd.Flags().StringSliceVar(&cachePruneKeep, "keep", nil, "Images to keep in the cache, even if unused (can be specified multiple times)")
+	pruneCacheCmd.Flags().StringVar(&cachePruneMaxSize, "max-size", "", "Remove the least recently used images until the cache fits within this size (format: <number>[<unit>], where unit = b, k, m or g)")
+	cacheCmd.AddCommand(pruneCacheCmd)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	// Healthy means all of the workloads of an addon are ready
+	Healthy = "healthy"
+	// Unhealthy means the workloads of an addon are missing, or not ready
+	Unhealthy = "unhealthy"
+	// Unknown means the health of an addon could not be checked
+	Unknown = "unknown"
+)
+
+// clusterClient returns a Kubernetes client for the current profile, or nil if its cluster is not running
+func clusterClient() kubernetes.Interface {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Warningf("machine client: %v", err)
+		return nil
+	}
+	defer api.Close()
+
+	st, err := cluster.GetHostStatus(api, config.GetMachineName())
+	if err != nil || st != state.Running.String() {
+		glog.Infof("not checking addon health, host status is %q: %v", st, err)
+		return nil
+	}
+	c, err := kapi.Client(config.GetMachineName())
+	if err != nil {
+		glog.Warningf("kubernetes client: %v", err)
+		return nil
+	}
+	return c
+}
+
+// addonHealth checks whether the workloads of an addon are ready.
+// An empty string is returned for addons which do not declare their workloads.
+func addonHealth(c kubernetes.Interface, addon *assets.Addon) string {
+	if addon.Selector == "" {
+		return ""
+	}
+	if c == nil {
+		return Unknown
+	}
+	workloads, err := kapi.WorkloadsWithLabel(c, addon.Selector)
+	if err != nil {
+		glog.Warningf("checking health of %s: %v", addon.Name(), err)
+		return Unknown
+	}
+	// The manifests of the addon were never applied, or were removed
+	if len(workloads) == 0 {
+		return Unhealthy
+	}
+	for _, w := range workloads {
+		if !w.IsReady() {
+			return Unhealthy
+		}
+	}
+	return Healthy
+}
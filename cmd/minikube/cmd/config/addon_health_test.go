@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func deployment(name string, replicas int32, ready int32) *apps.Deployment {
+	return &apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "kube-system", Labels: map[string]string{"addon": name}},
+		Spec: apps.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &meta.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: apps.DeploymentStatus{ReadyReplicas: ready},
+	}
+}
+
+func TestAddonHealth(t *testing.T) {
+	crashing := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: "crashing", Namespace: "kube-system", Labels: map[string]string{"addon": "crashing"}},
+		Status:     core.PodStatus{Phase: core.PodRunning},
+	}
+	objects := []runtime.Object{deployment("ready", 2, 2), deployment("starting", 2, 1), crashing}
+
+	var tests = []struct {
+		selector string
+		client   bool
+		want     string
+	}{
+		{selector: "addon=ready", client: true, want: Healthy},
+		{selector: "addon=starting", client: true, want: Unhealthy},
+		{selector: "addon=crashing", client: true, want: Unhealthy},
+		{selector: "addon=missing", client: true, want: Unhealthy},
+		{selector: "addon=ready", client: false, want: Unknown},
+		{selector: "", client: true, want: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.selector, func(t *testing.T) {
+			addon := assets.NewAddon(nil, true, "test")
+			addon.Selector = tc.selector
+			var got string
+			if tc.client {
+				got = addonHealth(fake.NewSimpleClientset(objects...), addon)
+			} else {
+				got = addonHealth(nil, addon)
+			}
+			if got != tc.want {
+				t.Errorf("addonHealth(%q) = %q, want %q", tc.selector, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// parseAddonValues parses values from a YAML or JSON file, followed by key=value pairs which take precedence
+func parseAddonValues(set []string, file string) (config.AddonValues, error) {
+	values := config.AddonValues{}
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&values); err != nil && err != io.EOF {
+			return nil, errors.Wrapf(err, "parsing %s", file)
+		}
+	}
+
+	for _, kv := range set {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid value %q: expected key=value", kv)
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values, nil
+}
+
+// configureAddonValues stores the values of an addon in the current profile, and applies them if the addon is enabled.
+// If reset is set, previously stored values are discarded first.
+func configureAddonValues(name string, set []string, file string, reset bool) error {
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return fmt.Errorf("%s is not a valid addon", name)
+	}
+	values, err := parseAddonValues(set, file)
+	if err != nil {
+		return err
+	}
+
+	cc, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, "loading profile")
+	}
+	if cc.KubernetesConfig.AddonValues == nil {
+		cc.KubernetesConfig.AddonValues = map[string]config.AddonValues{}
+	}
+	merged := cc.KubernetesConfig.AddonValues[name]
+	if reset || merged == nil {
+		merged = config.AddonValues{}
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		delete(cc.KubernetesConfig.AddonValues, name)
+	} else {
+		cc.KubernetesConfig.AddonValues[name] = merged
+	}
+	if err := config.CreateProfile(config.GetMachineName(), cc); err != nil {
+		return errors.Wrap(err, "saving profile")
+	}
+
+	enabled, err := addon.IsEnabled()
+	if err != nil || !enabled {
+		return err
+	}
+	return applyAddonValues(addon, cc.KubernetesConfig)
+}
+
+// applyAddonValues renders the assets of an enabled addon again, if the cluster is running
+func applyAddonValues(addon *assets.Addon, k8s config.KubernetesConfig) error {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+
+	st, err := cluster.GetHostStatus(api, config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "host status")
+	}
+	if st != state.Running.String() {
+		out.T(out.Notice, "The new values will be applied the next time the cluster is started")
+		return nil
+	}
+
+	host, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "getting host")
+	}
+	cmd, err := machine.CommandRunner(host)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+
+	data := assets.GenerateAddonTemplateData(k8s, addon.Name())
+	var files []assets.CopyableFile
+	for _, a := range addon.Assets {
+		var f assets.CopyableFile = a
+		if a.IsTemplate() {
+			if f, err = a.Evaluate(data); err != nil {
+				return errors.Wrapf(err, "evaluate bundled addon %s asset", a.GetAssetName())
+			}
+		}
+		files = append(files, f)
+	}
+	// Only the manifests whose values changed are copied
+	if err := cmd.CopyAll(files); err != nil {
+		return errors.Wrapf(err, "updating addon %s", addon.Name())
+	}
+	return nil
+}
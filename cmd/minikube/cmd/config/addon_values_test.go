@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseAddonValues(t *testing.T) {
+	f, err := ioutil.TempFile("", "values")
+	if err != nil {
+		t.Fatalf("tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("Replicas: 2\nImageTag: v1\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f.Close()
+
+	var tests = []struct {
+		description string
+		set         []string
+		file        string
+		want        pkgConfig.AddonValues
+		shouldErr   bool
+	}{
+		{description: "set", set: []string{"Replicas=3", "Args=a=b"}, want: pkgConfig.AddonValues{"Replicas": "3", "Args": "a=b"}},
+		{description: "file", file: f.Name(), want: pkgConfig.AddonValues{"Replicas": float64(2), "ImageTag": "v1"}},
+		{description: "set overrides file", set: []string{"ImageTag=v2"}, file: f.Name(), want: pkgConfig.AddonValues{"Replicas": float64(2), "ImageTag": "v2"}},
+		{description: "missing value", set: []string{"Replicas"}, shouldErr: true},
+		{description: "missing key", set: []string{"=3"}, shouldErr: true},
+		{description: "missing file", file: "/does/not/exist.yaml", shouldErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := parseAddonValues(test.set, test.file)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("parseAddonValues: %v", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("parseAddonValues returned %v, expected error", got)
+			}
+			if diff := cmp.Diff(test.want, got); !test.shouldErr && diff != "" {
+				t.Errorf("values differ: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
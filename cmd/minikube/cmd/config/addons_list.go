@@ -17,22 +17,31 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"sort"
 	"text/template"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
 )
 
 var addonListFormat string
+var addonListOutput string
 
 // AddonListTemplate represents the addon list template
 type AddonListTemplate struct {
-	AddonName   string
-	AddonStatus string
+	AddonName   string `json:"name"`
+	AddonStatus string `json:"status"`
+	// AddonHealth is whether the workloads of an enabled addon are ready: healthy, unhealthy or unknown
+	AddonHealth string `json:"health,omitempty"`
+	// AddonConfigured is whether values were set for the addon using "minikube addons configure"
+	AddonConfigured bool `json:"configured"`
 }
 
 var addonsListCmd = &cobra.Command{
@@ -43,6 +52,9 @@ var addonsListCmd = &cobra.Command{
 		if len(args) != 0 {
 			exit.UsageT("usage: minikube addons list")
 		}
+		if addonListOutput != "text" && addonListOutput != "json" {
+			exit.UsageT("Cannot use output {{.output}}: must be one of 'text' or 'json'", out.V{"output": addonListOutput})
+		}
 		err := addonList()
 		if err != nil {
 			exit.WithError("addon list failed", err)
@@ -54,6 +66,7 @@ func init() {
 	AddonsCmd.Flags().StringVar(&addonListFormat, "format", constants.DefaultAddonListFormat,
 		`Go template format string for the addon list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd/config#AddonListTemplate`)
+	addonsListCmd.Flags().StringVarP(&addonListOutput, "output", "o", "text", "minikube addons list --output OUTPUT. json, text")
 	AddonsCmd.AddCommand(addonsListCmd)
 }
 
@@ -65,23 +78,53 @@ func stringFromStatus(addonStatus bool) string {
 }
 
 func addonList() error {
+	// Configured values are per-profile, and there may not be a profile yet
+	var values map[string]config.AddonValues
+	if cc, err := config.Load(); err == nil {
+		values = cc.KubernetesConfig.AddonValues
+	}
+
 	addonNames := make([]string, 0, len(assets.Addons))
 	for addonName := range assets.Addons {
 		addonNames = append(addonNames, addonName)
 	}
 	sort.Strings(addonNames)
 
+	var client kubernetes.Interface
+	connected := false
+	var addons []AddonListTemplate
 	for _, addonName := range addonNames {
 		addonBundle := assets.Addons[addonName]
 		addonStatus, err := addonBundle.IsEnabled()
 		if err != nil {
 			return err
 		}
-		tmpl, err := template.New("list").Parse(addonListFormat)
-		if err != nil {
-			exit.WithError("Error creating list template", err)
+		listTmplt := AddonListTemplate{
+			AddonName:       addonName,
+			AddonStatus:     stringFromStatus(addonStatus),
+			AddonConfigured: len(values[addonName]) > 0,
+		}
+		if addonStatus && addonBundle.Selector != "" {
+			if !connected {
+				client = clusterClient()
+				connected = true
+			}
+			listTmplt.AddonHealth = addonHealth(client, addonBundle)
 		}
-		listTmplt := AddonListTemplate{addonName, stringFromStatus(addonStatus)}
+		addons = append(addons, listTmplt)
+	}
+
+	if addonListOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(addons)
+	}
+
+	tmpl, err := template.New("list").Parse(addonListFormat)
+	if err != nil {
+		exit.WithError("Error creating list template", err)
+	}
+	for _, listTmplt := range addons {
 		err = tmpl.Execute(os.Stdout, listTmplt)
 		if err != nil {
 			exit.WithError("Error executing list template", err)
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// maxAddonEvents is the number of recent events shown by "minikube addons status"
+const maxAddonEvents = 10
+
+var addonsStatusCmd = &cobra.Command{
+	Use:   "status ADDON_NAME",
+	Short: "Shows the health of the addon w/ADDON_NAME, along with its pods and recent events",
+	Long:  "Shows the health of the addon w/ADDON_NAME within minikube (example: minikube addons status dashboard), along with the status of its pods and recent events",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube addons status ADDON_NAME")
+		}
+		name := args[0]
+		addon, ok := assets.Addons[name]
+		if !ok {
+			exit.UsageT(`addon '{{.name}}' is not a valid addon packaged with minikube.
+To see the list of available addons run:
+minikube addons list`, out.V{"name": name})
+		}
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			exit.WithError("enabled failed", err)
+		}
+		if !enabled {
+			out.T(out.Notice, `addon '{{.name}}' is currently not enabled.
+To enable this addon run:
+minikube addons enable {{.name}}`, out.V{"name": name})
+			return
+		}
+		if addon.Selector == "" {
+			out.T(out.Notice, "addon '{{.name}}' does not declare any workloads to check", out.V{"name": name})
+			return
+		}
+
+		c := clusterClient()
+		if c == nil {
+			exit.WithCodeT(exit.Unavailable, "minikube is not running, so the health of addon '{{.name}}' can not be checked", out.V{"name": name})
+		}
+		if err := addonStatus(c, addon); err != nil {
+			exit.WithError("Error getting addon status", err)
+		}
+	},
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsStatusCmd)
+}
+
+// NewTable returns a table writer to w in the style of the other minikube tables
+func NewTable(w io.Writer, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	return table
+}
+
+// addonStatus prints the workloads and pods of an addon, along with their recent events
+func addonStatus(c kubernetes.Interface, addon *assets.Addon) error {
+	workloads, err := kapi.WorkloadsWithLabel(c, addon.Selector)
+	if err != nil {
+		return err
+	}
+	out.T(out.Check, "{{.name}} is {{.health}}", out.V{"name": addon.Name(), "health": addonHealth(c, addon)})
+	if len(workloads) == 0 {
+		out.T(out.Sad, "No workloads match {{.selector}}", out.V{"selector": addon.Selector})
+		return nil
+	}
+
+	wt := NewTable(os.Stdout, []string{"Kind", "Namespace", "Name", "Ready"})
+	pt := NewTable(os.Stdout, []string{"Namespace", "Pod", "Status", "Ready", "Restarts"})
+	names := map[string][]string{}
+	for _, w := range workloads {
+		wt.Append([]string{w.Kind, w.Namespace, w.Name, fmt.Sprintf("%d/%d", w.Ready, w.Desired)})
+		names[w.Namespace] = append(names[w.Namespace], w.Name)
+
+		pods, err := w.Pods(c)
+		if err != nil {
+			return err
+		}
+		for _, p := range pods {
+			pt.Append([]string{p.Namespace, p.Name, podStatus(p), readyContainers(p), fmt.Sprint(restarts(p))})
+			if w.Kind != "Pod" {
+				names[p.Namespace] = append(names[p.Namespace], p.Name)
+			}
+		}
+	}
+	wt.Render()
+	pt.Render()
+
+	var events []core.Event
+	for ns, n := range names {
+		evs, err := kapi.Events(c, ns, n...)
+		if err != nil {
+			return err
+		}
+		events = append(events, evs...)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].LastTimestamp.Before(&events[j].LastTimestamp) })
+	if len(events) > maxAddonEvents {
+		events = events[len(events)-maxAddonEvents:]
+	}
+	et := NewTable(os.Stdout, []string{"Last Seen", "Type", "Reason", "Object", "Message"})
+	for _, e := range events {
+		age := duration.HumanDuration(time.Since(e.LastTimestamp.Time))
+		et.Append([]string{age, e.Type, e.Reason, strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name, strings.TrimSpace(e.Message)})
+	}
+	et.Render()
+	return nil
+}
+
+// podStatus returns the reason a pod is not running, such as CrashLoopBackOff, or its phase
+func podStatus(p core.Pod) string {
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+	return string(p.Status.Phase)
+}
+
+// readyContainers returns the number of ready containers of a pod, in the style of kubectl
+func readyContainers(p core.Pod) string {
+	ready := 0
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers))
+}
+
+// restarts returns the total number of container restarts of a pod
+func restarts(p core.Pod) int32 {
+	var n int32
+	for _, cs := range p.Status.ContainerStatuses {
+		n += cs.RestartCount
+	}
+	return n
+}
@@ -144,108 +144,108 @@ var settings = []Setting{
 	{
 		name:        "dashboard",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "addon-manager",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "default-storageclass",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableStorageClasses},
 	},
 	{
 		name:        "heapster",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "efk",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "ingress",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "insecure-registry",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "registry",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "registry-creds",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "freshpod",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "default-storageclass",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableStorageClasses},
 	},
 	{
 		name:        "storage-provisioner",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "storage-provisioner-gluster",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableStorageClasses},
 	},
 	{
 		name:        "metrics-server",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "nvidia-driver-installer",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "nvidia-gpu-device-plugin",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
 		name:        "logviewer",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 	},
 	{
 		name:        "gvisor",
 		set:         SetBool,
-		validations: []setFn{IsValidAddon, IsContainerdRuntime},
+		validations: []setFn{IsValidAddon, IsAddonCompatible},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
 	{
@@ -25,16 +25,32 @@ import (
 	"k8s.io/minikube/pkg/minikube/service"
 )
 
+var (
+	addonSetValues   []string
+	addonValuesFile  string
+	addonResetValues bool
+)
+
 var addonsConfigureCmd = &cobra.Command{
 	Use:   "configure ADDON_NAME",
 	Short: "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list ",
-	Long:  "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list ",
+	Long: `Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list
+
+Values rendered into the addon manifests may be set for the current profile using --set or --values (example: minikube addons configure ingress --set Replicas=2).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			exit.UsageT("usage: minikube addons configure ADDON_NAME")
 		}
 
 		addon := args[0]
+		if len(addonSetValues) > 0 || addonValuesFile != "" || addonResetValues {
+			if err := configureAddonValues(addon, addonSetValues, addonValuesFile, addonResetValues); err != nil {
+				exit.WithError("Unable to configure addon", err)
+			}
+			out.SuccessT("{{.name}} was successfully configured", out.V{"name": addon})
+			return
+		}
+
 		// allows for additional prompting of information when enabling addons
 		switch addon {
 		case "registry-creds":
@@ -158,5 +174,8 @@ var addonsConfigureCmd = &cobra.Command{
 }
 
 func init() {
+	addonsConfigureCmd.Flags().StringArrayVar(&addonSetValues, "set", nil, "Set a value rendered into the addon manifests, as key=value. May be repeated.")
+	addonsConfigureCmd.Flags().StringVar(&addonValuesFile, "values", "", "Path to a YAML or JSON file of values rendered into the addon manifests.")
+	addonsConfigureCmd.Flags().BoolVar(&addonResetValues, "reset", false, "Discard the values previously configured for the addon.")
 	AddonsCmd.AddCommand(addonsConfigureCmd)
 }
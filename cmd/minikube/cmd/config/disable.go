@@ -32,7 +32,7 @@ var addonsDisableCmd = &cobra.Command{
 		}
 
 		addon := args[0]
-		err := Set(addon, "false")
+		err := disableAddon(addon)
 		if err != nil {
 			exit.WithError("disable failed", err)
 		}
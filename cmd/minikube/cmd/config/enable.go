@@ -32,7 +32,7 @@ var addonsEnableCmd = &cobra.Command{
 		}
 
 		addon := args[0]
-		err := Set(addon, "true")
+		err := enableAddon(addon)
 		if err != nil {
 			exit.WithError("enable failed", err)
 		}
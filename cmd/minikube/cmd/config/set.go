@@ -17,7 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"strconv"
+
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
 	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
@@ -36,7 +39,7 @@ var configSetCmd = &cobra.Command{
 		if len(args) > 2 {
 			exit.UsageT("toom any arguments ({{.ArgCount}}).\nusage: minikube config set PROPERTY_NAME PROPERTY_VALUE", out.V{"ArgCount": len(args)})
 		}
-		err := Set(args[0], args[1])
+		err := setProperty(args[0], args[1])
 		if err != nil {
 			exit.WithError("Set failed", err)
 		}
@@ -47,6 +50,19 @@ func init() {
 	ConfigCmd.AddCommand(configSetCmd)
 }
 
+// setProperty sets a property to a value. Addons are enabled along with the addons they require, as by "minikube addons enable".
+func setProperty(name string, value string) error {
+	if _, ok := assets.Addons[name]; ok {
+		if enable, err := strconv.ParseBool(value); err == nil {
+			if enable {
+				return enableAddon(name)
+			}
+			return disableAddon(name)
+		}
+	}
+	return Set(name, value)
+}
+
 // Set sets a property to a value
 func Set(name string, value string) error {
 	s, err := findSetting(name)
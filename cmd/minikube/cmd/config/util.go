@@ -55,6 +55,15 @@ func findSetting(name string) (Setting, error) {
 			return s, nil
 		}
 	}
+	// User-defined addons are not known ahead of time
+	if addon, ok := assets.Addons[name]; ok && addon.UserDefined {
+		return Setting{
+			name:        name,
+			set:         SetBool,
+			validations: []setFn{IsValidAddon, IsAddonCompatible},
+			callbacks:   []setFn{EnableOrDisableAddon},
+		}, nil
+	}
 	return Setting{}, fmt.Errorf("property name %q not found", name)
 }
 
@@ -103,6 +112,50 @@ func SetBool(m config.MinikubeConfig, name string, val string) error {
 	return nil
 }
 
+// enableAddon enables an addon, after enabling the addons it requires
+func enableAddon(name string) error {
+	addons, err := assets.ResolveDependencies(name)
+	if err != nil {
+		return err
+	}
+	// Check every addon before enabling any, so that an incompatible addon does not leave its dependencies half enabled
+	for i, a := range addons {
+		for _, b := range addons[:i] {
+			if a.ConflictsWith(b) {
+				return fmt.Errorf("addon %s requires both %s and %s, which conflict", name, a.Name(), b.Name())
+			}
+		}
+		if err := checkAddonRequirements(a); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range addons[:len(addons)-1] {
+		enabled, err := a.IsEnabled()
+		if err != nil {
+			return errors.Wrapf(err, "status of %s", a.Name())
+		}
+		if enabled {
+			continue
+		}
+		out.T(out.Enabling, "Enabling {{.dependency}}, which is required by {{.addon}}", out.V{"dependency": a.Name(), "addon": name})
+		if err := Set(a.Name(), "true"); err != nil {
+			return errors.Wrapf(err, "enabling %s", a.Name())
+		}
+	}
+	return Set(name, "true")
+}
+
+// disableAddon disables an addon, warning about enabled addons which require it
+func disableAddon(name string) error {
+	for _, d := range assets.Dependents(name) {
+		if enabled, err := d.IsEnabled(); err == nil && enabled {
+			out.WarningT("{{.dependent}} requires {{.addon}}, and may stop working", out.V{"dependent": d.Name(), "addon": name})
+		}
+	}
+	return Set(name, "false")
+}
+
 // EnableOrDisableAddon updates addon status executing any commands necessary
 func EnableOrDisableAddon(name string, val string) error {
 	enable, err := strconv.ParseBool(val)
@@ -133,7 +186,14 @@ func EnableOrDisableAddon(name string, val string) error {
 		exit.WithCodeT(exit.Data, "Unable to load config: {{.error}}", out.V{"error": err})
 	}
 
-	data := assets.GenerateTemplateData(cfg.KubernetesConfig)
+	if enable && len(addon.Images) > 0 {
+		out.T(out.Pulling, "Caching images required by {{.name}} ...", out.V{"name": name})
+		if err := machine.CacheAndLoadImages(addon.Images); err != nil {
+			out.WarningT("Unable to load images required by {{.name}}: {{.error}}", out.V{"name": name, "error": err})
+		}
+	}
+
+	data := assets.GenerateAddonTemplateData(cfg.KubernetesConfig, name)
 	return enableOrDisableAddonInternal(addon, cmd, data, enable)
 }
 
@@ -163,6 +223,7 @@ func enableOrDisableAddonInternal(addon *assets.Addon, cmd command.Runner, data
 	}
 
 	if enable {
+		var addonFiles []assets.CopyableFile
 		for _, addon := range addon.Assets {
 			var addonFile assets.CopyableFile
 			if addon.IsTemplate() {
@@ -174,9 +235,10 @@ func enableOrDisableAddonInternal(addon *assets.Addon, cmd command.Runner, data
 			} else {
 				addonFile = addon
 			}
-			if err := cmd.Copy(addonFile); err != nil {
-				return errors.Wrapf(err, "enabling addon %s", addon.AssetName)
-			}
+			addonFiles = append(addonFiles, addonFile)
+		}
+		if err := cmd.CopyAll(addonFiles); err != nil {
+			return errors.Wrapf(err, "enabling addon %s", addon.Name())
 		}
 	} else {
 		for _, addon := range addon.Assets {
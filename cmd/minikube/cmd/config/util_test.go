@@ -47,6 +47,21 @@ func TestFindSetting(t *testing.T) {
 	}
 }
 
+func TestFindSettingUserAddon(t *testing.T) {
+	addon := assets.NewAddon(nil, false, "internal-tools")
+	addon.UserDefined = true
+	assets.Addons["internal-tools"] = addon
+	defer delete(assets.Addons, "internal-tools")
+
+	s, err := findSetting("internal-tools")
+	if err != nil {
+		t.Fatalf("Couldn't find setting for user-defined addon: %v", err)
+	}
+	if s.name != "internal-tools" || len(s.callbacks) != 1 {
+		t.Fatalf("Found unexpected setting for user-defined addon: %+v", s)
+	}
+}
+
 func TestSetString(t *testing.T) {
 	err := SetString(minikubeConfig, "vm-driver", constants.DriverVirtualbox)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -165,3 +166,60 @@ func IsContainerdRuntime(_, _ string) error {
 	}
 	return nil
 }
+
+// IsAddonCompatible is a validator which returns an error if an addon being enabled conflicts with an enabled addon,
+// or requires a container runtime other than the current one
+func IsAddonCompatible(name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil || !enable {
+		return nil
+	}
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return nil
+	}
+	return checkAddonRequirements(addon)
+}
+
+// checkAddonRequirements checks the container runtime and conflicts of an addon against the current cluster
+func checkAddonRequirements(addon *assets.Addon) error {
+	if addon.Runtime != "" {
+		cc, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("config.Load: %v", err)
+		}
+		want, err := cruntime.New(cruntime.Config{Type: addon.Runtime})
+		if err != nil {
+			return err
+		}
+		have, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime})
+		if err != nil {
+			return err
+		}
+		if want.Name() != have.Name() {
+			if _, ok := want.(*cruntime.Containerd); ok {
+				return fmt.Errorf(containerdOnlyAddonMsg)
+			}
+			return fmt.Errorf("addon %s requires the %s container runtime, but the cluster is using %s", addon.Name(), want.Name(), have.Name())
+		}
+	}
+
+	var conflicts []string
+	for _, other := range assets.Addons {
+		if other == addon || !addon.ConflictsWith(other) {
+			continue
+		}
+		enabled, err := other.IsEnabled()
+		if err != nil {
+			return errors.Wrapf(err, "status of %s", other.Name())
+		}
+		if enabled {
+			conflicts = append(conflicts, other.Name())
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("addon %s conflicts with the enabled addons: %s. Disable them first with: minikube addons disable <name>", addon.Name(), strings.Join(conflicts, ", "))
+	}
+	return nil
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package config
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
 )
 
 type validationTest struct {
@@ -119,3 +124,30 @@ func TestIsURLExists(t *testing.T) {
 
 	runValidations(t, tests, "url", IsURLExists)
 }
+
+func TestIsAddonCompatible(t *testing.T) {
+	// The addons enabled in the config of the host must not change the result
+	dir, err := ioutil.TempDir("", "minipath")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(home string, configFile string) {
+		os.Setenv(localpath.MinikubeHome, home)
+		constants.ConfigFile = configFile
+	}(os.Getenv(localpath.MinikubeHome), constants.ConfigFile)
+	os.Setenv(localpath.MinikubeHome, dir)
+	constants.ConfigFile = localpath.MakeMiniPath("config", "config.json")
+	viper.Reset()
+	defer viper.Reset()
+
+	// default-storageclass is enabled by default
+	runValidations(t, []validationTest{
+		{value: "true", shouldErr: true},
+		{value: "false", shouldErr: false},
+	}, "storage-provisioner-gluster", IsAddonCompatible)
+
+	runValidations(t, []validationTest{
+		{value: "true", shouldErr: false},
+	}, "efk", IsAddonCompatible)
+}
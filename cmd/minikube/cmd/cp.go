@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// cpTarget is a source or destination of minikube cp
+type cpTarget struct {
+	// node is the node name given before the colon, which is empty for the control plane
+	node string
+	// remote is whether the path is on a node, rather than on the host
+	remote bool
+	path   string
+}
+
+// parseCpTarget parses an argument of the form [NODE:]PATH.
+// Arguments without a colon, or with a slash before it, are paths on the host.
+func parseCpTarget(arg string) cpTarget {
+	i := strings.Index(arg, ":")
+	if i < 0 {
+		return cpTarget{path: arg}
+	}
+	// C:\foo is a Windows path, not a node named C
+	if runtime.GOOS == "windows" && i == 1 {
+		return cpTarget{path: arg}
+	}
+	if strings.ContainsAny(arg[:i], `/\`) {
+		return cpTarget{path: arg}
+	}
+	return cpTarget{node: arg[:i], remote: true, path: arg[i+1:]}
+}
+
+// copyBetweenNodes copies src on one node to dst on another through a temporary directory on the host,
+// as nodes can not reach each other over SSH. Errors are returned rather than exiting, so that the temporary
+// directory is removed.
+func copyBetweenNodes(from command.Runner, to command.Runner, src string, dst string) error {
+	tmp, err := ioutil.TempDir("", "minikube-cp")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(tmp)
+	if err := machine.CopyFromGuest(from, src, tmp); err != nil {
+		return errors.Wrap(err, "copying from node")
+	}
+	if err := machine.CopyToGuest(to, filepath.Join(tmp, path.Base(src)), dst); err != nil {
+		return errors.Wrap(err, "copying to node")
+	}
+	return nil
+}
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp [NODE:]SRC [NODE:]DST",
+	Short: "Copy files and directories between the host and the nodes of a cluster",
+	Long: `Copy files and directories between the host and the nodes of a cluster, or between nodes.
+
+Paths on a node are prefixed with the node name and a colon. An empty node name, as in ":/etc/hosts", selects the control plane.
+Directories are copied recursively, and file permissions are preserved.`,
+	Example: `minikube cp :/var/log/kube-apiserver.log .
+minikube cp ./manifests minikube-m02:/etc/kubernetes/addons`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.UsageT("Usage: minikube cp [NODE:]SRC [NODE:]DST")
+		}
+		src := parseCpTarget(args[0])
+		dst := parseCpTarget(args[1])
+		if !src.remote && !dst.remote {
+			exit.UsageT("Either the source or the destination must be on a node, for example :{{.path}}", out.V{"path": args[1]})
+		}
+		if (src.remote && src.path == "") || (dst.remote && dst.path == "") {
+			exit.UsageT("Paths on a node may not be empty")
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		switch {
+		case src.remote && dst.remote:
+			from := runningMachineRunner(api, selectedMachineName(src.node))
+			to := runningMachineRunner(api, selectedMachineName(dst.node))
+			if err := copyBetweenNodes(from, to, src.path, dst.path); err != nil {
+				exit.WithError("Error copying between nodes", err)
+			}
+		case src.remote:
+			if err := machine.CopyFromGuest(runningMachineRunner(api, selectedMachineName(src.node)), src.path, dst.path); err != nil {
+				exit.WithError("Error copying from node", err)
+			}
+		default:
+			if _, err := os.Stat(src.path); err != nil {
+				exit.WithCodeT(exit.NoInput, "Unable to read {{.path}}: {{.error}}", out.V{"path": src.path, "error": err})
+			}
+			if err := machine.CopyToGuest(runningMachineRunner(api, selectedMachineName(dst.node)), src.path, dst.path); err != nil {
+				exit.WithError("Error copying to node", err)
+			}
+		}
+	},
+}
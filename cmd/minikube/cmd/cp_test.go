@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParseCpTarget(t *testing.T) {
+	var tests = []struct {
+		arg  string
+		want cpTarget
+	}{
+		{"file.txt", cpTarget{path: "file.txt"}},
+		{":/etc/hosts", cpTarget{remote: true, path: "/etc/hosts"}},
+		{"minikube-m02:/var/log", cpTarget{node: "minikube-m02", remote: true, path: "/var/log"}},
+		{"./a:b", cpTarget{path: "./a:b"}},
+		{"/tmp/a:b", cpTarget{path: "/tmp/a:b"}},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, struct {
+			arg  string
+			want cpTarget
+		}{`C:\Users\me`, cpTarget{path: `C:\Users\me`}})
+	}
+	for _, tc := range tests {
+		t.Run(tc.arg, func(t *testing.T) {
+			if got := parseCpTarget(tc.arg); got != tc.want {
+				t.Errorf("parseCpTarget(%q) = %+v, want %+v", tc.arg, got, tc.want)
+			}
+		})
+	}
+}
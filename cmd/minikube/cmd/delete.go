@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/mcnerror"
@@ -41,6 +42,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+// deleteNodeName is the node selected using --node
+var deleteNodeName string
+
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
@@ -62,10 +66,26 @@ func runDelete(cmd *cobra.Command, args []string) {
 	}
 	defer api.Close()
 
+	if deleteNodeName != "" {
+		cc := loadProfileOrExit()
+		n := selectNode(cc, deleteNodeName)
+		if n == nil {
+			exit.UsageT("The control plane cannot be deleted on its own. To delete the whole cluster, run: minikube delete")
+		}
+		deleteNode(api, cc, n.Name)
+		out.T(out.Crushed, `Node "{{.name}}" has been deleted.`, out.V{"name": n.Name})
+		return
+	}
+
 	cc, err := pkg_config.Load()
 	if err != nil && !os.IsNotExist(err) {
 		out.ErrT(out.Sad, "Error loading profile {{.name}}: {{.error}}", out.V{"name": profile, "error": err})
 	}
+	if err == nil {
+		for _, n := range cc.Nodes {
+			deleteNodeMachine(api, n.Name)
+		}
+	}
 
 	// In the case of "none", we want to uninstall Kubernetes as there is no VM to delete
 	if err == nil && cc.MachineConfig.VMDriver == constants.DriverNone {
@@ -75,8 +95,9 @@ func runDelete(cmd *cobra.Command, args []string) {
 	if err := killMountProcess(); err != nil {
 		out.T(out.FailureType, "Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	stopRegistryMirrors(api, profile)
 
-	if err = cluster.DeleteHost(api); err != nil {
+	if err = cluster.DeleteHost(api, pkg_config.GetMachineName()); err != nil {
 		switch errors.Cause(err).(type) {
 		case mcnerror.ErrHostDoesNotExist:
 			out.T(out.Meh, `"{{.name}}" cluster does not exist`, out.V{"name": profile})
@@ -115,6 +136,10 @@ func runDelete(cmd *cobra.Command, args []string) {
 	}
 }
 
+func init() {
+	deleteCmd.Flags().StringVar(&deleteNodeName, nodeFlag, "", "Delete only the given worker node, rather than the whole cluster.")
+}
+
 func uninstallKubernetes(api libmachine.API, kc pkg_config.KubernetesConfig, bsName string) {
 	out.T(out.Resetting, "Uninstalling Kubernetes {{.kubernetes_version}} using {{.bootstrapper_name}} ...", out.V{"kubernetes_version": kc.KubernetesVersion, "bootstrapper_name": bsName})
 	clusterBootstrapper, err := getClusterBootstrapper(api, bsName)
@@ -127,7 +152,11 @@ func uninstallKubernetes(api libmachine.API, kc pkg_config.KubernetesConfig, bsN
 
 // killMountProcess kills the mount process, if it is running
 func killMountProcess() error {
-	pidPath := filepath.Join(localpath.MiniPath(), constants.MountProcessFileName)
+	return killProcess(filepath.Join(localpath.MiniPath(), constants.MountProcessFileName))
+}
+
+// killProcess kills the process whose pid is on the first line of pidPath, if it is running
+func killProcess(pidPath string) error {
 	if _, err := os.Stat(pidPath); os.IsNotExist(err) {
 		return nil
 	}
@@ -138,7 +167,7 @@ func killMountProcess() error {
 		return errors.Wrap(err, "ReadFile")
 	}
 	glog.Infof("pidfile contents: %s", out)
-	pid, err := strconv.Atoi(string(out))
+	pid, err := strconv.Atoi(strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0]))
 	if err != nil {
 		return errors.Wrap(err, "error parsing pid")
 	}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var (
+	doctorOutput  string
+	doctorDriver  string
+	doctorRuntime string
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the host for problems which would prevent a cluster from starting",
+	Long: `Checks the host for problems which would prevent a cluster from starting, such as a missing hypervisor, insufficient resources or a proxy blocking access to the image registry.
+
+The checks are run for the existing profile if there is one, and otherwise for the configuration minikube start would use. Each check passes, warns or fails, along with advice on how to fix the problem found.
+The command exits with a non-zero code if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if doctorOutput != "text" && doctorOutput != "json" {
+			exit.UsageT("Cannot use output {{.output}}: must be one of 'text' or 'json'", out.V{"output": doctorOutput})
+		}
+
+		results := doctor.Run(doctorConfig())
+		switch doctorOutput {
+		case "json":
+			if err := doctorJSON(results, os.Stdout); err != nil {
+				exit.WithError("Error encoding results as JSON", err)
+			}
+		default:
+			doctorText(results)
+		}
+		if doctor.Worst(results) == doctor.Fail {
+			os.Exit(exit.Config)
+		}
+	},
+}
+
+// doctorConfig returns the configuration to check: that of the existing profile, or else that minikube start would use
+func doctorConfig() doctor.Config {
+	c := doctor.Config{
+		Driver:            viper.GetString("vm-driver"),
+		ContainerRuntime:  viper.GetString(containerRuntime),
+		KubernetesVersion: viper.GetString(kubernetesVersion),
+		ImageRepository:   viper.GetString(imageRepository),
+		Bootstrapper:      viper.GetString(cmdcfg.Bootstrapper),
+		ISOURL:            viper.GetString(isoURL),
+		HostOnlyCIDR:      viper.GetString(hostOnlyCIDR),
+		Memory:            pkgutil.CalculateSizeInMB(viper.GetString(memory)),
+		CPUs:              viper.GetInt(cpus),
+		DiskSize:          pkgutil.CalculateSizeInMB(viper.GetString(humanReadableDiskSize)),
+	}
+	if cc, err := config.Load(); err == nil {
+		m := cc.MachineConfig
+		c.Driver = m.VMDriver
+		c.ContainerRuntime = m.ContainerRuntime
+		c.ISOURL = m.MinikubeISO
+		c.HostOnlyCIDR = m.HostOnlyCIDR
+		c.Memory = m.Memory
+		c.CPUs = m.CPUs
+		c.DiskSize = m.DiskSize
+		c.KubernetesVersion = cc.KubernetesConfig.KubernetesVersion
+		c.ImageRepository = cc.KubernetesConfig.ImageRepository
+		c.NodeIP = cc.KubernetesConfig.NodeIP
+	}
+
+	if doctorDriver != "" {
+		c.Driver = doctorDriver
+	}
+	if doctorRuntime != "" {
+		c.ContainerRuntime = doctorRuntime
+	}
+	if c.Driver == "" {
+		c.Driver = constants.DefaultVMDriver
+	}
+	// The repository is only selected by minikube start
+	if strings.ToLower(c.ImageRepository) == "auto" {
+		c.ImageRepository = ""
+	}
+	return c
+}
+
+// doctorText displays the results of the checks, with advice for the problems found
+func doctorText(results []doctor.Result) {
+	for _, r := range results {
+		style := out.Check
+		switch r.Status {
+		case doctor.Warn:
+			style = out.WarningType
+		case doctor.Fail:
+			style = out.FailureType
+		}
+		if r.ID != "" {
+			out.T(style, "{{.check}}: [{{.id}}] {{.message}}", out.V{"check": r.Check, "id": r.ID, "message": r.Message})
+		} else {
+			out.T(style, "{{.check}}: {{.message}}", out.V{"check": r.Check, "message": r.Message})
+		}
+		if r.Advice != "" {
+			out.T(out.Tip, "Suggestion: {{.advice}}", out.V{"advice": r.Advice})
+		}
+		if r.URL != "" {
+			out.T(out.Documentation, "Documentation: {{.url}}", out.V{"url": r.URL})
+		}
+	}
+}
+
+// doctorJSON writes the results of the checks as a JSON list
+func doctorJSON(results []doctor.Result, w io.Writer) error {
+	if results == nil {
+		results = []doctor.Result{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "text", "Format to print the results in. One of: text, json")
+	doctorCmd.Flags().StringVar(&doctorDriver, "vm-driver", "", "The driver to check. Defaults to the driver of the existing profile, or that minikube start would use.")
+	doctorCmd.Flags().StringVar(&doctorRuntime, containerRuntime, "", "The container runtime to check. Defaults to the runtime of the existing profile, or that minikube start would use.")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/doctor"
+)
+
+func TestDoctorJSON(t *testing.T) {
+	results := []doctor.Result{
+		{Check: "driver", Status: doctor.Pass, Message: "The kvm2 driver is supported"},
+		{Check: "kvm", Status: doctor.Fail, Message: "/dev/kvm does not exist", ID: "KVM_UNAVAILABLE", Advice: "Load the kvm module"},
+	}
+	var b bytes.Buffer
+	if err := doctorJSON(results, &b); err != nil {
+		t.Fatalf("doctorJSON: %v", err)
+	}
+	var got []map[string]string
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, b.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d results, want 2:\n%s", len(got), b.String())
+	}
+	if got[0]["status"] != "pass" || got[0]["id"] != "" {
+		t.Errorf("passing result = %v, want status pass and no id", got[0])
+	}
+	if got[1]["status"] != "fail" || got[1]["id"] != "KVM_UNAVAILABLE" || got[1]["advice"] != "Load the kvm module" {
+		t.Errorf("failing result = %v", got[1])
+	}
+
+	b.Reset()
+	if err := doctorJSON(nil, &b); err != nil {
+		t.Fatalf("doctorJSON: %v", err)
+	}
+	if got := b.String(); got != "[]\n" {
+		t.Errorf("doctorJSON(nil) = %q, want an empty list", got)
+	}
+}
@@ -22,6 +22,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -43,6 +44,11 @@ import (
 const (
 	envTmpl = `{{ .Prefix }}DOCKER_TLS_VERIFY{{ .Delimiter }}{{ .DockerTLSVerify }}{{ .Suffix }}{{ .Prefix }}DOCKER_HOST{{ .Delimiter }}{{ .DockerHost }}{{ .Suffix }}{{ .Prefix }}DOCKER_CERT_PATH{{ .Delimiter }}{{ .DockerCertPath }}{{ .Suffix }}{{ if .NoProxyVar }}{{ .Prefix }}{{ .NoProxyVar }}{{ .Delimiter }}{{ .NoProxyValue }}{{ .Suffix }}{{end}}{{ .UsageHint }}`
 
+	podmanEnvTmpl = `{{ .Prefix }}PODMAN_VARLINK_BRIDGE{{ .Delimiter }}{{ .PodmanVarlinkBridge }}{{ .Suffix }}{{ .UsageHint }}`
+
+	// podmanVarlinkSocket is where io.podman.socket listens within the VM
+	podmanVarlinkSocket = "/run/podman/io.podman"
+
 	fishSetPfx   = "set -gx "
 	fishSetSfx   = "\";\n"
 	fishSetDelim = " \""
@@ -88,35 +94,37 @@ const (
 	noneDelim = "="
 )
 
+// usageHintMap has the usage hint of each shell, formatted with the name of the env command
 var usageHintMap = map[string]string{
 	"bash": `# Run this command to configure your shell:
-# eval $(minikube docker-env)
+# eval $(minikube %[1]s)
 `,
 	"fish": `# Run this command to configure your shell:
-# eval (minikube docker-env)
+# eval (minikube %[1]s)
 `,
 	"powershell": `# Run this command to configure your shell:
-# & minikube docker-env | Invoke-Expression
+# & minikube %[1]s | Invoke-Expression
 `,
 	"cmd": `REM Run this command to configure your shell:
-REM @FOR /f "tokens=*" %i IN ('minikube docker-env') DO @%i
+REM @FOR /f "tokens=*" %%i IN ('minikube %[1]s') DO @%%i
 `,
 	"emacs": `;; Run this command to configure your shell:
-;; (with-temp-buffer (shell-command "minikube docker-env" (current-buffer)) (eval-buffer))
+;; (with-temp-buffer (shell-command "minikube %[1]s" (current-buffer)) (eval-buffer))
 `,
 }
 
 // ShellConfig represents the shell config
 type ShellConfig struct {
-	Prefix          string
-	Delimiter       string
-	Suffix          string
-	DockerCertPath  string
-	DockerHost      string
-	DockerTLSVerify string
-	UsageHint       string
-	NoProxyVar      string
-	NoProxyValue    string
+	Prefix              string
+	Delimiter           string
+	Suffix              string
+	DockerCertPath      string
+	DockerHost          string
+	DockerTLSVerify     string
+	PodmanVarlinkBridge string
+	UsageHint           string
+	NoProxyVar          string
+	NoProxyValue        string
 }
 
 var (
@@ -143,12 +151,12 @@ type NoProxyGetter interface {
 // EnvNoProxyGetter gets the no_proxy variable, using environment
 type EnvNoProxyGetter struct{}
 
-func generateUsageHint(userShell string) string {
+func generateUsageHint(userShell string, envCmd string) string {
 	hint, ok := usageHintMap[userShell]
 	if !ok {
-		return usageHintMap["bash"]
+		hint = usageHintMap["bash"]
 	}
-	return hint
+	return fmt.Sprintf(hint, envCmd)
 }
 
 func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
@@ -167,7 +175,7 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		DockerCertPath:  envMap["DOCKER_CERT_PATH"],
 		DockerHost:      envMap["DOCKER_HOST"],
 		DockerTLSVerify: envMap["DOCKER_TLS_VERIFY"],
-		UsageHint:       generateUsageHint(userShell),
+		UsageHint:       generateUsageHint(userShell, "docker-env"),
 	}
 
 	if noProxy {
@@ -197,6 +205,12 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		shellCfg.NoProxyValue = noProxyValue
 	}
 
+	setShellSyntax(shellCfg, userShell)
+	return shellCfg, nil
+}
+
+// setShellSyntax configures a shell config to set variables in a shell
+func setShellSyntax(shellCfg *ShellConfig, userShell string) {
 	switch userShell {
 	case "fish":
 		shellCfg.Prefix = fishSetPfx
@@ -224,8 +238,6 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		shellCfg.Suffix = bashSetSfx
 		shellCfg.Delimiter = bashSetDelim
 	}
-
-	return shellCfg, nil
 }
 
 func shellCfgUnset() (*ShellConfig, error) {
@@ -236,13 +248,19 @@ func shellCfgUnset() (*ShellConfig, error) {
 	}
 
 	shellCfg := &ShellConfig{
-		UsageHint: generateUsageHint(userShell),
+		UsageHint: generateUsageHint(userShell, "docker-env"),
 	}
 
 	if noProxy {
 		shellCfg.NoProxyVar, shellCfg.NoProxyValue = defaultNoProxyGetter.GetNoProxyVar()
 	}
 
+	unsetShellSyntax(shellCfg, userShell)
+	return shellCfg, nil
+}
+
+// unsetShellSyntax configures a shell config to unset variables in a shell
+func unsetShellSyntax(shellCfg *ShellConfig, userShell string) {
 	switch userShell {
 	case "fish":
 		shellCfg.Prefix = fishUnsetPfx
@@ -270,12 +288,70 @@ func shellCfgUnset() (*ShellConfig, error) {
 		shellCfg.Suffix = bashUnsetSfx
 		shellCfg.Delimiter = bashUnsetDelim
 	}
+}
+
+func podmanShellCfgSet(h *host.Host) (*ShellConfig, error) {
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+	bridge, err := podmanVarlinkBridge(h)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &ShellConfig{
+		PodmanVarlinkBridge: bridge,
+		UsageHint:           generateUsageHint(userShell, "podman-env"),
+	}
+	setShellSyntax(shellCfg, userShell)
+	return shellCfg, nil
+}
 
+func podmanShellCfgUnset() (*ShellConfig, error) {
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &ShellConfig{
+		UsageHint: generateUsageHint(userShell, "podman-env"),
+	}
+	unsetShellSyntax(shellCfg, userShell)
 	return shellCfg, nil
 }
 
-func executeTemplateStdout(shellCfg *ShellConfig) error {
-	tmpl := template.Must(template.New("envConfig").Parse(envTmpl))
+// podmanVarlinkBridge returns the command which the podman remote client runs to reach the varlink socket within the VM
+func podmanVarlinkBridge(h *host.Host) (string, error) {
+	hostname, err := h.Driver.GetSSHHostname()
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting ssh hostname")
+	}
+	port, err := h.Driver.GetSSHPort()
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting ssh port")
+	}
+	args := []string{
+		"ssh", "-F", "/dev/null",
+		"-o", "ConnectionAttempts=3",
+		"-o", "ConnectTimeout=10",
+		"-o", "ControlMaster=no",
+		"-o", "ControlPath=none",
+		"-o", "LogLevel=quiet",
+		"-o", "PasswordAuthentication=no",
+		"-o", "ServerAliveInterval=60",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-i", h.Driver.GetSSHKeyPath(),
+		"-p", strconv.Itoa(port),
+		fmt.Sprintf("%s@%s", h.Driver.GetSSHUsername(), hostname),
+		"--", "sudo", "socat", "-", "unix-connect:" + podmanVarlinkSocket,
+	}
+	return strings.Join(args, " "), nil
+}
+
+func executeTemplateStdout(text string, shellCfg *ShellConfig) error {
+	tmpl := template.Must(template.New("envConfig").Parse(text))
 	return tmpl.Execute(os.Stdout, shellCfg)
 }
 
@@ -319,6 +395,15 @@ func runSSHCommand(h *host.Host, command string) (string, error) {
 	return runSSHCommandFromDriver(h.Driver, command)
 }
 
+// GetPodmanActive checks if the podman varlink socket is active
+func GetPodmanActive(host *host.Host) (bool, error) {
+	statusCmd := `sudo systemctl is-active io.podman.socket`
+	status, err := runSSHCommand(host, statusCmd)
+	// systemd returns error code on inactive
+	s := strings.TrimSpace(status)
+	return err == nil && s == "active", nil
+}
+
 // GetDockerActive checks if Docker is active
 func GetDockerActive(host *host.Host) (bool, error) {
 	statusCmd := `sudo systemctl is-active docker`
@@ -346,7 +431,7 @@ var dockerEnvCmd = &cobra.Command{
 		if host.Driver.DriverName() == constants.DriverNone {
 			exit.UsageT(`'none' driver does not support 'minikube docker-env' command`)
 		}
-		hostSt, err := cluster.GetHostStatus(api)
+		hostSt, err := cluster.GetHostStatus(api, config.GetMachineName())
 		if err != nil {
 			exit.WithError("Error getting host status", err)
 		}
@@ -375,7 +460,61 @@ var dockerEnvCmd = &cobra.Command{
 			}
 		}
 
-		if err := executeTemplateStdout(shellCfg); err != nil {
+		if err := executeTemplateStdout(envTmpl, shellCfg); err != nil {
+			exit.WithError("Error executing template", err)
+		}
+	},
+}
+
+// podmanEnvCmd represents the podman-env command
+var podmanEnvCmd = &cobra.Command{
+	Use:   "podman-env",
+	Short: "Sets up podman env variables; similar to '$(docker-machine env)'",
+	Long: `Sets up podman env variables, so that the podman remote client on the host talks to podman within the VM.
+The connection is tunnelled through ssh to the varlink socket of podman, which is enabled with --container-runtime=podman.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		host, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		if host.Driver.DriverName() == constants.DriverNone {
+			exit.UsageT(`'none' driver does not support 'minikube podman-env' command`)
+		}
+		hostSt, err := cluster.GetHostStatus(api, config.GetMachineName())
+		if err != nil {
+			exit.WithError("Error getting host status", err)
+		}
+		if hostSt != state.Running.String() {
+			exit.WithCodeT(exit.Unavailable, `The podman host is currently not running`)
+		}
+		podman, err := GetPodmanActive(host)
+		if err != nil {
+			exit.WithError("Error getting service status", err)
+		}
+		if !podman {
+			exit.WithCodeT(exit.Unavailable, `The podman service is currently not active, start minikube with --container-runtime=podman`)
+		}
+
+		var shellCfg *ShellConfig
+
+		if unset {
+			shellCfg, err = podmanShellCfgUnset()
+			if err != nil {
+				exit.WithError("Error unsetting shell variables", err)
+			}
+		} else {
+			shellCfg, err = podmanShellCfgSet(host)
+			if err != nil {
+				exit.WithError("Error setting shell variables", err)
+			}
+		}
+
+		if err := executeTemplateStdout(podmanEnvTmpl, shellCfg); err != nil {
 			exit.WithError("Error executing template", err)
 		}
 	},
@@ -387,4 +526,6 @@ func init() {
 	dockerEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add machine IP to NO_PROXY environment variable")
 	dockerEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
 	dockerEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset variables instead of setting them")
+	podmanEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	podmanEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset variables instead of setting them")
 }
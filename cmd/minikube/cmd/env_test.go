@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/host"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
@@ -60,7 +62,7 @@ func newShellCfg(shell, prefix, suffix, delim string) *ShellConfig {
 		DockerCertPath:  localpath.MakeMiniPath("certs"),
 		DockerTLSVerify: "1",
 		DockerHost:      "tcp://127.0.0.1:2376",
-		UsageHint:       generateUsageHint(shell),
+		UsageHint:       generateUsageHint(shell, "docker-env"),
 		Prefix:          prefix,
 		Suffix:          suffix,
 		Delimiter:       delim,
@@ -142,7 +144,7 @@ func TestShellCfgSet(t *testing.T) {
 				DockerCertPath:  localpath.MakeMiniPath("certs"),
 				DockerTLSVerify: "1",
 				DockerHost:      "tcp://127.0.0.1:2376",
-				UsageHint:       usageHintMap["bash"],
+				UsageHint:       generateUsageHint("bash", "docker-env"),
 				Prefix:          bashSetPfx,
 				Suffix:          bashSetSfx,
 				Delimiter:       bashSetDelim,
@@ -161,7 +163,7 @@ func TestShellCfgSet(t *testing.T) {
 				DockerCertPath:  localpath.MakeMiniPath("certs"),
 				DockerTLSVerify: "1",
 				DockerHost:      "tcp://127.0.0.1:2376",
-				UsageHint:       usageHintMap["bash"],
+				UsageHint:       generateUsageHint("bash", "docker-env"),
 				Prefix:          bashSetPfx,
 				Suffix:          bashSetSfx,
 				Delimiter:       bashSetDelim,
@@ -180,7 +182,7 @@ func TestShellCfgSet(t *testing.T) {
 				DockerCertPath:  localpath.MakeMiniPath("certs"),
 				DockerTLSVerify: "1",
 				DockerHost:      "tcp://127.0.0.1:2376",
-				UsageHint:       usageHintMap["bash"],
+				UsageHint:       generateUsageHint("bash", "docker-env"),
 				Prefix:          bashSetPfx,
 				Suffix:          bashSetSfx,
 				Delimiter:       bashSetDelim,
@@ -199,7 +201,7 @@ func TestShellCfgSet(t *testing.T) {
 				DockerCertPath:  localpath.MakeMiniPath("certs"),
 				DockerTLSVerify: "1",
 				DockerHost:      "tcp://127.0.0.1:2376",
-				UsageHint:       usageHintMap["bash"],
+				UsageHint:       generateUsageHint("bash", "docker-env"),
 				Prefix:          bashSetPfx,
 				Suffix:          bashSetSfx,
 				Delimiter:       bashSetDelim,
@@ -218,7 +220,7 @@ func TestShellCfgSet(t *testing.T) {
 				DockerCertPath:  localpath.MakeMiniPath("certs"),
 				DockerTLSVerify: "1",
 				DockerHost:      "tcp://127.0.0.1:2376",
-				UsageHint:       usageHintMap["bash"],
+				UsageHint:       generateUsageHint("bash", "docker-env"),
 				Prefix:          bashSetPfx,
 				Suffix:          bashSetSfx,
 				Delimiter:       bashSetDelim,
@@ -263,7 +265,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    bashUnsetPfx,
 				Suffix:    bashUnsetSfx,
 				Delimiter: bashUnsetDelim,
-				UsageHint: usageHintMap["bash"],
+				UsageHint: generateUsageHint("bash", "docker-env"),
 			},
 		},
 		{
@@ -273,7 +275,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    bashUnsetPfx,
 				Suffix:    bashUnsetSfx,
 				Delimiter: bashUnsetDelim,
-				UsageHint: usageHintMap["bash"],
+				UsageHint: generateUsageHint("bash", "docker-env"),
 			},
 		},
 		{
@@ -283,7 +285,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    fishUnsetPfx,
 				Suffix:    fishUnsetSfx,
 				Delimiter: fishUnsetDelim,
-				UsageHint: usageHintMap["fish"],
+				UsageHint: generateUsageHint("fish", "docker-env"),
 			},
 		},
 		{
@@ -293,7 +295,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    psUnsetPfx,
 				Suffix:    psUnsetSfx,
 				Delimiter: psUnsetDelim,
-				UsageHint: usageHintMap["powershell"],
+				UsageHint: generateUsageHint("powershell", "docker-env"),
 			},
 		},
 		{
@@ -303,7 +305,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    cmdUnsetPfx,
 				Suffix:    cmdUnsetSfx,
 				Delimiter: cmdUnsetDelim,
-				UsageHint: usageHintMap["cmd"],
+				UsageHint: generateUsageHint("cmd", "docker-env"),
 			},
 		},
 		{
@@ -313,7 +315,7 @@ func TestShellCfgUnset(t *testing.T) {
 				Prefix:    emacsUnsetPfx,
 				Suffix:    emacsUnsetSfx,
 				Delimiter: emacsUnsetDelim,
-				UsageHint: usageHintMap["emacs"],
+				UsageHint: generateUsageHint("emacs", "docker-env"),
 			},
 		},
 	}
@@ -329,3 +331,28 @@ func TestShellCfgUnset(t *testing.T) {
 		})
 	}
 }
+
+func TestPodmanShellCfgSet(t *testing.T) {
+	h := &host.Host{
+		Name: config.GetMachineName(),
+		Driver: &tests.MockDriver{
+			BaseDriver: drivers.BaseDriver{SSHUser: "docker", SSHKeyPath: "/home/user/.minikube/machines/minikube/id_rsa"},
+			Port:       22,
+		},
+	}
+	defaultShellDetector = &FakeShellDetector{"fish"}
+	shellCfg, err := podmanShellCfgSet(h)
+	if err != nil {
+		t.Fatalf("podmanShellCfgSet: %v", err)
+	}
+	if shellCfg.Prefix != fishSetPfx || shellCfg.Suffix != fishSetSfx || shellCfg.Delimiter != fishSetDelim {
+		t.Errorf("unexpected shell syntax: %+v", shellCfg)
+	}
+	if shellCfg.UsageHint != generateUsageHint("fish", "podman-env") {
+		t.Errorf("UsageHint = %q, want %q", shellCfg.UsageHint, generateUsageHint("fish", "podman-env"))
+	}
+	want := "-i /home/user/.minikube/machines/minikube/id_rsa -p 22 docker@localhost -- sudo socat - unix-connect:/run/podman/io.podman"
+	if !strings.HasPrefix(shellCfg.PodmanVarlinkBridge, "ssh ") || !strings.HasSuffix(shellCfg.PodmanVarlinkBridge, want) {
+		t.Errorf("PodmanVarlinkBridge = %q, want it to end with %q", shellCfg.PodmanVarlinkBridge, want)
+	}
+}
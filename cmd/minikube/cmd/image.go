@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Build, load, list or remove images in the cluster's container runtime.",
+	Long: `Build, load, list or remove images in the container runtime of the cluster, so that they can be used by pods
+without pushing them to a registry. Works with the docker, cri-o and containerd runtimes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.UsageT("Usage: minikube image [command]")
+	},
+}
+
+// imageRuntime returns a command runner and the container runtime of the running cluster, or exits
+func imageRuntime(api libmachine.API) (command.Runner, cruntime.Manager) {
+	cc := loadProfileOrExit()
+	runner := runningClusterRunner(api)
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		exit.WithError("Unable to get runtime", err)
+	}
+	return runner, cr
+}
+
+func init() {
+	imageCmd.AddCommand(imageListCmd)
+	imageCmd.AddCommand(imageRemoveCmd)
+	imageCmd.AddCommand(imageLoadCmd)
+	imageCmd.AddCommand(imageBuildCmd)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var imageBuildTag string
+
+// imageBuildCmd represents the image build command
+var imageBuildCmd = &cobra.Command{
+	Use:   "build DIR",
+	Short: "Builds an image inside the cluster's container runtime.",
+	Long: `Builds an image from a directory containing a Dockerfile, inside the VM, with the builder of the container runtime:
+docker build for docker, and podman build for cri-o and containerd. With containerd, the image is imported with ctr after the build.
+The built image is immediately available to pods.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube image build -t TAG DIR")
+		}
+		if imageBuildTag == "" {
+			exit.UsageT("The --tag flag is required")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		runner, cr := imageRuntime(api)
+		out.T(out.Provisioning, "Building {{.image}} from {{.dir}} with {{.runtime}} ...", out.V{"image": imageBuildTag, "dir": args[0], "runtime": cr.Name()})
+		if err := machine.BuildImage(runner, cr, args[0], imageBuildTag); err != nil {
+			exit.WithError("Unable to build image", err)
+		}
+		out.T(out.Ready, "Built {{.image}}", out.V{"image": imageBuildTag})
+	},
+}
+
+func init() {
+	imageBuildCmd.Flags().StringVarP(&imageBuildTag, "tag", "t", "", "Name and tag of the image to build, such as example.com/app:v1")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// imageListCmd represents the image ls command
+var imageListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "Lists the images in the cluster's container runtime.",
+	Long:    "Lists the tagged images in the container runtime of the cluster, sorted by name.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("Usage: minikube image ls")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		_, cr := imageRuntime(api)
+		images, err := cr.ListImages()
+		if err != nil {
+			exit.WithError("Unable to list images", err)
+		}
+		sort.Strings(images)
+		for _, image := range images {
+			out.String("%s\n", image)
+		}
+	},
+}
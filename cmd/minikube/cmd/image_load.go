@@ -0,0 +1,52 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// imageLoadCmd represents the image load command
+var imageLoadCmd = &cobra.Command{
+	Use:   "load IMAGE|TARBALL [IMAGE|TARBALL...]",
+	Short: "Loads images into the cluster's container runtime.",
+	Long: `Loads images into the container runtime of the cluster. Each argument is either the path of an image tarball,
+such as one created by "docker save", or the name of an image, which is taken from the local docker daemon if present,
+and pulled otherwise. Unlike "minikube cache add", the images are not kept in the cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube image load IMAGE|TARBALL [IMAGE|TARBALL...]")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		runner, cr := imageRuntime(api)
+		for _, image := range args {
+			out.T(out.Copying, "Loading {{.image}} into {{.runtime}} ...", out.V{"image": image, "runtime": cr.Name()})
+			if err := machine.LoadImage(runner, cr, image); err != nil {
+				exit.WithError("Unable to load image", err)
+			}
+		}
+		out.T(out.Ready, "Loaded {{.count}} images", out.V{"count": len(args)})
+	},
+}
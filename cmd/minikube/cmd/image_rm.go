@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// imageRemoveCmd represents the image rm command
+var imageRemoveCmd = &cobra.Command{
+	Use:     "rm IMAGE [IMAGE...]",
+	Aliases: []string{"remove"},
+	Short:   "Removes images from the cluster's container runtime.",
+	Long:    "Removes images from the container runtime of the cluster. Images which are used by a container can not be removed.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("Usage: minikube image rm IMAGE [IMAGE...]")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		_, cr := imageRuntime(api)
+		for _, image := range args {
+			if err := cr.RemoveImage(image); err != nil {
+				exit.WithError("Unable to remove image", err)
+			}
+			out.T(out.Crushed, "Removed {{.image}}", out.V{"image": image})
+		}
+	},
+}
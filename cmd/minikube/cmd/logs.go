@@ -39,6 +39,8 @@ var (
 	numberOfLines int
 	// showProblems only shows lines that match known issues
 	showProblems bool
+	// logsNode is the node to show logs for, set via --node
+	logsNode string
 )
 
 // logsCmd represents the logs command
@@ -58,7 +60,8 @@ var logsCmd = &cobra.Command{
 		}
 		defer api.Close()
 
-		h, err := api.Load(config.GetMachineName())
+		machineName := selectedMachineName(logsNode)
+		h, err := api.Load(machineName)
 		if err != nil {
 			exit.WithError("api load", err)
 		}
@@ -66,7 +69,7 @@ var logsCmd = &cobra.Command{
 		if err != nil {
 			exit.WithError("command runner", err)
 		}
-		bs, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		bs, err := getNodeBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), machineName)
 		if err != nil {
 			exit.WithError("Error getting cluster bootstrapper", err)
 		}
@@ -98,4 +101,5 @@ func init() {
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 60, "Number of lines back to go within the log")
+	logsCmd.Flags().StringVar(&logsNode, nodeFlag, "", "The node to get logs from. Defaults to the control plane.")
 }
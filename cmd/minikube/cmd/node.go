@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// nodeFlag is the name of the flag used to select a node within the profile
+const nodeFlag = "node"
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Add, delete, or list the nodes of a multi-node cluster.",
+	Long:  "Add, delete, or list the nodes of a multi-node cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.UsageT("Usage: minikube node [command]")
+	},
+}
+
+// loadProfileOrExit loads the configuration of the current profile, exiting if it does not exist
+func loadProfileOrExit() *config.Config {
+	cc, err := config.Load()
+	if err != nil {
+		if os.IsNotExist(err) {
+			exit.WithCodeT(exit.Data, `The "{{.name}}" profile does not exist. Run "minikube start -p {{.name}}" to create it.`, out.V{"name": config.GetMachineName()})
+		}
+		exit.WithError("Error getting config", err)
+	}
+	return cc
+}
+
+// selectNode returns the named worker node, or nil if the name refers to the control plane.
+// An empty name, or the name of the profile itself, selects the control plane.
+func selectNode(cc *config.Config, name string) *config.Node {
+	if name == "" || name == config.GetMachineName() {
+		return nil
+	}
+	i, ok := cc.FindNode(name)
+	if !ok {
+		exit.WithCodeT(exit.Data, `Node "{{.node}}" does not exist in profile "{{.profile}}". Run "minikube node list" to see the available nodes.`, out.V{"node": name, "profile": config.GetMachineName()})
+	}
+	return &cc.Nodes[i]
+}
+
+// selectedMachineName returns the libmachine host name of the node selected using --node
+func selectedMachineName(name string) string {
+	if name == "" {
+		return config.GetMachineName()
+	}
+	if n := selectNode(loadProfileOrExit(), name); n != nil {
+		return n.Name
+	}
+	return config.GetMachineName()
+}
+
+// joinNode starts the machine for a worker node, and joins it to the control plane of the profile.
+// The IP address of the node is updated in place.
+func joinNode(api libmachine.API, cc *config.Config, n *config.Node) error {
+	return startNode(api, cc, n, false)
+}
+
+// startNode starts the machine for a worker node. A node which is already registered with the control plane
+// only has its kubelet restarted, otherwise it is joined to the cluster. The IP address of the node is updated in place.
+func startNode(api libmachine.API, cc *config.Config, n *config.Node, registered bool) error {
+	h, err := cluster.StartHost(api, cc.NodeMachineConfig(*n))
+	if err != nil {
+		return errors.Wrap(err, "start host")
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "ip")
+	}
+	n.IP = ip
+	n.KubernetesVersion = cc.KubernetesConfig.KubernetesVersion
+
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	creds, err := config.LoadRegistryCredentials(config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner, Mirror: startRegistryMirror(h, cc.MachineConfig), Credentials: creds})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	if err := cr.Enable(true); err != nil {
+		return errors.Wrap(err, "enable runtime")
+	}
+
+	bsName := viper.GetString(cmdcfg.Bootstrapper)
+	bs, err := getNodeBootstrapper(api, bsName, n.Name)
+	if err != nil {
+		return errors.Wrap(err, "node bootstrapper")
+	}
+	if registered {
+		err := bs.RestartNode(cc.KubernetesConfig, *n)
+		if err == nil {
+			return nil
+		}
+		glog.Warningf("unable to restart node %s, joining it again: %v", n.Name, err)
+	}
+
+	cp, err := getClusterBootstrapper(api, bsName)
+	if err != nil {
+		return errors.Wrap(err, "control plane bootstrapper")
+	}
+	token, err := cp.GenerateToken(cc.KubernetesConfig)
+	if err != nil {
+		return errors.Wrap(err, "generate token")
+	}
+	out.T(out.Launch, `Joining "{{.name}}" to the cluster ...`, out.V{"name": n.Name})
+	return bs.JoinCluster(cc.KubernetesConfig, *n, token)
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeCmd.AddCommand(nodeDeleteCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// nodeAddCmd represents the node add command
+var nodeAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds a worker node to the cluster.",
+	Long:  "Creates a new machine using the driver of the profile, and joins it to the cluster as a worker node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("Usage: minikube node add")
+		}
+		profile := config.GetMachineName()
+		cc := loadProfileOrExit()
+		if cc.MachineConfig.VMDriver == constants.DriverNone {
+			exit.UsageT("The 'none' driver does not support multi-node clusters")
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		st, err := cluster.GetHostStatus(api, profile)
+		if err != nil {
+			exit.WithError("Error getting host status", err)
+		}
+		if st != state.Running.String() {
+			exit.WithCodeT(exit.Unavailable, `The control plane for "{{.profile}}" is not running. Run "minikube start -p {{.profile}}" first.`, out.V{"profile": profile})
+		}
+
+		// Record the node before creating it, so that a partially created node may be removed using "node delete"
+		cc.Nodes = append(cc.Nodes, config.Node{Name: cc.NextNodeName(profile)})
+		n := &cc.Nodes[len(cc.Nodes)-1]
+		if err := saveConfig(cc); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+
+		out.T(out.Happy, `Adding node "{{.name}}" to cluster "{{.profile}}"`, out.V{"name": n.Name, "profile": profile})
+		if err := joinNode(api, cc, n); err != nil {
+			exit.WithError("Failed to add node", err)
+		}
+		if err := saveConfig(cc); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+		out.T(out.Ready, `Node "{{.name}}" has joined cluster "{{.profile}}"`, out.V{"name": n.Name, "profile": profile})
+	},
+}
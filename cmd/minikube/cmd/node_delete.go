@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/mcnerror"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// nodeDeleteCmd represents the node delete command
+var nodeDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a worker node from the cluster.",
+	Long:  "Removes a worker node from Kubernetes, and deletes its machine.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube node delete NAME")
+		}
+		cc := loadProfileOrExit()
+		n := selectNode(cc, args[0])
+		if n == nil {
+			exit.UsageT("The control plane cannot be deleted on its own. To delete the whole cluster, run: minikube delete")
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		deleteNode(api, cc, n.Name)
+		out.T(out.Crushed, `Node "{{.name}}" has been deleted.`, out.V{"name": args[0]})
+	},
+}
+
+// deleteNode removes a worker node from Kubernetes and the profile config, and deletes its machine
+func deleteNode(api libmachine.API, cc *config.Config, name string) {
+	client, err := kapi.Client(config.GetMachineName())
+	if err == nil {
+		err = client.CoreV1().Nodes().Delete(name, &metav1.DeleteOptions{})
+	}
+	if err != nil {
+		glog.Warningf("unable to remove node %s from Kubernetes: %v", name, err)
+	}
+
+	deleteNodeMachine(api, name)
+
+	cc.RemoveNode(name)
+	if err := saveConfig(cc); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+}
+
+// deleteNodeMachine deletes the machine of a node, including any files left behind
+func deleteNodeMachine(api libmachine.API, name string) {
+	if err := cluster.DeleteHost(api, name); err != nil {
+		switch errors.Cause(err).(type) {
+		case mcnerror.ErrHostDoesNotExist:
+			glog.Infof("%s machine does not exist", name)
+		default:
+			out.T(out.FailureType, "Failed to delete node: {{.error}}", out.V{"error": err})
+			out.T(out.Notice, `You may need to manually remove the "{{.name}}" VM from your hypervisor`, out.V{"name": name})
+		}
+	}
+
+	machineDir := filepath.Join(localpath.MiniPath(), "machines", name)
+	if _, err := os.Stat(machineDir); err == nil {
+		if err := os.RemoveAll(machineDir); err != nil {
+			exit.WithError("Unable to remove machine directory", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+)
+
+// nodeListCmd represents the node list command
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes of the cluster.",
+	Long:  "Lists the control plane and worker nodes of the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("Usage: minikube node list")
+		}
+		nodeTable(config.GetMachineName(), loadProfileOrExit(), os.Stdout)
+	},
+}
+
+// nodeTable renders the nodes of a profile as a table
+func nodeTable(profile string, cc *config.Config, w io.Writer) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Name", "IP", "Role", "Kubernetes Version"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+
+	table.Append([]string{profile, cc.KubernetesConfig.NodeIP, "control-plane", cc.KubernetesConfig.KubernetesVersion})
+	for _, n := range cc.Nodes {
+		table.Append([]string{n.Name, n.IP, "worker", n.KubernetesVersion})
+	}
+	table.Render()
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNodeTable(t *testing.T) {
+	cc := &config.Config{
+		KubernetesConfig: config.KubernetesConfig{NodeIP: "192.168.99.100", KubernetesVersion: "v1.16.0"},
+		Nodes: []config.Node{
+			{Name: "p1-m02", IP: "192.168.99.101", KubernetesVersion: "v1.16.0"},
+			{Name: "p1-m03", IP: "192.168.99.102", KubernetesVersion: "v1.16.0"},
+		},
+	}
+
+	var b bytes.Buffer
+	nodeTable("p1", cc, &b)
+
+	want := [][]string{
+		{"p1", "192.168.99.100", "control-plane"},
+		{"p1-m02", "192.168.99.101", "worker"},
+		{"p1-m03", "192.168.99.102", "worker"},
+	}
+	lines := strings.Split(b.String(), "\n")
+	for _, fields := range want {
+		found := false
+		for _, l := range lines {
+			if strings.Contains(l, " "+fields[0]+" ") && strings.Contains(l, fields[1]) && strings.Contains(l, fields[2]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no row for %v in:\n%s", fields, b.String())
+		}
+	}
+}
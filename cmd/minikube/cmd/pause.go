@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pauses a running local kubernetes cluster",
+	Long: `Pauses the Kubernetes cluster without stopping the VM. The kubelet is stopped, and every
+Kubernetes container is frozen, freeing CPU while keeping all state in memory. Use "unpause" to resume.`,
+	Run: runPause,
+}
+
+// runPause handles the executes the flow of "minikube pause"
+func runPause(cmd *cobra.Command, args []string) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error getting config", err)
+	}
+	cr, bs := runningClusterRuntime(api, cc)
+
+	out.T(out.Pause, "Pausing Kubernetes in {{.name}} ...", out.V{"name": config.GetMachineName()})
+	ids, err := cluster.Pause(cr, bs)
+	if err != nil {
+		exit.WithError("Pause", err)
+	}
+	out.T(out.Pause, "Paused {{.count}} containers", out.V{"count": len(ids)})
+}
+
+// runningClusterRuntime returns the container runtime and bootstrapper of the running cluster, or exits
+func runningClusterRuntime(api libmachine.API, cc *config.Config) (cruntime.Manager, bootstrapper.Bootstrapper) {
+	runner := runningClusterRunner(api)
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		exit.WithError("Unable to get runtime", err)
+	}
+	bs, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		exit.WithError("Error getting cluster bootstrapper", err)
+	}
+	return cr, bs
+}
+
+// runningClusterRunner returns a command runner for the VM of the running cluster, or exits
+func runningClusterRunner(api libmachine.API) command.Runner {
+	return runningMachineRunner(api, config.GetMachineName())
+}
+
+// runningMachineRunner returns a command runner for a running machine, such as the VM of a node, or exits
+func runningMachineRunner(api libmachine.API, machineName string) command.Runner {
+	h, err := api.Load(machineName)
+	if err != nil {
+		exit.WithError("api load", err)
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		exit.WithError("Error getting machine status", err)
+	}
+	if s != state.Running {
+		exit.WithCodeT(exit.Unavailable, `"{{.name}}" is not running`, out.V{"name": machineName})
+	}
+
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.WithError("command runner", err)
+	}
+	return runner
+}
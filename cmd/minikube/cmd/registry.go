@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// registryCmd represents the registry command
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the credentials used to pull images from private registries.",
+	Long: `Manage the credentials used by the cluster to pull images from private registries.
+Credentials are stored per profile, and applied to the container runtime whenever the cluster is started.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.UsageT("Usage: minikube registry [command]")
+	},
+}
+
+// applyRegistryCredentials configures the container runtime of each running node of the cluster with the stored credentials
+func applyRegistryCredentials(creds map[string]config.RegistryCredential) error {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+
+	st, err := cluster.GetHostStatus(api, config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "host status")
+	}
+	if st != state.Running.String() {
+		out.T(out.Notice, "The credentials will be applied the next time the cluster is started")
+		return nil
+	}
+
+	cc, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, "loading profile")
+	}
+	if err := applyMachineCredentials(api, config.GetMachineName(), cc.KubernetesConfig.ContainerRuntime, creds); err != nil {
+		return err
+	}
+	for _, n := range cc.Nodes {
+		st, err := cluster.GetHostStatus(api, n.Name)
+		if err != nil {
+			return errors.Wrapf(err, "host status of %s", n.Name)
+		}
+		if st != state.Running.String() {
+			out.T(out.Notice, `The credentials will be applied to node "{{.node}}" the next time it is started`, out.V{"node": n.Name})
+			continue
+		}
+		if err := applyMachineCredentials(api, n.Name, cc.KubernetesConfig.ContainerRuntime, creds); err != nil {
+			return errors.Wrapf(err, "node %s", n.Name)
+		}
+	}
+	return nil
+}
+
+// applyMachineCredentials configures the container runtime of a single machine with the stored credentials
+func applyMachineCredentials(api libmachine.API, name string, runtime string, creds map[string]config.RegistryCredential) error {
+	host, err := cluster.CheckIfHostExistsAndLoad(api, name)
+	if err != nil {
+		return errors.Wrap(err, "getting host")
+	}
+	runner, err := machine.CommandRunner(host)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: runtime, Runner: runner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	return cr.UpdateCredentials(creds)
+}
+
+func init() {
+	registryCmd.AddCommand(registryLoginCmd)
+	registryCmd.AddCommand(registryLogoutCmd)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	registryUsername      string
+	registryPasswordStdin bool
+	pullSecretNamespaces  []string
+)
+
+// registryLoginCmd represents the registry login command
+var registryLoginCmd = &cobra.Command{
+	Use:   "login HOST",
+	Short: "Stores the credentials of a private registry for the current profile.",
+	Long: `Stores the credentials of a private registry for the current profile, and configures the container runtime of
+the cluster to use them when pulling images. A matching imagePullSecret may also be created in chosen namespaces.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube registry login HOST")
+		}
+		host := args[0]
+
+		if registryUsername == "" {
+			registryUsername = cmdcfg.AskForStaticValue("-- Enter username: ")
+		}
+		var password string
+		if registryPasswordStdin {
+			data, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				exit.WithError("Unable to read password", err)
+			}
+			password = strings.TrimRight(string(data), "\r\n")
+		} else {
+			password = cmdcfg.AskForPasswordValue("-- Enter password: ")
+		}
+		if password == "" {
+			exit.UsageT("The password may not be empty")
+		}
+
+		profile := config.GetMachineName()
+		creds, err := config.LoadRegistryCredentials(profile)
+		if err != nil {
+			exit.WithError("Unable to load registry credentials", err)
+		}
+		if creds == nil {
+			creds = map[string]config.RegistryCredential{}
+		}
+		cred := config.RegistryCredential{Username: registryUsername, Password: password}
+		creds[host] = cred
+		if err := config.SaveRegistryCredentials(profile, creds); err != nil {
+			exit.WithError("Unable to save registry credentials", err)
+		}
+		out.T(out.Check, "Stored the credentials of {{.host}} for profile {{.profile}}", out.V{"host": host, "profile": profile})
+
+		if err := applyRegistryCredentials(creds); err != nil {
+			exit.WithError("Unable to apply registry credentials", err)
+		}
+
+		if len(pullSecretNamespaces) == 0 {
+			return
+		}
+		data, err := cruntime.DockerConfigJSON(map[string]config.RegistryCredential{host: cred})
+		if err != nil {
+			exit.WithError("Unable to generate pull secret", err)
+		}
+		name := pullSecretName(host)
+		for _, ns := range pullSecretNamespaces {
+			if err := service.CreateSecretOfType(ns, name, core.SecretTypeDockerConfigJson, map[string]string{core.DockerConfigJsonKey: string(data)}, map[string]string{"app": "minikube-registry-login"}); err != nil {
+				exit.WithError("Unable to create pull secret", err)
+			}
+			out.T(out.Check, "Created imagePullSecret {{.name}} in namespace {{.namespace}}", out.V{"name": name, "namespace": ns})
+		}
+	},
+}
+
+// pullSecretName returns the name of the imagePullSecret created for a registry host
+func pullSecretName(host string) string {
+	return "registry-" + strings.NewReplacer(".", "-", ":", "-").Replace(strings.ToLower(host))
+}
+
+func init() {
+	registryLoginCmd.Flags().StringVarP(&registryUsername, "username", "u", "", "The username of the registry. Prompted for if not set.")
+	registryLoginCmd.Flags().BoolVar(&registryPasswordStdin, "password-stdin", false, "Read the password from stdin, instead of prompting for it.")
+	registryLoginCmd.Flags().StringSliceVar(&pullSecretNamespaces, "pull-secret-namespaces", nil, "Namespaces in which to create an imagePullSecret with the credentials, named registry-<host>.")
+}
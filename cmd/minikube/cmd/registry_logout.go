@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// registryLogoutCmd represents the registry logout command
+var registryLogoutCmd = &cobra.Command{
+	Use:   "logout HOST",
+	Short: "Removes the credentials of a private registry from the current profile.",
+	Long: `Removes the credentials of a private registry from the current profile, and from the container runtime of the cluster.
+imagePullSecrets created by "minikube registry login" are left in place.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube registry logout HOST")
+		}
+		host := args[0]
+
+		profile := config.GetMachineName()
+		creds, err := config.LoadRegistryCredentials(profile)
+		if err != nil {
+			exit.WithError("Unable to load registry credentials", err)
+		}
+		if _, ok := creds[host]; !ok {
+			exit.WithCodeT(exit.Data, "No credentials are stored for {{.host}}", out.V{"host": host})
+		}
+		delete(creds, host)
+		if err := config.SaveRegistryCredentials(profile, creds); err != nil {
+			exit.WithError("Unable to save registry credentials", err)
+		}
+		if err := applyRegistryCredentials(creds); err != nil {
+			exit.WithError("Unable to apply registry credentials", err)
+		}
+		out.T(out.Crushed, "Removed the credentials of {{.host}}", out.V{"host": host})
+	},
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mirror"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util/lock"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// registryMirrorListen is the address the registry mirror listens on, which is the address of the host as seen from the VMs
+var registryMirrorListen string
+
+// registryMirrorCmd represents the registry-mirror command, which is started in the background by "minikube start"
+var registryMirrorCmd = &cobra.Command{
+	Use:    "registry-mirror",
+	Short:  "Runs a pull-through registry mirror of Docker Hub, caching images on the host",
+	Long:   "Runs a pull-through registry mirror of Docker Hub, caching images on the host. It is started in the background by \"minikube start --registry-mirror-cache\".",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if net.ParseIP(registryMirrorListen) == nil {
+			exit.UsageT("Usage: minikube registry-mirror --listen IP")
+		}
+		s, err := mirror.NewServer(localpath.MakeMiniPath("cache", "registry"), mirror.DockerHub)
+		if err != nil {
+			exit.WithError("Unable to create the registry mirror", err)
+		}
+		// Prefer the usual port, so that the configuration of existing VMs remains valid
+		l, err := net.Listen("tcp", net.JoinHostPort(registryMirrorListen, strconv.Itoa(constants.RegistryMirrorPort)))
+		if err != nil {
+			glog.Warningf("port %d is unavailable, using another port: %v", constants.RegistryMirrorPort, err)
+			l, err = net.Listen("tcp", net.JoinHostPort(registryMirrorListen, "0"))
+			if err != nil {
+				exit.WithError("Unable to listen for the registry mirror", err)
+			}
+		}
+		addr := l.Addr().String()
+		state := fmt.Sprintf("%d\n%s", os.Getpid(), addr)
+		if err := lock.WriteFile(registryMirrorProcessFile(registryMirrorListen), []byte(state), 0644); err != nil {
+			exit.WithError("Unable to record the registry mirror process", err)
+		}
+		glog.Infof("Serving a registry mirror of %s on %s", mirror.DockerHub, addr)
+		if err := http.Serve(l, s); err != nil {
+			exit.WithError("Registry mirror failed", err)
+		}
+	},
+}
+
+// startRegistryMirror starts the registry mirror of the host if the profile uses it, and returns its URL as seen from the VM.
+// An empty URL is returned if the mirror can not be used, in which case images are pulled directly.
+func startRegistryMirror(h *host.Host, mc config.MachineConfig) string {
+	if !mc.RegistryMirrorCache {
+		return ""
+	}
+	if mc.VMDriver == constants.DriverNone {
+		out.WarningT("The registry mirror cache is not supported by the none driver, as images are already stored on the host")
+		return ""
+	}
+	if len(mc.RegistryMirror) > 0 && (mc.ContainerRuntime == "" || mc.ContainerRuntime == "docker") {
+		out.WarningT("The registry mirror cache is not used by docker, as --registry-mirror is set")
+		return ""
+	}
+	ip, err := cluster.GetVMHostIP(h)
+	if err != nil {
+		out.WarningT("Unable to determine the host address for the registry mirror cache: {{.error}}", out.V{"error": err})
+		return ""
+	}
+	addr, err := ensureRegistryMirror(ip.String())
+	if err != nil {
+		out.WarningT("Unable to start the registry mirror cache: {{.error}}", out.V{"error": err})
+		return ""
+	}
+	return fmt.Sprintf("http://%s", addr)
+}
+
+// registryMirrorProcessFile returns the path of the file recording the pid and address of the registry mirror listening on ip
+func registryMirrorProcessFile(ip string) string {
+	return localpath.MakeMiniPath(fmt.Sprintf("%s-%s", constants.RegistryMirrorProcessFileName, ip))
+}
+
+// registryMirrorAddr returns the address recorded in a registry mirror process file, or "" if there is none
+func registryMirrorAddr(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(lines[1])
+}
+
+// ensureRegistryMirror starts the registry mirror listening on ip in the background, unless it is already running,
+// and returns the address it listens on. Each host address the VMs use gets its own mirror, sharing the same cache.
+func ensureRegistryMirror(ip string) (string, error) {
+	pidPath := registryMirrorProcessFile(ip)
+	if addr := registryMirrorAddr(pidPath); addr != "" && mirror.Running(addr) {
+		glog.Infof("registry mirror is already running on %s", addr)
+		return addr, nil
+	}
+	if err := os.Remove(pidPath); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	out.T(out.Caching, "Starting the registry mirror cache on {{.ip}} ...", out.V{"ip": ip})
+	mirrorCmd := exec.Command(os.Args[0], "registry-mirror", "--listen", ip)
+	mirrorCmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	if err := mirrorCmd.Start(); err != nil {
+		return "", err
+	}
+	var addr string
+	err := retry.Expo(func() error {
+		addr = registryMirrorAddr(pidPath)
+		if addr == "" || !mirror.Running(addr) {
+			return fmt.Errorf("registry mirror is not listening on %s", ip)
+		}
+		return nil
+	}, 100*time.Millisecond, 10*time.Second)
+	return addr, err
+}
+
+// stopRegistryMirrors stops the registry mirrors of the host, unless a running profile other than profile still uses them
+func stopRegistryMirrors(api libmachine.API, profile string) {
+	files, err := filepath.Glob(registryMirrorProcessFile("*"))
+	if err != nil || len(files) == 0 {
+		return
+	}
+	profiles, _, err := config.ListProfiles()
+	if err != nil && !os.IsNotExist(err) {
+		glog.Warningf("unable to list profiles, leaving the registry mirror running: %v", err)
+		return
+	}
+	for _, p := range profiles {
+		if p.Name == profile || p.Config == nil || !p.Config.MachineConfig.RegistryMirrorCache {
+			continue
+		}
+		st, err := cluster.GetHostStatus(api, p.Name)
+		if err != nil || st == state.Running.String() {
+			glog.Infof("the registry mirror is still used by %q, leaving it running", p.Name)
+			return
+		}
+	}
+	for _, f := range files {
+		if err := killProcess(f); err != nil {
+			out.WarningT("Unable to stop the registry mirror cache: {{.error}}", out.V{"error": err})
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			glog.Warningf("unable to remove %s: %v", f, err)
+		}
+	}
+}
+
+func init() {
+	registryMirrorCmd.Flags().StringVar(&registryMirrorListen, "listen", "", "The IP address to listen on")
+	RootCmd.AddCommand(registryMirrorCmd)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/kubectl/pkg/util/templates"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -70,6 +71,10 @@ var RootCmd = &cobra.Command{
 			}
 		}
 
+		if err := assets.RegisterUserAddons(); err != nil {
+			glog.Warningf("unable to register user-defined addons: %v", err)
+		}
+
 		logDir := pflag.Lookup("log_dir")
 		if !logDir.Changed {
 			if err := logDir.Value.Set(localpath.MakeMiniPath("logs")); err != nil {
@@ -170,6 +175,8 @@ func init() {
 				startCmd,
 				statusCmd,
 				stopCmd,
+				pauseCmd,
+				unpauseCmd,
 				deleteCmd,
 				dashboardCmd,
 			},
@@ -178,7 +185,9 @@ func init() {
 			Message: translate.T("Images Commands:"),
 			Commands: []*cobra.Command{
 				dockerEnvCmd,
+				podmanEnvCmd,
 				cacheCmd,
+				imageCmd,
 			},
 		},
 		{
@@ -187,6 +196,9 @@ func init() {
 				configCmd.AddonsCmd,
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
+				nodeCmd,
+				registryCmd,
+				snapshotCmd,
 				updateContextCmd,
 			},
 		},
@@ -202,6 +214,7 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				cpCmd,
 				kubectlCmd,
 			},
 		},
@@ -211,6 +224,7 @@ func init() {
 				sshKeyCmd,
 				ipCmd,
 				logsCmd,
+				doctorCmd,
 				updateCheckCmd,
 				versionCmd,
 			},
@@ -262,11 +276,16 @@ func setupViper() {
 
 // getClusterBootstrapper returns a new bootstrapper for the cluster
 func getClusterBootstrapper(api libmachine.API, bootstrapperName string) (bootstrapper.Bootstrapper, error) {
+	return getNodeBootstrapper(api, bootstrapperName, config.GetMachineName())
+}
+
+// getNodeBootstrapper returns a new bootstrapper for the named machine of the cluster
+func getNodeBootstrapper(api libmachine.API, bootstrapperName string, machineName string) (bootstrapper.Bootstrapper, error) {
 	var b bootstrapper.Bootstrapper
 	var err error
 	switch bootstrapperName {
 	case bootstrapper.BootstrapperTypeKubeadm:
-		b, err = kubeadm.NewKubeadmBootstrapper(api)
+		b, err = kubeadm.NewKubeadmBootstrapper(api, machineName)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting kubeadm bootstrapper")
 		}
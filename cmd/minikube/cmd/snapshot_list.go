@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// snapshotListCmd represents the snapshot list command
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of the cluster.",
+	Long:  "Lists the snapshots saved for the current profile, oldest first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("Usage: minikube snapshot list")
+		}
+		profile := config.GetMachineName()
+		snapshots, err := cluster.ListSnapshots(profile)
+		if err != nil {
+			exit.WithError("Unable to list snapshots", err)
+		}
+		if len(snapshots) == 0 {
+			out.T(out.Empty, `No snapshots found for "{{.profile}}". Run "minikube snapshot save NAME" to create one.`, out.V{"profile": profile})
+			return
+		}
+		snapshotTable(snapshots, os.Stdout)
+	},
+}
+
+// snapshotTable renders snapshots as a table
+func snapshotTable(snapshots []*cluster.Snapshot, w io.Writer) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Name", "Kubernetes Version", "Created"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+
+	for _, s := range snapshots {
+		table.Append([]string{s.Name, s.KubernetesVersion, s.Created.Format(time.RFC3339)})
+	}
+	table.Render()
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// snapshotRestoreForce is whether to replace the certificate authority shared by every profile, if the snapshot has another one
+var snapshotRestoreForce bool
+
+// snapshotRestoreCmd represents the snapshot restore command
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restores the cluster state from a snapshot.",
+	Long: `Restores the cluster state from a snapshot. The control plane is stopped, the etcd data is replaced,
+and the control plane is restarted. Changes made since the snapshot was saved are lost.
+If the snapshot was taken with another certificate authority, which would invalidate the other profiles, --force is required.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube snapshot restore NAME")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		cc := loadProfileOrExit()
+		_, bs := runningClusterRuntime(api, cc)
+
+		profile := config.GetMachineName()
+		out.T(out.Restarting, `Restoring "{{.profile}}" from snapshot "{{.name}}" ...`, out.V{"name": args[0], "profile": profile})
+		if err := cluster.RestoreSnapshot(bs, cc, profile, args[0], snapshotRestoreForce); err != nil {
+			exit.WithError("Unable to restore snapshot", err)
+		}
+		out.T(out.Ready, `Restored snapshot "{{.name}}"`, out.V{"name": args[0]})
+	},
+}
+
+func init() {
+	snapshotRestoreCmd.Flags().BoolVar(&snapshotRestoreForce, "force", false, "Replace the certificate authority shared by every profile, if the snapshot was taken with another one. The current one is backed up first.")
+}
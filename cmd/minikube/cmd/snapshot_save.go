@@ -0,0 +1,53 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// snapshotSaveCmd represents the snapshot save command
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save NAME",
+	Short: "Saves a snapshot of the cluster state.",
+	Long:  "Saves a snapshot of the cluster state. An existing snapshot with the same name is replaced.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("Usage: minikube snapshot save NAME")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		cc := loadProfileOrExit()
+		_, bs := runningClusterRuntime(api, cc)
+
+		profile := config.GetMachineName()
+		out.T(out.FileDownload, `Saving snapshot "{{.name}}" of "{{.profile}}" ...`, out.V{"name": args[0], "profile": profile})
+		if _, err := cluster.SaveSnapshot(bs, cc, profile, args[0]); err != nil {
+			exit.WithError("Unable to save snapshot", err)
+		}
+		out.T(out.Ready, `Saved snapshot "{{.name}}"`, out.V{"name": args[0]})
+	},
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/url"
@@ -42,8 +43,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
@@ -110,6 +114,28 @@ const (
 	interactive           = "interactive"
 	waitTimeout           = "wait-timeout"
 	nativeSSH             = "native-ssh"
+	registryMirrorCache   = "registry-mirror-cache"
+	outputFormat          = "output"
+)
+
+// Timeouts of the steps of bootstrapCluster, after which the commands they run are killed
+const (
+	pullImagesTimeout = 10 * time.Minute
+	startTimeout      = 10 * time.Minute
+	restartTimeout    = 5 * time.Minute
+)
+
+// Steps reported by "minikube start", in order
+const (
+	stepInitialSetup       = "Initial Minikube Setup"
+	stepSelectingDriver    = "Selecting Driver"
+	stepDownloading        = "Downloading Artifacts"
+	stepStartingHost       = "Starting Host"
+	stepConfiguringRuntime = "Configuring Runtime"
+	stepPreparingK8s       = "Preparing Kubernetes"
+	stepBootstrapping      = "Bootstrapping Cluster"
+	stepWaiting            = "Waiting For Cluster"
+	stepDone               = "Done"
 )
 
 var (
@@ -153,7 +179,7 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(isoURL, constants.DefaultISOURL, "Location of the minikube iso.")
 	startCmd.Flags().Bool(keepContext, constants.DefaultKeepContext, "This will keep the existing kubectl context and will create a minikube context.")
 	startCmd.Flags().Bool(embedCerts, constants.DefaultEmbedCerts, "if true, will embed the certs in kubeconfig.")
-	startCmd.Flags().String(containerRuntime, "docker", "The container runtime to be used (docker, crio, containerd).")
+	startCmd.Flags().String(containerRuntime, "docker", "The container runtime to be used (docker, crio, containerd, podman).")
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":"+constants.DefaultMountEndpoint, "The argument to pass the minikube mount command on start.")
 	startCmd.Flags().String(criSocket, "", "The cri socket path to be used.")
@@ -161,6 +187,7 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(enableDefaultCNI, false, "Enable the default CNI plugin (/etc/cni/net.d/k8s.conf). Used in conjunction with \"--network-plugin=cni\".")
 	startCmd.Flags().Bool(waitUntilHealthy, true, "Wait until Kubernetes core services are healthy before exiting.")
 	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes core services to be healthy.")
+	startCmd.Flags().StringP(outputFormat, "o", "text", "Format to print progress in. One of: text, json. 'json' emits one JSON event per line.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 }
 
@@ -212,6 +239,7 @@ func initDriverFlags() {
 func initNetworkingFlags() {
 	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().Bool(registryMirrorCache, false, "Pull Docker Hub images through a registry mirror on the host, which keeps them cached for every profile, even after it is deleted.")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, pkgutil.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
@@ -257,6 +285,16 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
+	switch viper.GetString(outputFormat) {
+	case "text":
+	case "json":
+		out.SetJSON(true)
+	default:
+		exit.UsageT("Cannot use output {{.output}}: must be one of 'text' or 'json'", out.V{"output": viper.GetString(outputFormat)})
+	}
+	out.SetSteps(stepInitialSetup, stepSelectingDriver, stepDownloading, stepStartingHost, stepConfiguringRuntime, stepPreparingK8s, stepBootstrapping, stepWaiting, stepDone)
+	out.SetStep(stepInitialSetup)
+
 	prefix := ""
 	if viper.GetString(cfg.MachineProfile) != constants.DefaultMachineName {
 		prefix = fmt.Sprintf("[%s] ", viper.GetString(cfg.MachineProfile))
@@ -286,6 +324,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		exit.WithCodeT(exit.Data, "Unable to load config: {{.error}}", out.V{"error": err})
 	}
 
+	out.SetStep(stepSelectingDriver)
 	driver := selectDriver(oldConfig)
 	err = autoSetDriverOptions(driver)
 	if err != nil {
@@ -307,7 +346,13 @@ func runStart(cmd *cobra.Command, args []string) {
 	if err != nil {
 		exit.WithError("Failed to generate config", err)
 	}
+	// Worker nodes and addon values are managed by other commands, so carry them over
+	if oldConfig != nil {
+		config.Nodes = oldConfig.Nodes
+		config.KubernetesConfig.AddonValues = oldConfig.KubernetesConfig.AddonValues
+	}
 
+	out.SetStep(stepDownloading)
 	// For non-"none", the ISO is required to boot, so block until it is downloaded
 	if driver != constants.DriverNone {
 		if err := cluster.CacheISO(config.MachineConfig); err != nil {
@@ -333,10 +378,12 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	// exits here in case of --download-only option.
 	handleDownloadOnly(&cacheGroup, k8sVersion)
+	out.SetStep(stepStartingHost)
 	mRunner, preExists, machineAPI, host := startMachine(&config)
 	defer machineAPI.Close()
 	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(mRunner, driver)
+	out.SetStep(stepConfiguringRuntime)
+	cr := configureRuntimes(mRunner, driver, startRegistryMirror(host, config.MachineConfig))
 	showVersionInfo(k8sVersion, cr)
 	waitCacheImages(&cacheGroup)
 
@@ -346,11 +393,19 @@ func runStart(cmd *cobra.Command, args []string) {
 		exit.WithError("Failed to setup kubeconfig", err)
 	}
 
+	// A paused cluster is thawed before it is restarted
+	if preExists {
+		unpauseCluster(machineAPI, cr)
+	}
+
 	// setup kubeadm (must come after setupKubeconfig)
+	out.SetStep(stepPreparingK8s)
 	bs := setupKubeAdm(machineAPI, config.KubernetesConfig)
 
 	// pull images or restart cluster
+	out.SetStep(stepBootstrapping)
 	bootstrapCluster(bs, cr, mRunner, config.KubernetesConfig, preExists, isUpgrade)
+	startNodes(machineAPI, &config)
 	configureMounts()
 	if err = loadCachedImagesInConfigFile(); err != nil {
 		out.T(out.FailureType, "Unable to load cached images from config file.")
@@ -361,10 +416,12 @@ func runStart(cmd *cobra.Command, args []string) {
 		prepareNone()
 	}
 	if viper.GetBool(waitUntilHealthy) {
+		out.SetStep(stepWaiting)
 		if err := bs.WaitCluster(config.KubernetesConfig, viper.GetDuration(waitTimeout)); err != nil {
 			exit.WithError("Wait failed", err)
 		}
 	}
+	out.SetStep(stepDone)
 	showKubectlConnectInfo(kubeconfig)
 }
 
@@ -787,6 +844,7 @@ func generateCfgFromFlags(cmd *cobra.Command, k8sVersion string, driver string)
 			DockerOpt:           dockerOpt,
 			InsecureRegistry:    insecureRegistry,
 			RegistryMirror:      registryMirror,
+			RegistryMirrorCache: viper.GetBool(registryMirrorCache),
 			HostOnlyCIDR:        viper.GetString(hostOnlyCIDR),
 			HypervVirtualSwitch: viper.GetString(hypervVirtualSwitch),
 			KVMNetwork:          viper.GetString(kvmNetwork),
@@ -864,7 +922,7 @@ func prepareNone() {
 
 // startHost starts a new minikube host using a VM or None
 func startHost(api libmachine.API, mc cfg.MachineConfig) (*host.Host, bool) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	exists, err := api.Exists(mc.MachineName())
 	if err != nil {
 		exit.WithError("Failed to check if machine exists", err)
 	}
@@ -874,7 +932,7 @@ func startHost(api libmachine.API, mc cfg.MachineConfig) (*host.Host, bool) {
 		host, err = cluster.StartHost(api, mc)
 		if err != nil {
 			out.T(out.Resetting, "Retriable failure: {{.error}}", out.V{"error": err})
-			if derr := cluster.DeleteHost(api); derr != nil {
+			if derr := cluster.DeleteHost(api, mc.MachineName()); derr != nil {
 				glog.Warningf("DeleteHost: %v", derr)
 			}
 		}
@@ -887,6 +945,35 @@ func startHost(api libmachine.API, mc cfg.MachineConfig) (*host.Host, bool) {
 	return host, exists
 }
 
+// startNodes starts the worker nodes of the cluster, joining those which are not yet registered with the control plane
+func startNodes(api libmachine.API, config *cfg.Config) {
+	if len(config.Nodes) == 0 {
+		return
+	}
+	client, err := kapi.Client(cfg.GetMachineName())
+	if err != nil {
+		glog.Warningf("unable to get kubernetes client, joining all nodes: %v", err)
+	}
+	for i := range config.Nodes {
+		n := &config.Nodes[i]
+		registered := false
+		if client != nil {
+			_, err := client.CoreV1().Nodes().Get(n.Name, metav1.GetOptions{})
+			if err != nil && !apierr.IsNotFound(err) {
+				glog.Warningf("unable to get node %s: %v", n.Name, err)
+			}
+			registered = err == nil
+		}
+		out.T(out.Restarting, `Starting node "{{.name}}" ...`, out.V{"name": n.Name})
+		if err := startNode(api, config, n, registered); err != nil {
+			out.WarningT(`Unable to start node "{{.name}}": {{.error}}`, out.V{"name": n.Name, "error": err})
+		}
+	}
+	if err := saveConfig(config); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+}
+
 // validateNetwork tries to catch network problems as soon as possible
 func validateNetwork(h *host.Host) string {
 	ip, err := h.Driver.GetIP()
@@ -992,9 +1079,33 @@ func setupKubeAdm(mAPI libmachine.API, kc cfg.KubernetesConfig) bootstrapper.Boo
 	return bs
 }
 
+// unpauseCluster unpauses a cluster paused by "minikube pause", if it is paused
+func unpauseCluster(mAPI libmachine.API, cr cruntime.Manager) {
+	paused, err := cluster.IsPaused(cr)
+	if err != nil {
+		glog.Warningf("unable to determine if cluster is paused: %v", err)
+		return
+	}
+	if !paused {
+		return
+	}
+	bs, err := getClusterBootstrapper(mAPI, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		exit.WithError("Failed to get bootstrapper", err)
+	}
+	out.T(out.Unpause, "Unpausing Kubernetes ...")
+	if _, err := cluster.Unpause(cr, bs); err != nil {
+		exit.WithError("Failed to unpause cluster", err)
+	}
+}
+
 // configureRuntimes does what needs to happen to get a runtime going.
-func configureRuntimes(runner cruntime.CommandRunner, driver string) cruntime.Manager {
-	config := cruntime.Config{Type: viper.GetString(containerRuntime), Runner: runner}
+func configureRuntimes(runner cruntime.CommandRunner, driver string, mirror string) cruntime.Manager {
+	creds, err := cfg.LoadRegistryCredentials(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		out.WarningT("Unable to load registry credentials: {{.error}}", out.V{"error": err})
+	}
+	config := cruntime.Config{Type: viper.GetString(containerRuntime), Runner: runner, Mirror: mirror, Credentials: creds}
 	cr, err := cruntime.New(config)
 	if err != nil {
 		exit.WithError("Failed runtime", err)
@@ -1019,21 +1130,27 @@ func bootstrapCluster(bs bootstrapper.Bootstrapper, r cruntime.Manager, runner c
 
 	if isUpgrade || !preexisting {
 		out.T(out.Pulling, "Pulling images ...")
-		if err := bs.PullImages(kc); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), pullImagesTimeout)
+		defer cancel()
+		if err := bs.PullImages(ctx, kc); err != nil {
 			out.T(out.FailureType, "Unable to pull images, which may be OK: {{.error}}", out.V{"error": err})
 		}
 	}
 
 	if preexisting {
 		out.T(out.Restarting, "Relaunching Kubernetes using {{.bootstrapper}} ... ", out.V{"bootstrapper": bsName})
-		if err := bs.RestartCluster(kc); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), restartTimeout)
+		defer cancel()
+		if err := bs.RestartCluster(ctx, kc); err != nil {
 			exit.WithLogEntries("Error restarting cluster", err, logs.FindProblems(r, bs, runner))
 		}
 		return
 	}
 
 	out.T(out.Launch, "Launching Kubernetes ... ")
-	if err := bs.StartCluster(kc); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err := bs.StartCluster(ctx, kc); err != nil {
 		exit.WithLogEntries("Error starting cluster", err, logs.FindProblems(r, bs, runner))
 	}
 }
@@ -1053,7 +1170,10 @@ func configureMounts() {
 	mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), viper.GetString(mountString))
 	mountCmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
 	if glog.V(8) {
-		mountCmd.Stdout = os.Stdout
+		// the output of the child would break the stream of JSON events
+		if !out.JSON() {
+			mountCmd.Stdout = os.Stdout
+		}
 		mountCmd.Stderr = os.Stderr
 	}
 	if err := mountCmd.Start(); err != nil {
@@ -17,17 +17,22 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 	"text/template"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -35,13 +40,22 @@ import (
 )
 
 var statusFormat string
+var statusOutput string
+var statusAll bool
+var statusNode string
 
-// Status represents the status
+// Status holds the state of a minikube profile and its cluster components
 type Status struct {
-	Host       string
-	Kubelet    string
-	APIServer  string
-	Kubeconfig string
+	Name              string `json:"name"`
+	Host              string `json:"host"`
+	Kubelet           string `json:"kubelet"`
+	APIServer         string `json:"apiServer"`
+	Kubeconfig        string `json:"kubeconfig"`
+	IP                string `json:"ip,omitempty"`
+	APIServerPort     int    `json:"apiServerPort,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	ContainerRuntime  string `json:"containerRuntime,omitempty"`
+	Error             string `json:"error,omitempty"`
 }
 
 const (
@@ -50,6 +64,15 @@ const (
 	k8sNotRunningStatusFlag      = 1 << 2
 )
 
+const (
+	// Configured means the kubeconfig context points to the running cluster
+	Configured = "Configured"
+	// Misconfigured means the kubeconfig context points elsewhere
+	Misconfigured = "Misconfigured"
+	// Irrelevant means the component does not run on the node, such as the apiserver on a worker
+	Irrelevant = "Irrelevant"
+)
+
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -58,89 +81,294 @@ var statusCmd = &cobra.Command{
 	Exit status contains the status of minikube's VM, cluster and kubernetes encoded on it's bits in this order from right to left.
 	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var returnCode = 0
+		if statusOutput != "text" && statusOutput != "json" {
+			exit.UsageT("Cannot use output {{.output}}: must be one of 'text' or 'json'", out.V{"output": statusOutput})
+		}
+
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			exit.WithCodeT(exit.Unavailable, "Error getting client: {{.error}}", out.V{"error": err})
 		}
 		defer api.Close()
 
-		hostSt, err := cluster.GetHostStatus(api)
-		if err != nil {
-			exit.WithError("Error getting host status", err)
-		}
-
-		kubeletSt := state.None.String()
-		kubeconfigSt := state.None.String()
-		apiserverSt := state.None.String()
-
-		if hostSt == state.Running.String() {
-			clusterBootstrapper, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		profiles := []string{config.GetMachineName()}
+		var invalidProfiles []*config.Profile
+		if statusAll {
+			validProfiles, invalid, err := config.ListProfiles()
 			if err != nil {
-				exit.WithError("Error getting bootstrapper", err)
+				exit.WithError("Error listing profiles", err)
 			}
-			kubeletSt, err = clusterBootstrapper.GetKubeletStatus()
-			if err != nil {
-				glog.Warningf("kubelet err: %v", err)
-				returnCode |= clusterNotRunningStatusFlag
-			} else if kubeletSt != state.Running.String() {
-				returnCode |= clusterNotRunningStatusFlag
-			}
-
-			ip, err := cluster.GetHostDriverIP(api, config.GetMachineName())
-			if err != nil {
-				glog.Errorln("Error host driver ip status:", err)
+			profiles = []string{}
+			for _, p := range validProfiles {
+				profiles = append(profiles, p.Name)
 			}
+			invalidProfiles = invalid
+		}
 
-			apiserverPort, err := kubeconfig.Port(config.GetMachineName())
-			if err != nil {
-				// Fallback to presuming default apiserver port
-				apiserverPort = constants.APIServerPort
+		if statusNode != "" {
+			if statusAll {
+				exit.UsageT("The --node and --all flags cannot be used together")
 			}
-
-			apiserverSt, err = clusterBootstrapper.GetAPIServerStatus(ip, apiserverPort)
-			if err != nil {
-				glog.Errorln("Error apiserver status:", err)
-			} else if apiserverSt != state.Running.String() {
-				returnCode |= clusterNotRunningStatusFlag
+			cc := loadProfileOrExit()
+			if n := selectNode(cc, statusNode); n != nil {
+				st, code, err := nodeStatus(api, cc, *n)
+				if err != nil {
+					exit.WithError("Error getting status", err)
+				}
+				printStatus([]*Status{st})
+				os.Exit(code)
 			}
+		}
 
-			ks, err := kubeconfig.IsClusterInConfig(ip, config.GetMachineName())
+		var returnCode = 0
+		var statuses []*Status
+		for _, name := range profiles {
+			st, code, err := status(api, name)
 			if err != nil {
-				glog.Errorln("Error kubeconfig status:", err)
-			}
-			if ks {
-				kubeconfigSt = "Correctly Configured: pointing to minikube-vm at " + ip.String()
-			} else {
-				kubeconfigSt = "Misconfigured: pointing to stale minikube-vm." +
-					"\nTo fix the kubectl context, run minikube update-context"
-				returnCode |= k8sNotRunningStatusFlag
+				if !statusAll {
+					exit.WithError("Error getting status", err)
+				}
+				// keep going, so that one broken profile does not hide the status of the others
+				glog.Warningf("status of %s: %v", name, err)
+				st = errorStatus(name, err)
+				code = minikubeNotRunningStatusFlag
 			}
-		} else {
+			returnCode |= code
+			statuses = append(statuses, st)
+		}
+		for _, p := range invalidProfiles {
+			statuses = append(statuses, errorStatus(p.Name, errors.New("invalid profile")))
 			returnCode |= minikubeNotRunningStatusFlag
 		}
 
-		status := Status{
-			Host:       hostSt,
-			Kubelet:    kubeletSt,
-			APIServer:  apiserverSt,
-			Kubeconfig: kubeconfigSt,
+		printStatus(statuses)
+		os.Exit(returnCode)
+	},
+}
+
+// printStatus writes statuses in the selected output format
+func printStatus(statuses []*Status) {
+	switch statusOutput {
+	case "json":
+		if err := statusJSON(statuses, os.Stdout); err != nil {
+			exit.WithError("Error encoding status as JSON", err)
 		}
-		tmpl, err := template.New("status").Parse(statusFormat)
-		if err != nil {
-			exit.WithError("Error creating status template", err)
+	default:
+		for _, st := range statuses {
+			if statusAll {
+				out.Ln("%s:", st.Name)
+			}
+			if err := statusText(st, os.Stdout); err != nil {
+				exit.WithError("Error executing status template", err)
+			}
+			if st.Error != "" {
+				out.ErrT(out.FailureType, "Error getting status of {{.name}}: {{.error}}", out.V{"name": st.Name, "error": st.Error})
+			}
 		}
-		err = tmpl.Execute(os.Stdout, status)
+	}
+}
+
+// errorStatus returns the status of a profile whose status could not be determined
+func errorStatus(name string, err error) *Status {
+	return &Status{
+		Name:       name,
+		Host:       state.Error.String(),
+		Kubelet:    state.None.String(),
+		APIServer:  state.None.String(),
+		Kubeconfig: state.None.String(),
+		Error:      err.Error(),
+	}
+}
+
+// status returns the status of the named profile, along with the status exit code bits
+func status(api libmachine.API, name string) (*Status, int, error) {
+	returnCode := 0
+	st := &Status{
+		Name:       name,
+		Host:       state.None.String(),
+		Kubelet:    state.None.String(),
+		APIServer:  state.None.String(),
+		Kubeconfig: state.None.String(),
+	}
+
+	cc, err := config.DefaultLoader.LoadConfigFromFile(name)
+	if err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Error loading profile %s: %v", name, err)
+	}
+	if cc != nil {
+		st.KubernetesVersion = cc.KubernetesConfig.KubernetesVersion
+		st.ContainerRuntime = cc.MachineConfig.ContainerRuntime
+	}
+
+	hostSt, err := cluster.GetHostStatus(api, name)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "host status")
+	}
+	st.Host = hostSt
+	if hostSt != state.Running.String() {
+		returnCode |= minikubeNotRunningStatusFlag
+		return st, returnCode, nil
+	}
+
+	clusterBootstrapper, err := getNodeBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), name)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "bootstrapper")
+	}
+	st.Kubelet, err = clusterBootstrapper.GetKubeletStatus()
+	if err != nil {
+		glog.Warningf("kubelet err: %v", err)
+		returnCode |= clusterNotRunningStatusFlag
+	} else if st.Kubelet != state.Running.String() {
+		returnCode |= clusterNotRunningStatusFlag
+	}
+
+	ip, err := cluster.GetHostDriverIP(api, name)
+	if err != nil {
+		glog.Errorln("Error host driver ip status:", err)
+	} else {
+		st.IP = ip.String()
+	}
+
+	apiserverPort, err := kubeconfig.Port(name)
+	if err != nil {
+		// Fallback to presuming default apiserver port
+		apiserverPort = constants.APIServerPort
+	}
+	st.APIServerPort = apiserverPort
+
+	// A paused apiserver never answers health checks, so look for it first.
+	if isPaused(api, name, cc) {
+		st.APIServer = state.Paused.String()
+		returnCode |= clusterNotRunningStatusFlag
+	} else {
+		st.APIServer, err = clusterBootstrapper.GetAPIServerStatus(ip, apiserverPort)
 		if err != nil {
-			exit.WithError("Error executing status template", err)
+			glog.Errorln("Error apiserver status:", err)
+		} else if st.APIServer != state.Running.String() {
+			returnCode |= clusterNotRunningStatusFlag
 		}
+	}
 
-		os.Exit(returnCode)
-	},
+	ks, err := kubeconfig.IsClusterInConfig(ip, name)
+	if err != nil {
+		glog.Errorln("Error kubeconfig status:", err)
+	}
+	if ks {
+		st.Kubeconfig = Configured
+	} else {
+		st.Kubeconfig = Misconfigured
+		returnCode |= k8sNotRunningStatusFlag
+	}
+	return st, returnCode, nil
+}
+
+// nodeStatus returns the status of a worker node, along with the status exit code bits
+func nodeStatus(api libmachine.API, cc *config.Config, n config.Node) (*Status, int, error) {
+	returnCode := 0
+	st := &Status{
+		Name:              n.Name,
+		Host:              state.None.String(),
+		Kubelet:           state.None.String(),
+		APIServer:         Irrelevant,
+		Kubeconfig:        Irrelevant,
+		KubernetesVersion: n.KubernetesVersion,
+		ContainerRuntime:  cc.MachineConfig.ContainerRuntime,
+	}
+
+	hostSt, err := cluster.GetHostStatus(api, n.Name)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "host status")
+	}
+	st.Host = hostSt
+	if hostSt != state.Running.String() {
+		returnCode |= minikubeNotRunningStatusFlag
+		return st, returnCode, nil
+	}
+
+	bs, err := getNodeBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), n.Name)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "bootstrapper")
+	}
+	st.Kubelet, err = bs.GetKubeletStatus()
+	if err != nil {
+		glog.Warningf("kubelet err: %v", err)
+		returnCode |= clusterNotRunningStatusFlag
+	} else if st.Kubelet != state.Running.String() {
+		returnCode |= clusterNotRunningStatusFlag
+	}
+
+	ip, err := cluster.GetHostDriverIP(api, n.Name)
+	if err != nil {
+		glog.Errorln("Error host driver ip status:", err)
+	} else {
+		st.IP = ip.String()
+	}
+	return st, returnCode, nil
+}
+
+// isPaused returns whether the named profile's cluster has been paused with "minikube pause"
+func isPaused(api libmachine.API, name string, cc *config.Config) bool {
+	if cc == nil {
+		return false
+	}
+	h, err := api.Load(name)
+	if err != nil {
+		glog.Warningf("api load: %v", err)
+		return false
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		glog.Warningf("command runner: %v", err)
+		return false
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		glog.Warningf("runtime: %v", err)
+		return false
+	}
+	paused, err := cluster.IsPaused(cr)
+	if err != nil {
+		glog.Warningf("paused: %v", err)
+		return false
+	}
+	return paused
+}
+
+// statusText writes the status with the status template, describing the kubeconfig state as it always has
+func statusText(st *Status, w io.Writer) error {
+	tmpl, err := template.New("status").Parse(statusFormat)
+	if err != nil {
+		return errors.Wrap(err, "parse template")
+	}
+	text := *st
+	switch st.Kubeconfig {
+	case Configured:
+		text.Kubeconfig = "Correctly Configured: pointing to minikube-vm at " + st.IP
+	case Misconfigured:
+		text.Kubeconfig = "Misconfigured: pointing to stale minikube-vm." +
+			"\nTo fix the kubectl context, run minikube update-context"
+	}
+	return tmpl.Execute(w, text)
+}
+
+// statusJSON writes a single JSON object for the status of a profile, or an array of objects when reporting on all
+// profiles, which is empty rather than null when there are none
+func statusJSON(statuses []*Status, w io.Writer) error {
+	var v interface{} = append([]*Status{}, statuses...)
+	if !statusAll && len(statuses) == 1 {
+		v = statuses[0]
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
 }
 
 func init() {
 	statusCmd.Flags().StringVar(&statusFormat, "format", constants.DefaultStatusFormat,
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#Status`)
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "text",
+		`minikube status --output OUTPUT. json, text`)
+	statusCmd.Flags().BoolVar(&statusAll, "all", false, "Show the status of all valid profiles. With --output json, the statuses are written as an array.")
+	statusCmd.Flags().StringVar(&statusNode, nodeFlag, "", "The node to show the status of. Defaults to the control plane.")
 }
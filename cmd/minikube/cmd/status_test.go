@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestStatusText(t *testing.T) {
+	statusFormat = constants.DefaultStatusFormat
+	var tests = []struct {
+		description string
+		status      *Status
+		want        string
+	}{
+		{
+			description: "configured",
+			status:      &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, IP: "192.168.99.100"},
+			want:        "host: Running\nkubelet: Running\napiserver: Running\nkubectl: Correctly Configured: pointing to minikube-vm at 192.168.99.100\n",
+		},
+		{
+			description: "misconfigured",
+			status:      &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Misconfigured, IP: "192.168.99.100"},
+			want:        "host: Running\nkubelet: Running\napiserver: Running\nkubectl: Misconfigured: pointing to stale minikube-vm.\nTo fix the kubectl context, run minikube update-context\n",
+		},
+		{
+			description: "stopped",
+			status:      &Status{Host: "Stopped", Kubelet: "None", APIServer: "None", Kubeconfig: "None"},
+			want:        "host: Stopped\nkubelet: None\napiserver: None\nkubectl: None\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := statusText(test.status, &b); err != nil {
+				t.Fatalf("statusText: %v", err)
+			}
+			if got := b.String(); got != test.want {
+				t.Errorf("statusText = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStatusJSONFieldNames(t *testing.T) {
+	statusAll = false
+	var b bytes.Buffer
+	st := errorStatus("broken", errors.New("invalid profile"))
+	if err := statusJSON([]*Status{st}, &b); err != nil {
+		t.Fatalf("statusJSON: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, b.String())
+	}
+	want := map[string]interface{}{
+		"name":       "broken",
+		"host":       state.Error.String(),
+		"kubelet":    state.None.String(),
+		"apiServer":  state.None.String(),
+		"kubeconfig": state.None.String(),
+		"error":      "invalid profile",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statusJSON = %v, want %v", got, want)
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	statuses := []*Status{
+		{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, IP: "192.168.99.100", APIServerPort: 8443},
+		{Name: "other", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: "Stopped"},
+	}
+
+	var tests = []struct {
+		description string
+		all         bool
+		statuses    []*Status
+		wantList    bool
+	}{
+		{description: "single profile", all: false, statuses: statuses[:1], wantList: false},
+		{description: "all profiles", all: true, statuses: statuses, wantList: true},
+		{description: "single profile with all", all: true, statuses: statuses[:1], wantList: true},
+		{description: "no profiles", all: true, statuses: nil, wantList: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			statusAll = test.all
+			defer func() { statusAll = false }()
+
+			var b bytes.Buffer
+			if err := statusJSON(test.statuses, &b); err != nil {
+				t.Fatalf("statusJSON: %v", err)
+			}
+			if test.wantList {
+				if !strings.HasPrefix(b.String(), "[") {
+					t.Errorf("statusJSON = %s, want an array", b.String())
+				}
+				var got []Status
+				if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+					t.Fatalf("unmarshal list: %v\n%s", err, b.String())
+				}
+				if len(got) != len(test.statuses) {
+					t.Errorf("got %d statuses, want %d", len(got), len(test.statuses))
+				}
+				return
+			}
+			var got Status
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal object: %v\n%s", err, b.String())
+			}
+			if got != *test.statuses[0] {
+				t.Errorf("got %+v, want %+v", got, *test.statuses[0])
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"time"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/mcnerror"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -51,9 +52,16 @@ func runStop(cmd *cobra.Command, args []string) {
 	}
 	defer api.Close()
 
+	// Worker nodes are stopped first, as they are of no use without the control plane
+	if cc, err := pkg_config.Load(); err == nil {
+		for _, n := range cc.Nodes {
+			stopNode(api, n.Name)
+		}
+	}
+
 	nonexistent := false
 	stop := func() (err error) {
-		err = cluster.StopHost(api)
+		err = cluster.StopHost(api, pkg_config.GetMachineName())
 		switch err := errors.Cause(err).(type) {
 		case mcnerror.ErrHostDoesNotExist:
 			out.T(out.Meh, `"{{.profile_name}}" VM does not exist, nothing to stop`, out.V{"profile_name": profile})
@@ -75,6 +83,7 @@ func runStop(cmd *cobra.Command, args []string) {
 	if err := killMountProcess(); err != nil {
 		out.T(out.WarningType, "Unable to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	stopRegistryMirrors(api, profile)
 
 	machineName := pkg_config.GetMachineName()
 	err = kubeconfig.UnsetCurrentContext(constants.KubeconfigPath, machineName)
@@ -82,3 +91,17 @@ func runStop(cmd *cobra.Command, args []string) {
 		exit.WithError("update config", err)
 	}
 }
+
+// stopNode stops the machine of a worker node, warning on failure
+func stopNode(api libmachine.API, name string) {
+	stop := func() error {
+		err := cluster.StopHost(api, name)
+		if _, ok := errors.Cause(err).(mcnerror.ErrHostDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+	if err := retry.Expo(stop, 5*time.Second, 3*time.Minute, 5); err != nil {
+		out.T(out.WarningType, `Unable to stop node "{{.name}}": {{.error}}`, out.V{"name": name, "error": err})
+	}
+}
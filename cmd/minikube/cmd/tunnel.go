@@ -18,8 +18,14 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"os"
+	"os/exec"
 	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/golang/glog"
@@ -27,63 +33,427 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/service"
 	"k8s.io/minikube/pkg/minikube/tunnel"
 )
 
-var cleanup bool
+var (
+	cleanup      bool
+	background   bool
+	daemon       bool
+	tunnelOutput string
+	tunnelIPPool string
+	// tunnelProfileNames are the profiles given with --profiles, to tunnel in one process
+	tunnelProfileNames []string
+	tunnelAll          bool
+	// tunnelRemapFlags are the PROFILE=CIDR values of --remap
+	tunnelRemapFlags []string
+)
+
+// ipPoolUsage is the usage of the --ip-pool flag
+const ipPoolUsage = "CIDR to allocate a distinct LoadBalancer IP to each service from, instead of its ClusterIP. The CIDR is routed to the cluster, and the IPs allocated are kept for the next tunnel."
+
+// tunnelStartTimeout is how long to wait for a background tunnel to serve its control endpoint
+const tunnelStartTimeout = 30 * time.Second
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
 	Use:   "tunnel",
 	Short: "tunnel makes services of type LoadBalancer accessible on localhost",
-	Long:  `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP`,
+	Long: `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP
+
+If a tunnel is already running for the profile, its status is shown instead of starting another one.
+
+Several profiles can be tunneled in one process with --profiles or --all. The CIDRs routed for the profiles must not overlap: the LoadBalancer services of a profile whose service CIDR overlaps with that of another profile can be remapped to another CIDR with --remap.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		RootCmd.PersistentPreRun(cmd, args)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		manager := tunnel.NewManager()
-
 		if cleanup {
 			glog.Info("Checking for tunnels to cleanup...")
-			if err := manager.CleanupNotRunningTunnels(); err != nil {
+			if err := tunnel.NewManager().CleanupNotRunningTunnels(); err != nil {
 				glog.Errorf("error cleaning up: %s", err)
 			}
 			return
 		}
+		validateTunnelOutput()
+		runTunnel()
+	},
+}
+
+// tunnelStartCmd represents the tunnel start command
+var tunnelStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Starts a tunnel, in the foreground or as a background process",
+	Long: `Starts a tunnel, in the foreground or as a background process.
+
+A background tunnel writes its output to a log file in the minikube home directory, and keeps running until it is stopped with 'minikube tunnel stop' or the cluster stops.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTunnelOutput()
+		if background {
+			startBackgroundTunnel()
+			return
+		}
+		if daemon {
+			// the background tunnel outlives the terminal it was started from
+			signal.Ignore(syscall.SIGHUP)
+		}
+		runTunnel()
+	},
+}
+
+// tunnelStopCmd represents the tunnel stop command
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stops the running tunnel",
+	Long:  `Stops the tunnel running for the profile, and removes the routes it created.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := tunnel.NewManager()
+		for _, machineName := range tunnelProfiles() {
+			err := manager.StopTunnel(machineName, tunnelStartTimeout)
+			if err == tunnel.ErrTunnelNotRunning {
+				out.T(out.Meh, "No tunnel is running for {{.name}}", out.V{"name": machineName})
+				continue
+			}
+			if err != nil {
+				exit.WithError("error stopping tunnel", err)
+			}
+			out.T(out.Stopped, "Stopped the tunnel for {{.name}}", out.V{"name": machineName})
+		}
+	},
+}
+
+// tunnelStatusCmd represents the tunnel status command
+var tunnelStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Gets the status of the running tunnel",
+	Long: `Gets the status of the tunnel running for the profile, as reported by its control endpoint.
+The command exits with a non-zero code if no tunnel is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTunnelOutput()
+		var reports []*tunnel.StatusReport
+		for _, machineName := range tunnelProfiles() {
+			report, err := tunnel.QueryStatus(machineName)
+			if err == tunnel.ErrTunnelNotRunning {
+				out.ErrT(out.Meh, "No tunnel is running for {{.name}}", out.V{"name": machineName})
+				continue
+			}
+			if err != nil {
+				exit.WithError("error getting tunnel status", err)
+			}
+			reports = append(reports, report)
+		}
+		if len(reports) == 0 {
+			os.Exit(exit.Unavailable)
+		}
+
+		var err error
+		switch tunnelOutput {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			// An array is written even for a single profile, so that the output has the same shape for any number of profiles
+			err = enc.Encode(reports)
+		default:
+			for _, report := range reports {
+				if err = tunnel.WriteReport(os.Stdout, report); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			exit.WithError("error writing tunnel status", err)
+		}
+	},
+}
+
+// validateTunnelOutput exits if the output format requested is not supported
+func validateTunnelOutput() {
+	if tunnelOutput != string(tunnel.TextFormat) && tunnelOutput != string(tunnel.JSONFormat) {
+		exit.UsageT("Cannot use output {{.output}}: must be one of 'text' or 'json'", out.V{"output": tunnelOutput})
+	}
+}
+
+// tunnelProfiles returns the profiles to tunnel: those given with --profiles or --all, or else the current profile
+func tunnelProfiles() []string {
+	if tunnelAll {
+		valid, _, err := config.ListProfiles()
+		if err != nil {
+			exit.WithError("error listing profiles", err)
+		}
+		var names []string
+		for _, p := range valid {
+			names = append(names, p.Name)
+		}
+		if len(names) == 0 {
+			exit.WithCodeT(exit.Config, "No valid profiles found to tunnel")
+		}
+		return names
+	}
+	if len(tunnelProfileNames) > 0 {
+		return tunnelProfileNames
+	}
+	return []string{config.GetMachineName()}
+}
+
+// tunnelRemaps parses the --remap flags, which map a profile to the CIDR its LoadBalancer services are remapped to
+func tunnelRemaps() map[string]*net.IPNet {
+	remaps := map[string]*net.IPNet{}
+	for _, r := range tunnelRemapFlags {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			exit.UsageT("Invalid remap {{.remap}}: expected PROFILE=CIDR", out.V{"remap": r})
+		}
+		_, cidr, err := net.ParseCIDR(parts[1])
+		if err != nil {
+			exit.UsageT("Invalid remap {{.remap}}: {{.error}}", out.V{"remap": r, "error": err})
+		}
+		remaps[parts[0]] = cidr
+	}
+	return remaps
+}
+
+// tunnelPool parses the --ip-pool flag, returning nil if it is not set
+func tunnelPool(profiles []string) *net.IPNet {
+	if tunnelIPPool == "" {
+		return nil
+	}
+	if len(profiles) > 1 {
+		exit.UsageT("--ip-pool can only be used with a single profile: use --remap to allocate LoadBalancer IPs to the services of several profiles")
+	}
+	_, cidr, err := net.ParseCIDR(tunnelIPPool)
+	if err != nil {
+		exit.UsageT("Invalid LoadBalancer IP pool {{.cidr}}: {{.error}}", out.V{"cidr": tunnelIPPool, "error": err})
+	}
+	return cidr
+}
+
+// routeCIDR returns the destination CIDR of a route reported by a tunnel, or nil if there is none
+func routeCIDR(route string) *net.IPNet {
+	_, cidr, err := net.ParseCIDR(strings.Split(route, " ")[0])
+	if err != nil {
+		return nil
+	}
+	return cidr
+}
+
+// checkTunnelCIDRs exits if the CIDRs routed for several profiles would overlap, suggesting how to remap them.
+// Tunnels which are already running route the CIDRs they report. A LoadBalancer IP pool is also checked against
+// the CIDRs routed by the tunnels already running for other profiles.
+func checkTunnelCIDRs(profiles []string, remaps map[string]*net.IPNet, pool *net.IPNet) {
+	if len(profiles) < 2 && pool == nil {
+		return
+	}
+	cidrs := map[string]*net.IPNet{}
+	pools := map[string]*net.IPNet{}
+	for _, p := range profiles {
+		if report, err := tunnel.QueryStatus(p); err == nil {
+			if cidr := routeCIDR(report.Route); cidr != nil {
+				cidrs[p] = cidr
+				if lb := routeCIDR(report.LoadBalancerRoute); lb != nil {
+					pools[p] = lb
+				}
+				delete(remaps, p)
+				continue
+			}
+		}
+		cc, err := config.DefaultLoader.LoadConfigFromFile(p)
+		if err != nil {
+			exit.WithError("error loading profile "+p, err)
+		}
+		_, cidr, err := net.ParseCIDR(cc.KubernetesConfig.ServiceCIDR)
+		if err != nil {
+			exit.WithError("error parsing the service CIDR of "+p, err)
+		}
+		cidrs[p] = cidr
+		if pool != nil {
+			pools[p] = pool
+		}
+	}
+	if pool != nil {
+		valid, _, err := config.ListProfiles()
+		if err != nil {
+			glog.Warningf("unable to list the profiles to check the LoadBalancer IP pool against their tunnels: %v", err)
+		}
+		for _, p := range valid {
+			if _, ok := cidrs[p.Name]; ok {
+				continue
+			}
+			report, err := tunnel.QueryStatus(p.Name)
+			if err != nil {
+				continue
+			}
+			if cidr := routeCIDR(report.Route); cidr != nil {
+				cidrs[p.Name] = cidr
+				if lb := routeCIDR(report.LoadBalancerRoute); lb != nil {
+					pools[p.Name] = lb
+				}
+			}
+		}
+	}
+
+	err := tunnel.CheckServiceCIDRs(cidrs, remaps, pools)
+	if oe, ok := err.(*tunnel.CIDROverlapError); ok {
+		out.ErrT(out.Conflict, "The CIDR {{.cidr}} routed for {{.name}} overlaps with the CIDR {{.other_cidr}} routed for {{.other}}", out.V{"cidr": oe.CIDR, "name": oe.Profile, "other_cidr": oe.OtherCIDR, "other": oe.OtherProfile})
+		if oe.Suggestion != nil && pool != nil && (oe.CIDR == pool || oe.OtherCIDR == pool) {
+			out.ErrT(out.Tip, "Allocate the LoadBalancer IPs from another pool, for example with: --ip-pool {{.cidr}}", out.V{"cidr": oe.Suggestion})
+		} else if oe.Suggestion != nil {
+			out.ErrT(out.Tip, "To keep the LoadBalancer services of {{.name}} reachable, remap them to another CIDR, for example with: --remap {{.name}}={{.cidr}}", out.V{"name": oe.OtherProfile, "cidr": oe.Suggestion})
+		}
+		os.Exit(exit.Config)
+	}
+	if err != nil {
+		exit.WithCodeT(exit.BadUsage, "Cannot tunnel the profiles: {{.error}}", out.V{"error": err})
+	}
+}
+
+// runTunnel runs the tunnels of the profiles in the foreground until interrupted, attaching to those already running
+func runTunnel() {
+	profiles := tunnelProfiles()
+	remaps := tunnelRemaps()
+	pool := tunnelPool(profiles)
+	checkTunnelCIDRs(profiles, remaps, pool)
+
+	manager := tunnel.NewManager()
+	manager.SetReportFormat(tunnel.ReportFormat(tunnelOutput))
+	if pool != nil {
+		manager.SetIPPool(pool)
+	}
+	for p, cidr := range remaps {
+		manager.SetRemap(p, cidr)
+	}
 
+	ctrlC := make(chan os.Signal, 1)
+	signal.Notify(ctrlC, os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctrlC
+		cancel()
+	}()
+
+	var dones []chan bool
+	var toStart []string
+	for _, machineName := range profiles {
+		if report, err := tunnel.QueryStatus(machineName); err == nil {
+			out.T(out.Running, "Attaching to the tunnel already running for {{.name}} (pid {{.pid}}). Press Ctrl+C to detach, or run 'minikube tunnel stop' to stop it.", out.V{"name": machineName, "pid": report.Pid})
+			dones = append(dones, manager.AttachTunnel(ctx, machineName, os.Stdout))
+			continue
+		}
+		toStart = append(toStart, machineName)
+	}
+
+	if len(toStart) > 0 {
 		glog.Infof("Creating docker machine client...")
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			exit.WithError("error creating machine client", err)
 		}
-		glog.Infof("Creating k8s client...")
 
-		// Tunnel uses the k8s clientset to query the API server for services in the LoadBalancerEmulator.
-		// We define the tunnel and minikube error free if the API server responds within a second.
-		// This also contributes to better UX, the tunnel status check can happen every second and
-		// doesn't hang on the API server call during startup and shutdown time or if there is a temporary error.
-		clientset, err := service.K8s.GetClientset(1 * time.Second)
-		if err != nil {
-			exit.WithError("error creating clientset", err)
+		for _, machineName := range toStart {
+			glog.Infof("Creating k8s client for %s...", machineName)
+			// Tunnel uses the k8s clientset to query the API server for services in the LoadBalancerEmulator,
+			// and for the hosts of Ingress objects on Linux, where it runs a DNS responder.
+			// We define the tunnel and minikube error free if the API server responds within a second.
+			// This also contributes to better UX, the tunnel status check can happen every second and
+			// doesn't hang on the API server call during startup and shutdown time or if there is a temporary error.
+			clientset, err := service.GetClientsetForProfile(machineName, 1*time.Second)
+			if err != nil {
+				exit.WithError("error creating clientset", err)
+			}
+
+			done, err := manager.StartTunnel(ctx, machineName, api, config.DefaultLoader, clientset)
+			if err != nil {
+				// the tunnels already started clean up before exiting
+				cancel()
+				for _, d := range dones {
+					<-d
+				}
+				exit.WithError("error starting tunnel for "+machineName, err)
+			}
+			dones = append(dones, done)
+		}
+	}
+
+	for _, done := range dones {
+		<-done
+	}
+}
+
+// startBackgroundTunnel runs this command again as a background tunnel process for each profile
+func startBackgroundTunnel() {
+	profiles := tunnelProfiles()
+	remaps := tunnelRemaps()
+	pool := tunnelPool(profiles)
+	checkTunnelCIDRs(profiles, remaps, pool)
+
+	var toStart []string
+	for _, machineName := range profiles {
+		if report, err := tunnel.QueryStatus(machineName); err == nil {
+			out.T(out.Running, "A tunnel is already running for {{.name}} (pid {{.pid}})", out.V{"name": machineName, "pid": report.Pid})
+			continue
 		}
+		toStart = append(toStart, machineName)
+	}
+	if len(toStart) == 0 {
+		return
+	}
 
-		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
-		ctx, cancel := context.WithCancel(context.Background())
-		go func() {
-			<-ctrlC
-			cancel()
-		}()
+	// The routes are added with sudo, which cannot prompt for a password once in the background
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		out.T(out.Permissions, "The tunnel needs sudo to add routes, you may be asked for your password")
+		c := exec.Command("sudo", "-v")
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			exit.WithError("error validating sudo credentials", err)
+		}
+	}
 
-		done, err := manager.StartTunnel(ctx, config.GetMachineName(), api, config.DefaultLoader, clientset.CoreV1())
+	executable, err := os.Executable()
+	if err != nil {
+		exit.WithError("error finding the minikube executable", err)
+	}
+	for _, machineName := range toStart {
+		command := []string{executable, "tunnel", "start", "--daemon", "--profile", machineName, "--output", tunnelOutput, "--alsologtostderr"}
+		if tunnelIPPool != "" {
+			command = append(command, "--ip-pool", tunnelIPPool)
+		}
+		if cidr, ok := remaps[machineName]; ok {
+			command = append(command, "--remap", machineName+"="+cidr.String())
+		}
+		report, err := tunnel.StartBackground(machineName, command, tunnelStartTimeout)
 		if err != nil {
-			exit.WithError("error starting tunnel", err)
+			exit.WithError("error starting tunnel for "+machineName, err)
 		}
-		<-done
-	},
+		out.T(out.Running, "Started a background tunnel for {{.name}} (pid {{.pid}}), logging to {{.log}}", out.V{"name": machineName, "pid": report.Pid, "log": tunnel.LogPath(machineName)})
+	}
+	out.T(out.Tip, "Run 'minikube tunnel status' to check on it, and 'minikube tunnel stop' to stop it")
+}
+
+// addTunnelStartFlags adds the flags of the commands which start tunnels
+func addTunnelStartFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&tunnelOutput, "output", "o", "text", "Format to report the status of the tunnel in. One of: text, json. The json format reports each change as an event.")
+	cmd.Flags().StringVar(&tunnelIPPool, "ip-pool", "", ipPoolUsage)
+	cmd.Flags().StringSliceVar(&tunnelRemapFlags, "remap", nil, "Remap the LoadBalancer services of a profile whose service CIDR overlaps with that of another profile, as PROFILE=CIDR. LoadBalancer IPs are allocated to its services from the CIDR, which is routed instead of its service CIDR.")
 }
 
 func init() {
+	tunnelCmd.PersistentFlags().StringSliceVar(&tunnelProfileNames, "profiles", nil, "Profiles to tunnel in one process, instead of the current profile (can be specified multiple times)")
+	tunnelCmd.PersistentFlags().BoolVar(&tunnelAll, "all", false, "Tunnel all valid profiles in one process")
+
 	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", false, "call with cleanup=true to remove old tunnels")
+	addTunnelStartFlags(tunnelCmd)
+
+	tunnelStartCmd.Flags().BoolVar(&background, "background", false, "Run the tunnel as a background process, which keeps running after the command exits")
+	addTunnelStartFlags(tunnelStartCmd)
+	tunnelStartCmd.Flags().BoolVar(&daemon, "daemon", false, "Run as the background tunnel process (internal use)")
+	if err := tunnelStartCmd.Flags().MarkHidden("daemon"); err != nil {
+		glog.Warningf("hiding daemon flag: %v", err)
+	}
+
+	tunnelStatusCmd.Flags().StringVarP(&tunnelOutput, "output", "o", "text", "Format to print the status in. One of: text, json")
+
+	tunnelCmd.AddCommand(tunnelStartCmd)
+	tunnelCmd.AddCommand(tunnelStopCmd)
+	tunnelCmd.AddCommand(tunnelStatusCmd)
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// unpauseCmd represents the unpause command
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause",
+	Short: "Unpauses a paused local kubernetes cluster",
+	Long:  `Unpauses a Kubernetes cluster which was paused with "pause", and restarts the kubelet.`,
+	Run:   runUnpause,
+}
+
+// runUnpause handles the executes the flow of "minikube unpause"
+func runUnpause(cmd *cobra.Command, args []string) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error getting config", err)
+	}
+	cr, bs := runningClusterRuntime(api, cc)
+
+	out.T(out.Unpause, "Unpausing Kubernetes in {{.name}} ...", out.V{"name": config.GetMachineName()})
+	ids, err := cluster.Unpause(cr, bs)
+	if err != nil {
+		exit.WithError("Unpause", err)
+	}
+	out.T(out.Unpause, "Unpaused {{.count}} containers", out.V{"count": len(ids)})
+}
@@ -31,4 +31,13 @@ define PODMAN_INSTALL_TARGET_CMDS
 	$(INSTALL) -Dm755 $(@D)/bin/podman $(TARGET_DIR)/usr/bin/podman
 endef
 
+define PODMAN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -Dm644 \
+		$(@D)/contrib/varlink/io.podman.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/io.podman.service
+	$(INSTALL) -Dm644 \
+		$(@D)/contrib/varlink/io.podman.socket \
+		$(TARGET_DIR)/usr/lib/systemd/system/io.podman.socket
+endef
+
 $(eval $(generic-package))
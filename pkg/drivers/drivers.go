@@ -212,6 +212,16 @@ func fixDriverPermissions(driver string, path string, interactive bool) error {
 	return nil
 }
 
+// ValidateDriver checks that the plugin binary of a driver is installed and at least version v.
+// Only the drivers which InstallOrUpdate manages are checked.
+func ValidateDriver(driver string, v semver.Version) error {
+	if driver != constants.DriverKvm2 && driver != constants.DriverHyperkit {
+		return nil
+	}
+	_, err := validateDriver(fmt.Sprintf("docker-machine-driver-%s", driver), v)
+	return err
+}
+
 // validateDriver validates if a driver appears to be up-to-date and installed properly
 func validateDriver(driver string, v semver.Version) (string, error) {
 	glog.Infof("Validating %s, PATH=%s", driver, os.Getenv("PATH"))
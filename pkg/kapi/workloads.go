@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kapi
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload describes the readiness of an object running pods
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Desired   int32
+	Ready     int32
+	// selector matches the pods of the workload, and is nil for pods
+	selector labels.Selector
+}
+
+// IsReady returns whether all of the desired pods of the workload are ready
+func (w Workload) IsReady() bool {
+	return w.Ready >= w.Desired
+}
+
+// WorkloadsWithLabel returns the Deployments, DaemonSets, ReplicationControllers and Pods matching a label selector, in all namespaces
+func WorkloadsWithLabel(c kubernetes.Interface, selector string) ([]Workload, error) {
+	opts := meta.ListOptions{LabelSelector: selector}
+	var workloads []Workload
+
+	dps, err := c.AppsV1().Deployments(meta.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "deployments")
+	}
+	for _, d := range dps.Items {
+		s, err := meta.LabelSelectorAsSelector(d.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "deployment %s selector", d.Name)
+		}
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, Workload{"Deployment", d.Namespace, d.Name, desired, d.Status.ReadyReplicas, s})
+	}
+
+	dss, err := c.AppsV1().DaemonSets(meta.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "daemonsets")
+	}
+	for _, d := range dss.Items {
+		s, err := meta.LabelSelectorAsSelector(d.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "daemonset %s selector", d.Name)
+		}
+		workloads = append(workloads, Workload{"DaemonSet", d.Namespace, d.Name, d.Status.DesiredNumberScheduled, d.Status.NumberReady, s})
+	}
+
+	rcs, err := c.CoreV1().ReplicationControllers(meta.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "replicationcontrollers")
+	}
+	for _, r := range rcs.Items {
+		desired := int32(1)
+		if r.Spec.Replicas != nil {
+			desired = *r.Spec.Replicas
+		}
+		workloads = append(workloads, Workload{"ReplicationController", r.Namespace, r.Name, desired, r.Status.ReadyReplicas, labels.SelectorFromSet(r.Spec.Selector)})
+	}
+
+	// Pods which are not managed by one of the workloads above, such as static pods
+	pods, err := c.CoreV1().Pods(meta.NamespaceAll).List(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "pods")
+	}
+	for _, p := range pods.Items {
+		if len(p.OwnerReferences) > 0 && p.OwnerReferences[0].Kind != "Node" {
+			continue
+		}
+		ready := int32(0)
+		if IsPodReady(p) {
+			ready = 1
+		}
+		workloads = append(workloads, Workload{"Pod", p.Namespace, p.Name, 1, ready, nil})
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads, nil
+}
+
+// Pods returns the pods of a workload
+func (w Workload) Pods(c kubernetes.Interface) ([]core.Pod, error) {
+	if w.selector == nil {
+		p, err := c.CoreV1().Pods(w.Namespace).Get(w.Name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []core.Pod{*p}, nil
+	}
+	pods, err := c.CoreV1().Pods(w.Namespace).List(meta.ListOptions{LabelSelector: w.selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// IsPodReady returns whether a pod is running, and its containers are ready
+func IsPodReady(p core.Pod) bool {
+	if p.Status.Phase != core.PodRunning {
+		return false
+	}
+	for _, c := range p.Status.Conditions {
+		if c.Type == core.PodReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Events returns the events about the named objects within a namespace, oldest first
+func Events(c kubernetes.Interface, ns string, names ...string) ([]core.Event, error) {
+	list, err := c.CoreV1().Events(ns).List(meta.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var events []core.Event
+	for _, e := range list.Items {
+		if wanted[e.InvolvedObject.Name] {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].LastTimestamp.Before(&events[j].LastTimestamp) })
+	return events, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolveDependencies returns the addons which need to be enabled for an addon to work,
+// in the order they should be enabled, ending with the addon itself.
+func ResolveDependencies(name string) ([]*Addon, error) {
+	var order []*Addon
+	visited := map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		for _, p := range path {
+			if p == name {
+				return fmt.Errorf("addon dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+			}
+		}
+		if visited[name] {
+			return nil
+		}
+		a, ok := Addons[name]
+		if !ok {
+			if len(path) == 0 {
+				return fmt.Errorf("%s is not a valid addon", name)
+			}
+			return fmt.Errorf("addon %s requires %s, which is not a valid addon", path[len(path)-1], name)
+		}
+		for _, r := range a.Requires {
+			if err := visit(r, append(path, name)); err != nil {
+				return err
+			}
+		}
+		visited[name] = true
+		order = append(order, a)
+		return nil
+	}
+	if err := visit(name, nil); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ConflictsWith returns whether two addons may not be enabled together.
+// A conflict declared by either addon applies to both.
+func (a *Addon) ConflictsWith(other *Addon) bool {
+	for _, c := range a.Conflicts {
+		if c == other.Name() {
+			return true
+		}
+	}
+	for _, c := range other.Conflicts {
+		if c == a.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// Dependents returns the addons which require an addon, sorted by name
+func Dependents(name string) []*Addon {
+	var dependents []*Addon
+	for _, a := range Addons {
+		for _, r := range a.Requires {
+			if r == name {
+				dependents = append(dependents, a)
+				break
+			}
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool { return dependents[i].Name() < dependents[j].Name() })
+	return dependents
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	test := map[string]*Addon{
+		"a":       NewAddon(nil, false, "a").requires("b", "c"),
+		"b":       NewAddon(nil, false, "b").requires("c"),
+		"c":       NewAddon(nil, false, "c"),
+		"cycle":   NewAddon(nil, false, "cycle").requires("cycle2"),
+		"cycle2":  NewAddon(nil, false, "cycle2").requires("cycle"),
+		"missing": NewAddon(nil, false, "missing").requires("nonexistent"),
+	}
+	for name, a := range test {
+		Addons[name] = a
+		defer delete(Addons, name)
+	}
+
+	var tests = []struct {
+		name      string
+		want      []string
+		shouldErr bool
+	}{
+		{name: "c", want: []string{"c"}},
+		{name: "a", want: []string{"c", "b", "a"}},
+		{name: "cycle", shouldErr: true},
+		{name: "missing", shouldErr: true},
+		{name: "nonexistent", shouldErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			addons, err := ResolveDependencies(tc.name)
+			if err != nil {
+				if !tc.shouldErr {
+					t.Fatalf("ResolveDependencies(%s): %v", tc.name, err)
+				}
+				return
+			}
+			if tc.shouldErr {
+				t.Fatalf("ResolveDependencies(%s) = %v, expected error", tc.name, addons)
+			}
+			var got []string
+			for _, a := range addons {
+				got = append(got, a.Name())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ResolveDependencies(%s) differs: (-want +got)\n%s", tc.name, diff)
+			}
+		})
+	}
+}
+
+func TestBundledAddonDependencies(t *testing.T) {
+	efk, err := ResolveDependencies("efk")
+	if err != nil {
+		t.Fatalf("ResolveDependencies(efk): %v", err)
+	}
+	if len(efk) != 2 || efk[0].Name() != "storage-provisioner" {
+		t.Errorf("efk should be enabled after storage-provisioner, got %v", efk)
+	}
+
+	gluster, storageclass := Addons["storage-provisioner-gluster"], Addons["default-storageclass"]
+	if !gluster.ConflictsWith(storageclass) || !storageclass.ConflictsWith(gluster) {
+		t.Errorf("storage-provisioner-gluster and default-storageclass should conflict")
+	}
+	if gluster.ConflictsWith(Addons["efk"]) {
+		t.Errorf("storage-provisioner-gluster and efk should not conflict")
+	}
+
+	var dependents []string
+	for _, a := range Dependents("storage-provisioner") {
+		dependents = append(dependents, a.Name())
+	}
+	if diff := cmp.Diff([]string{"efk"}, dependents); diff != "" {
+		t.Errorf("Dependents(storage-provisioner) differs: (-want +got)\n%s", diff)
+	}
+}
@@ -36,6 +36,21 @@ type Addon struct {
 	Assets    []*BinAsset
 	enabled   bool
 	addonName string
+
+	// Requires are the addons which must be enabled before this addon
+	Requires []string
+	// Conflicts are the addons which may not be enabled along with this addon
+	Conflicts []string
+	// Runtime is the container runtime required by the addon, if any
+	Runtime string
+	// Selector is a label selector matching the workloads of the addon, used to check its health
+	Selector string
+
+	// The following fields are only set for user-defined addons
+	UserDefined bool
+	Description string
+	// Images are cached and loaded into the cluster when the addon is enabled
+	Images []string
 }
 
 // NewAddon creates a new Addon
@@ -53,6 +68,30 @@ func (a *Addon) Name() string {
 	return a.addonName
 }
 
+// requires declares the addons which must be enabled before this addon
+func (a *Addon) requires(names ...string) *Addon {
+	a.Requires = append(a.Requires, names...)
+	return a
+}
+
+// conflictsWith declares the addons which may not be enabled along with this addon
+func (a *Addon) conflictsWith(names ...string) *Addon {
+	a.Conflicts = append(a.Conflicts, names...)
+	return a
+}
+
+// requiresRuntime declares the container runtime required by the addon
+func (a *Addon) requiresRuntime(runtime string) *Addon {
+	a.Runtime = runtime
+	return a
+}
+
+// withSelector declares the label selector matching the workloads of the addon
+func (a *Addon) withSelector(selector string) *Addon {
+	a.Selector = selector
+	return a
+}
+
 // IsEnabled checks if an Addon is enabled
 func (a *Addon) IsEnabled() (bool, error) {
 	addonStatusText, err := config.Get(a.addonName)
@@ -66,6 +105,9 @@ func (a *Addon) IsEnabled() (bool, error) {
 	return a.enabled, nil
 }
 
+// addonLabel is the label set on the workloads of most bundled addons
+const addonLabel = "kubernetes.io/minikube-addons"
+
 // Addons is the list of addons
 // TODO: Make dynamically loadable: move this data to a .yaml file within each addon directory
 var Addons = map[string]*Addon{
@@ -76,19 +118,19 @@ var Addons = map[string]*Addon{
 			"addon-manager.yaml.tmpl",
 			"0640",
 			true),
-	}, true, "addon-manager"),
+	}, true, "addon-manager").withSelector(addonLabel + "=addon-manager"),
 	"dashboard": NewAddon([]*BinAsset{
 		MustBinAsset("deploy/addons/dashboard/dashboard-clusterrole.yaml", constants.GuestAddonsDir, "dashboard-clusterrole.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-clusterrolebinding.yaml", constants.GuestAddonsDir, "dashboard-clusterrolebinding.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-configmap.yaml", constants.GuestAddonsDir, "dashboard-configmap.yaml", "0640", false),
-		MustBinAsset("deploy/addons/dashboard/dashboard-dp.yaml", constants.GuestAddonsDir, "dashboard-dp.yaml", "0640", false),
+		MustBinAsset("deploy/addons/dashboard/dashboard-dp.yaml", constants.GuestAddonsDir, "dashboard-dp.yaml", "0640", true),
 		MustBinAsset("deploy/addons/dashboard/dashboard-ns.yaml", constants.GuestAddonsDir, "dashboard-ns.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-role.yaml", constants.GuestAddonsDir, "dashboard-role.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-rolebinding.yaml", constants.GuestAddonsDir, "dashboard-rolebinding.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-sa.yaml", constants.GuestAddonsDir, "dashboard-sa.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-secret.yaml", constants.GuestAddonsDir, "dashboard-secret.yaml", "0640", false),
 		MustBinAsset("deploy/addons/dashboard/dashboard-svc.yaml", constants.GuestAddonsDir, "dashboard-svc.yaml", "0640", false),
-	}, false, "dashboard"),
+	}, false, "dashboard").withSelector(addonLabel + "=dashboard"),
 	"default-storageclass": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/storageclass/storageclass.yaml.tmpl",
@@ -104,7 +146,7 @@ var Addons = map[string]*Addon{
 			"storage-provisioner.yaml",
 			"0640",
 			true),
-	}, true, "storage-provisioner"),
+	}, true, "storage-provisioner").withSelector("integration-test=storage-provisioner"),
 	"storage-provisioner-gluster": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/storage-provisioner-gluster/storage-gluster-ns.yaml.tmpl",
@@ -130,7 +172,7 @@ var Addons = map[string]*Addon{
 			"storage-privisioner-glusterfile.yaml",
 			"0640",
 			false),
-	}, false, "storage-provisioner-gluster"),
+	}, false, "storage-provisioner-gluster").conflictsWith("default-storageclass").withSelector(addonLabel + "=storage-provisioner-gluster"),
 	"heapster": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/heapster/influx-grafana-rc.yaml.tmpl",
@@ -162,7 +204,7 @@ var Addons = map[string]*Addon{
 			"heapster-svc.yaml",
 			"0640",
 			false),
-	}, false, "heapster"),
+	}, false, "heapster").withSelector(addonLabel + "=heapster"),
 	"efk": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/efk/elasticsearch-rc.yaml.tmpl",
@@ -200,7 +242,7 @@ var Addons = map[string]*Addon{
 			"kibana-svc.yaml",
 			"0640",
 			false),
-	}, false, "efk"),
+	}, false, "efk").requires("storage-provisioner").withSelector(addonLabel + "=efk"),
 	"ingress": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/ingress/ingress-configmap.yaml.tmpl",
@@ -220,7 +262,7 @@ var Addons = map[string]*Addon{
 			"ingress-dp.yaml",
 			"0640",
 			true),
-	}, false, "ingress"),
+	}, false, "ingress").withSelector("app.kubernetes.io/name=nginx-ingress-controller"),
 	"metrics-server": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/metrics-server/metrics-apiservice.yaml.tmpl",
@@ -240,14 +282,14 @@ var Addons = map[string]*Addon{
 			"metrics-server-service.yaml",
 			"0640",
 			false),
-	}, false, "metrics-server"),
+	}, false, "metrics-server").withSelector(addonLabel + "=metrics-server"),
 	"registry": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/registry/registry-rc.yaml.tmpl",
 			constants.GuestAddonsDir,
 			"registry-rc.yaml",
 			"0640",
-			false),
+			true),
 		MustBinAsset(
 			"deploy/addons/registry/registry-svc.yaml.tmpl",
 			constants.GuestAddonsDir,
@@ -260,7 +302,7 @@ var Addons = map[string]*Addon{
 			"registry-proxy.yaml",
 			"0640",
 			false),
-	}, false, "registry"),
+	}, false, "registry").withSelector(addonLabel + "=registry"),
 	"registry-creds": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/registry-creds/registry-creds-rc.yaml.tmpl",
@@ -268,7 +310,7 @@ var Addons = map[string]*Addon{
 			"registry-creds-rc.yaml",
 			"0640",
 			false),
-	}, false, "registry-creds"),
+	}, false, "registry-creds").withSelector(addonLabel + "=registry-creds"),
 	"freshpod": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/freshpod/freshpod-rc.yaml.tmpl",
@@ -276,7 +318,7 @@ var Addons = map[string]*Addon{
 			"freshpod-rc.yaml",
 			"0640",
 			true),
-	}, false, "freshpod"),
+	}, false, "freshpod").withSelector(addonLabel + "=freshpod"),
 	"nvidia-driver-installer": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/gpu/nvidia-driver-installer.yaml.tmpl",
@@ -284,7 +326,7 @@ var Addons = map[string]*Addon{
 			"nvidia-driver-installer.yaml",
 			"0640",
 			true),
-	}, false, "nvidia-driver-installer"),
+	}, false, "nvidia-driver-installer").withSelector(addonLabel + "=nvidia-driver-installer"),
 	"nvidia-gpu-device-plugin": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/gpu/nvidia-gpu-device-plugin.yaml.tmpl",
@@ -292,7 +334,7 @@ var Addons = map[string]*Addon{
 			"nvidia-gpu-device-plugin.yaml",
 			"0640",
 			true),
-	}, false, "nvidia-gpu-device-plugin"),
+	}, false, "nvidia-gpu-device-plugin").withSelector(addonLabel + "=nvidia-gpu-device-plugin"),
 	"logviewer": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/logviewer/logviewer-dp-and-svc.yaml.tmpl",
@@ -306,7 +348,7 @@ var Addons = map[string]*Addon{
 			"logviewer-rbac.yaml",
 			"0640",
 			false),
-	}, false, "logviewer"),
+	}, false, "logviewer").withSelector(addonLabel + "=logviewer"),
 	"gvisor": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/gvisor/gvisor-pod.yaml.tmpl",
@@ -326,16 +368,16 @@ var Addons = map[string]*Addon{
 			constants.GvisorConfigTomlTargetName,
 			"0640",
 			true),
-	}, false, "gvisor"),
+	}, false, "gvisor").requiresRuntime("containerd").withSelector(addonLabel + "=gvisor"),
 }
 
 // AddMinikubeDirAssets adds all addons and files to the list
-// of files to be copied to the vm.
+// of files to be copied to the vm. User-defined addons are skipped, as they are copied when enabled.
 func AddMinikubeDirAssets(assets *[]CopyableFile) error {
-	if err := addMinikubeDirToAssets(localpath.MakeMiniPath("addons"), constants.GuestAddonsDir, assets); err != nil {
+	if err := addMinikubeDirToAssets(localpath.MakeMiniPath("addons"), constants.GuestAddonsDir, assets, true); err != nil {
 		return errors.Wrap(err, "adding addons folder to assets")
 	}
-	if err := addMinikubeDirToAssets(localpath.MakeMiniPath("files"), "", assets); err != nil {
+	if err := addMinikubeDirToAssets(localpath.MakeMiniPath("files"), "", assets, false); err != nil {
 		return errors.Wrap(err, "adding files rootfs to assets")
 	}
 
@@ -345,7 +387,8 @@ func AddMinikubeDirAssets(assets *[]CopyableFile) error {
 // AddMinikubeDirToAssets adds all the files in the basedir argument to the list
 // of files to be copied to the vm.  If vmpath is left blank, the files will be
 // transferred to the location according to their relative minikube folder path.
-func addMinikubeDirToAssets(basedir, vmpath string, assets *[]CopyableFile) error {
+// If skipUserAddons is set, directories containing a user-defined addon are skipped.
+func addMinikubeDirToAssets(basedir, vmpath string, assets *[]CopyableFile, skipUserAddons bool) error {
 	return filepath.Walk(basedir, func(hostpath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -354,6 +397,9 @@ func addMinikubeDirToAssets(basedir, vmpath string, assets *[]CopyableFile) erro
 		if err != nil {
 			return errors.Wrapf(err, "checking if %s is directory", hostpath)
 		}
+		if isDir && skipUserAddons && isUserAddonDir(hostpath) {
+			return filepath.SkipDir
+		}
 		if !isDir {
 			vmdir := vmpath
 			if vmdir == "" {
@@ -384,7 +430,7 @@ func addMinikubeDirToAssets(basedir, vmpath string, assets *[]CopyableFile) erro
 }
 
 // GenerateTemplateData generates template data for template assets
-func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
+func GenerateTemplateData(cfg config.KubernetesConfig) map[string]interface{} {
 
 	a := runtime.GOARCH
 	// Some legacy docker images still need the -arch suffix
@@ -393,15 +439,19 @@ func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
 	if runtime.GOARCH != "amd64" {
 		ea = runtime.GOARCH
 	}
-	opts := struct {
-		Arch            string
-		ExoticArch      string
-		ImageRepository string
-	}{
-		Arch:            a,
-		ExoticArch:      ea,
-		ImageRepository: cfg.ImageRepository,
+	return map[string]interface{}{
+		"Arch":            a,
+		"ExoticArch":      ea,
+		"ImageRepository": cfg.ImageRepository,
 	}
+}
 
-	return opts
+// GenerateAddonTemplateData generates template data for the assets of an addon, including the values configured for it.
+// Configured values take precedence over the generated ones.
+func GenerateAddonTemplateData(cfg config.KubernetesConfig, addon string) map[string]interface{} {
+	data := GenerateTemplateData(cfg)
+	for k, v := range cfg.AddonValues[addon] {
+		data[k] = v
+	}
+	return data
 }
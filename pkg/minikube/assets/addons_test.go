@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/localpath"
 )
@@ -140,7 +141,7 @@ func TestAddMinikubeDirAssets(t *testing.T) {
 			}
 
 			var actualFiles []CopyableFile
-			err = addMinikubeDirToAssets(testFileBaseDir, test.vmPath, &actualFiles)
+			err = addMinikubeDirToAssets(testFileBaseDir, test.vmPath, &actualFiles, false)
 			if err != nil {
 				t.Errorf("got unexpected error adding minikube dir assets: %v", err)
 				return
@@ -157,3 +158,21 @@ func TestAddMinikubeDirAssets(t *testing.T) {
 	}
 
 }
+
+func TestGenerateAddonTemplateData(t *testing.T) {
+	cfg := config.KubernetesConfig{
+		ImageRepository: "example.com",
+		AddonValues: map[string]config.AddonValues{
+			"ingress": {"Replicas": "2", "ImageRepository": "mirror.example.com"},
+		},
+	}
+
+	got := GenerateAddonTemplateData(cfg, "ingress")
+	if got["Replicas"] != "2" || got["ImageRepository"] != "mirror.example.com" || got["Arch"] == "" {
+		t.Errorf("unexpected ingress template data: %v", got)
+	}
+	got = GenerateAddonTemplateData(cfg, "registry")
+	if _, ok := got["Replicas"]; ok || got["ImageRepository"] != "example.com" {
+		t.Errorf("unexpected registry template data: %v", got)
+	}
+}
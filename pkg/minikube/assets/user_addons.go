@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// AddonMetadataFile is the file describing a user-defined addon, within the addon directory
+const AddonMetadataFile = "addon.json"
+
+// AddonMetadata describes a user-defined addon
+type AddonMetadata struct {
+	// Name is the name of the addon, which defaults to the name of its directory
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Enabled is whether the addon is enabled when not configured otherwise
+	Enabled bool `json:"enabled"`
+	// Images are the images required by the addon
+	Images []string `json:"images"`
+	// Requires are the addons which must be enabled before the addon
+	Requires []string `json:"requires"`
+	// Conflicts are the addons which may not be enabled along with the addon
+	Conflicts []string `json:"conflicts"`
+	// Runtime is the container runtime required by the addon, if any
+	Runtime string `json:"runtime"`
+	// Selector is a label selector matching the workloads of the addon, used to check its health
+	Selector string `json:"selector"`
+}
+
+// manifestExtensions are the file extensions of the manifests of a user-defined addon.
+// A ".tmpl" suffix is also accepted, and stripped from the name of the file in the VM, which is prefixed with the addon name.
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// isUserAddonDir returns whether a directory contains a user-defined addon
+func isUserAddonDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, AddonMetadataFile))
+	return err == nil
+}
+
+// isManifest returns whether a file within a user-defined addon directory is a manifest
+func isManifest(name string) bool {
+	if name == AddonMetadataFile {
+		return false
+	}
+	name = strings.TrimSuffix(name, ".tmpl")
+	for _, ext := range manifestExtensions {
+		if filepath.Ext(name) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadUserAddons loads the user-defined addons found in the subdirectories of dir.
+// Invalid addons are skipped with a warning, so that they do not prevent the use of other addons.
+func LoadUserAddons(dir string) ([]*Addon, error) {
+	items, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var addons []*Addon
+	for _, f := range items {
+		addonDir := filepath.Join(dir, f.Name())
+		if !f.IsDir() || !isUserAddonDir(addonDir) {
+			continue
+		}
+		a, err := loadUserAddon(addonDir)
+		if err != nil {
+			glog.Warningf("skipping invalid addon in %s: %v", addonDir, err)
+			continue
+		}
+		addons = append(addons, a)
+	}
+	return addons, nil
+}
+
+// loadUserAddon loads the metadata and manifests of a user-defined addon
+func loadUserAddon(dir string) (*Addon, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, AddonMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	md := AddonMetadata{}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", AddonMetadataFile)
+	}
+	if md.Name == "" {
+		md.Name = filepath.Base(dir)
+	}
+	if strings.ContainsAny(md.Name, " \t\n/") {
+		return nil, fmt.Errorf("invalid addon name %q", md.Name)
+	}
+
+	items, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var manifests []*BinAsset
+	for _, f := range items {
+		if f.IsDir() || !isManifest(f.Name()) {
+			continue
+		}
+		// Manifests are prefixed with the addon name, so that addons sharing a file name do not overwrite each other in the VM
+		target := fmt.Sprintf("%s-%s", md.Name, strings.TrimSuffix(f.Name(), ".tmpl"))
+		m, err := NewBinAssetFromFile(filepath.Join(dir, f.Name()), constants.GuestAddonsDir, target, "0640", true)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no manifests found")
+	}
+
+	a := NewAddon(manifests, md.Enabled, md.Name)
+	a.UserDefined = true
+	a.Description = md.Description
+	a.Images = md.Images
+	a.Requires = md.Requires
+	a.Conflicts = md.Conflicts
+	a.Runtime = md.Runtime
+	a.Selector = md.Selector
+	return a, nil
+}
+
+// RegisterUserAddons adds the user-defined addons within the minikube addons directory to Addons.
+// Bundled addons take precedence over user-defined addons of the same name.
+func RegisterUserAddons() error {
+	addons, err := LoadUserAddons(localpath.MakeMiniPath("addons"))
+	if err != nil {
+		return errors.Wrap(err, "loading user-defined addons")
+	}
+	for _, a := range addons {
+		if existing, ok := Addons[a.Name()]; ok && !existing.UserDefined {
+			glog.Warningf("ignoring user-defined addon %q, as a bundled addon has the same name", a.Name())
+			continue
+		}
+		glog.Infof("registering user-defined addon %q with %d manifests", a.Name(), len(a.Assets))
+		Addons[a.Name()] = a
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// writeFiles creates files relative to dir, with the given contents
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestLoadUserAddons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"internal/addon.json":           `{"description": "Our internal tools", "enabled": true, "images": ["example.com/tool:v1"]}`,
+		"internal/deployment.yaml.tmpl": "image: {{.ImageRepository}}tool",
+		"internal/service.yaml":         "kind: Service",
+		"internal/README.md":            "not a manifest",
+		"renamed/addon.json":            `{"name": "other"}`,
+		"renamed/pod.yml":               "kind: Pod",
+		"empty/addon.json":              `{}`,
+		"broken/addon.json":             `{`,
+		"broken/pod.yaml":               "kind: Pod",
+		"plain/pod.yaml":                "kind: Pod",
+	})
+
+	addons, err := LoadUserAddons(dir)
+	if err != nil {
+		t.Fatalf("LoadUserAddons: %v", err)
+	}
+	got := map[string]*Addon{}
+	for _, a := range addons {
+		got[a.Name()] = a
+	}
+	if len(got) != 2 || got["internal"] == nil || got["other"] == nil {
+		t.Fatalf("LoadUserAddons returned %v, want internal and other", got)
+	}
+
+	internal := got["internal"]
+	if !internal.UserDefined || internal.Description != "Our internal tools" {
+		t.Errorf("unexpected metadata: %+v", internal)
+	}
+	if diff := cmp.Diff([]string{"example.com/tool:v1"}, internal.Images); diff != "" {
+		t.Errorf("images differ: (-want +got)\n%s", diff)
+	}
+	if enabled, err := internal.IsEnabled(); err != nil || !enabled {
+		t.Errorf("IsEnabled() = %v, %v, want true", enabled, err)
+	}
+
+	var names []string
+	for _, m := range internal.Assets {
+		names = append(names, m.GetTargetName())
+		if m.GetTargetDir() != constants.GuestAddonsDir {
+			t.Errorf("%s target dir = %q, want %q", m.GetAssetName(), m.GetTargetDir(), constants.GuestAddonsDir)
+		}
+	}
+	if diff := cmp.Diff([]string{"internal-deployment.yaml", "internal-service.yaml"}, names); diff != "" {
+		t.Errorf("manifests differ: (-want +got)\n%s", diff)
+	}
+
+	f, err := internal.Assets[0].Evaluate(GenerateTemplateData(config.KubernetesConfig{ImageRepository: "example.com/"}))
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, f); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := "image: example.com/tool"; b.String() != want {
+		t.Errorf("evaluated manifest = %q, want %q", b.String(), want)
+	}
+}
+
+func TestUserAddonsSharingFileNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"first/addon.json":       `{}`,
+		"first/deployment.yaml":  "name: first",
+		"second/addon.json":      `{}`,
+		"second/deployment.yaml": "name: second",
+	})
+
+	addons, err := LoadUserAddons(dir)
+	if err != nil {
+		t.Fatalf("LoadUserAddons: %v", err)
+	}
+	targets := map[string]string{}
+	for _, a := range addons {
+		for _, m := range a.Assets {
+			target := filepath.Join(m.GetTargetDir(), m.GetTargetName())
+			if other, ok := targets[target]; ok {
+				t.Errorf("addons %q and %q both install %s", other, a.Name(), target)
+			}
+			targets[target] = a.Name()
+		}
+	}
+	want := map[string]string{
+		filepath.Join(constants.GuestAddonsDir, "first-deployment.yaml"):  "first",
+		filepath.Join(constants.GuestAddonsDir, "second-deployment.yaml"): "second",
+	}
+	if diff := cmp.Diff(want, targets); diff != "" {
+		t.Errorf("targets differ: (-want +got)\n%s", diff)
+	}
+}
+
+func TestAddMinikubeDirAssetsSkipsUserAddons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"internal/addon.json": `{}`,
+		"internal/pod.yaml":   "kind: Pod",
+		"plain/pod.yaml":      "kind: Pod",
+	})
+
+	var files []CopyableFile
+	if err := addMinikubeDirToAssets(dir, constants.GuestAddonsDir, &files, true); err != nil {
+		t.Fatalf("addMinikubeDirToAssets: %v", err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.GetAssetName())
+	}
+	if diff := cmp.Diff([]string{filepath.Join(dir, "plain", "pod.yaml")}, got); diff != "" {
+		t.Errorf("files differ: (-want +got)\n%s", diff)
+	}
+}
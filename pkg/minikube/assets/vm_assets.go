@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 
@@ -111,6 +112,14 @@ func (f *FileAsset) Read(p []byte) (int, error) {
 	return f.reader.Read(p)
 }
 
+// Close closes the file, which is only needed when copying many files
+func (f *FileAsset) Close() error {
+	if c, ok := f.reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // MemoryAsset is a memory-based asset
 type MemoryAsset struct {
 	BaseAsset
@@ -173,12 +182,35 @@ func NewBinAsset(name, targetDir, targetName, permissions string, isTemplate boo
 	return m, err
 }
 
+// NewBinAssetFromFile creates a new BinAsset from a file on the host, such as the manifest of a user-defined addon
+func NewBinAssetFromFile(src, targetDir, targetName, permissions string, isTemplate bool) (*BinAsset, error) {
+	contents, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", src)
+	}
+	m := &BinAsset{
+		BaseAsset: BaseAsset{
+			AssetName:   src,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+		template: nil,
+	}
+	err = m.setData(contents, isTemplate)
+	return m, err
+}
+
 func defaultValue(defValue string, val interface{}) string {
 	if val == nil {
 		return defValue
 	}
 	strVal, ok := val.(string)
-	if !ok || strVal == "" {
+	if !ok {
+		// Values read from a file may be numbers or booleans
+		return fmt.Sprint(val)
+	}
+	if strVal == "" {
 		return defValue
 	}
 	return strVal
@@ -189,7 +221,11 @@ func (m *BinAsset) loadData(isTemplate bool) error {
 	if err != nil {
 		return err
 	}
+	return m.setData(contents, isTemplate)
+}
 
+// setData parses the contents of the asset, as a template if requested
+func (m *BinAsset) setData(contents []byte, isTemplate bool) error {
 	if isTemplate {
 		tpl, err := template.New(m.AssetName).Funcs(template.FuncMap{"default": defaultValue}).Parse(string(contents))
 		if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -36,16 +37,30 @@ type LogOptions struct {
 // Bootstrapper contains all the methods needed to bootstrap a kubernetes cluster
 type Bootstrapper interface {
 	// PullImages pulls images necessary for a cluster. Success should not be required.
-	PullImages(config.KubernetesConfig) error
-	StartCluster(config.KubernetesConfig) error
+	PullImages(context.Context, config.KubernetesConfig) error
+	StartCluster(context.Context, config.KubernetesConfig) error
 	UpdateCluster(config.KubernetesConfig) error
-	RestartCluster(config.KubernetesConfig) error
+	RestartCluster(context.Context, config.KubernetesConfig) error
 	DeleteCluster(config.KubernetesConfig) error
 	WaitCluster(config.KubernetesConfig, time.Duration) error
+	// GenerateToken creates a short-lived token, used by JoinCluster to join additional nodes to the cluster
+	GenerateToken(config.KubernetesConfig) (string, error)
+	// JoinCluster joins this machine to the control plane described by the KubernetesConfig, as the given worker node
+	JoinCluster(config.KubernetesConfig, config.Node, string) error
+	// RestartNode restarts the kubelet of a worker node which has already joined the control plane described by the KubernetesConfig
+	RestartNode(config.KubernetesConfig, config.Node) error
+	// SnapshotCluster saves a snapshot of the cluster state to the given path on the host
+	SnapshotCluster(config.KubernetesConfig, string) error
+	// RestoreCluster replaces the cluster state with the snapshot at the given path on the host, and restarts the control plane
+	RestoreCluster(config.KubernetesConfig, string) error
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(LogOptions) map[string]string
 	SetupCerts(cfg config.KubernetesConfig) error
 	GetKubeletStatus() (string, error)
+	// StopKubelet stops the kubelet, so that it no longer manages the containers on the host
+	StopKubelet() error
+	// StartKubelet starts the kubelet
+	StartKubelet() error
 	GetAPIServerStatus(net.IP, int) (string, error)
 }
 
@@ -56,6 +56,11 @@ var (
 	}
 )
 
+// CertFiles returns the names of the certificates copied by SetupCerts, relative to localpath.MiniPath()
+func CertFiles() []string {
+	return append([]string{}, certs...)
+}
+
 // SetupCerts gets the generated credentials required to talk to the APIServer.
 func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig) error {
 	// WARNING: This function was not designed for multiple profiles, so it is VERY racey:
@@ -131,10 +136,8 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig) error {
 	kubeCfgFile := assets.NewMemoryAsset(data, constants.GuestPersistentDir, "kubeconfig", "0644")
 	copyableFiles = append(copyableFiles, kubeCfgFile)
 
-	for _, f := range copyableFiles {
-		if err := cmd.Copy(f); err != nil {
-			return errors.Wrapf(err, "Copy %s", f.GetAssetName())
-		}
+	if err := cmd.CopyAll(copyableFiles); err != nil {
+		return errors.Wrap(err, "copying certs")
 	}
 
 	// configure CA certificates
@@ -177,7 +180,7 @@ func generateCerts(k8s config.KubernetesConfig) error {
 	apiServerIPs := append(
 		k8s.APIServerIPs,
 		[]net.IP{net.ParseIP(k8s.NodeIP), serviceIP, net.ParseIP("10.0.0.1")}...)
-	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName)
+	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
 		apiServerNames,
 		util.GetAlternateDNS(k8s.DNSDomain)...)
@@ -18,9 +18,14 @@ package kubeadm
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
-
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 
@@ -48,6 +53,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/util"
@@ -102,6 +108,20 @@ var PodsByLayer = []pod{
 // yamlConfigPath is the path to the kubeadm configuration
 var yamlConfigPath = path.Join(constants.GuestEphemeralDir, "kubeadm.yaml")
 
+// joinTokenTTL is how long a token generated for joining a node remains valid
+const joinTokenTTL = "15m"
+
+// kubeletKubeconfig is the kubeconfig written for the kubelet by kubeadm
+const kubeletKubeconfig = "/etc/kubernetes/kubelet.conf"
+
+// controlPlaneKubeconfigs are the kubeconfigs generated by kubeadm for the control plane
+var controlPlaneKubeconfigs = []string{
+	"/etc/kubernetes/admin.conf",
+	kubeletKubeconfig,
+	"/etc/kubernetes/controller-manager.conf",
+	"/etc/kubernetes/scheduler.conf",
+}
+
 // SkipAdditionalPreflights are additional preflights we skip depending on the runtime in use.
 var SkipAdditionalPreflights = map[string][]string{}
 
@@ -111,10 +131,9 @@ type Bootstrapper struct {
 	contextName string
 }
 
-// NewKubeadmBootstrapper creates a new kubeadm.Bootstrapper
-func NewKubeadmBootstrapper(api libmachine.API) (*Bootstrapper, error) {
-	name := config.GetMachineName()
-	h, err := api.Load(name)
+// NewKubeadmBootstrapper creates a new kubeadm.Bootstrapper for the named machine of the current profile
+func NewKubeadmBootstrapper(api libmachine.API, machineName string) (*Bootstrapper, error) {
+	h, err := api.Load(machineName)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting api client")
 	}
@@ -122,7 +141,7 @@ func NewKubeadmBootstrapper(api libmachine.API) (*Bootstrapper, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "command runner")
 	}
-	return &Bootstrapper{c: runner, contextName: name}, nil
+	return &Bootstrapper{c: runner, contextName: config.GetMachineName()}, nil
 }
 
 // GetKubeletStatus returns the kubelet status
@@ -144,6 +163,22 @@ func (k *Bootstrapper) GetKubeletStatus() (string, error) {
 	return state.Error.String(), nil
 }
 
+// StopKubelet stops the kubelet
+func (k *Bootstrapper) StopKubelet() error {
+	if err := k.c.Run("sudo systemctl stop kubelet"); err != nil {
+		return errors.Wrap(err, "stopping kubelet")
+	}
+	return nil
+}
+
+// StartKubelet starts the kubelet
+func (k *Bootstrapper) StartKubelet() error {
+	if err := k.c.Run("sudo systemctl start kubelet"); err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+	return nil
+}
+
 // GetAPIServerStatus returns the api-server status
 func (k *Bootstrapper) GetAPIServerStatus(ip net.IP, apiserverPort int) (string, error) {
 	url := fmt.Sprintf("https://%s:%d/healthz", ip, apiserverPort)
@@ -227,8 +262,8 @@ func (k *Bootstrapper) createCompatSymlinks() error {
 	return nil
 }
 
-// StartCluster starts the cluster
-func (k *Bootstrapper) StartCluster(k8s config.KubernetesConfig) error {
+// StartCluster starts the cluster, stopping kubeadm if the context is done first
+func (k *Bootstrapper) StartCluster(ctx context.Context, k8s config.KubernetesConfig) error {
 	start := time.Now()
 	glog.Infof("StartCluster: %+v", k8s)
 	defer func() {
@@ -266,8 +301,12 @@ func (k *Bootstrapper) StartCluster(k8s config.KubernetesConfig) error {
 
 	cmd := fmt.Sprintf("%s init --config %s %s --ignore-preflight-errors=%s",
 		invokeKubeadm(k8s.KubernetesVersion), yamlConfigPath, extraFlags, strings.Join(ignore, ","))
-	out, err := k.c.CombinedOutput(cmd)
+	rr, err := k.c.RunCmd(ctx, command.Cmd{Command: cmd})
 	if err != nil {
+		out := ""
+		if rr != nil {
+			out = rr.Output()
+		}
 		return errors.Wrapf(err, "cmd failed: %s\n%s\n", cmd, out)
 	}
 
@@ -312,15 +351,16 @@ func (k *Bootstrapper) adjustResourceLimits() error {
 	return nil
 }
 
-func addAddons(files *[]assets.CopyableFile, data interface{}) error {
+func addAddons(files *[]assets.CopyableFile, cfg config.KubernetesConfig) error {
 	// add addons to file list
 	// custom addons
 	if err := assets.AddMinikubeDirAssets(files); err != nil {
 		return errors.Wrap(err, "adding minikube dir assets")
 	}
 	// bundled addons
-	for _, addonBundle := range assets.Addons {
+	for name, addonBundle := range assets.Addons {
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
+			data := assets.GenerateAddonTemplateData(cfg, name)
 			for _, addon := range addonBundle.Assets {
 				if addon.IsTemplate() {
 					addonFile, err := addon.Evaluate(data)
@@ -364,11 +404,13 @@ func (k *Bootstrapper) WaitCluster(k8s config.KubernetesConfig, timeout time.Dur
 	// by a CNI plugin which is usually started after minikube has been brought
 	// up. Otherwise, minikube won't start, as "k8s-app" pods are not ready.
 	componentsOnly := k8s.NetworkPlugin == "cni"
-	out.T(out.WaitingPods, "Waiting for:")
+	if !out.JSON() {
+		out.T(out.WaitingPods, "Waiting for:")
+	}
 
 	// Wait until the apiserver can answer queries properly. We don't care if the apiserver
 	// pod shows up as registered, but need the webserver for all subsequent queries.
-	out.String(" apiserver")
+	reportWaiting("apiserver")
 	if err := k.waitForAPIServer(k8s); err != nil {
 		return errors.Wrap(err, "waiting for apiserver")
 	}
@@ -382,18 +424,30 @@ func (k *Bootstrapper) WaitCluster(k8s config.KubernetesConfig, timeout time.Dur
 		if componentsOnly && p.key != "component" { // skip component check if network plugin is cni
 			continue
 		}
-		out.String(" %s", p.name)
+		reportWaiting(p.name)
 		selector := labels.SelectorFromSet(labels.Set(map[string]string{p.key: p.value}))
 		if err := kapi.WaitForPodsWithLabelRunning(client, "kube-system", selector, timeout); err != nil {
 			return errors.Wrap(err, fmt.Sprintf("waiting for %s=%s", p.key, p.value))
 		}
 	}
-	out.Ln("")
+	if !out.JSON() {
+		out.Ln("")
+	}
 	return nil
 }
 
-// RestartCluster restarts the Kubernetes cluster configured by kubeadm
-func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
+// reportWaiting reports that WaitCluster is waiting for the named component, on the line started by WaitCluster,
+// or as an event of its own in JSON mode
+func reportWaiting(name string) {
+	if out.JSON() {
+		out.T(out.Waiting, "Waiting for {{.name}} ...", out.V{"name": name})
+		return
+	}
+	out.String(" %s", name)
+}
+
+// RestartCluster restarts the Kubernetes cluster configured by kubeadm, stopping kubeadm if the context is done first
+func (k *Bootstrapper) RestartCluster(ctx context.Context, k8s config.KubernetesConfig) error {
 	glog.Infof("RestartCluster start")
 	start := time.Now()
 	defer func() {
@@ -416,6 +470,18 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 		glog.Errorf("failed to create compat symlinks: %v", err)
 	}
 
+	// Clusters created before the control plane alias was introduced have kubeconfigs for another endpoint,
+	// which kubeadm refuses to overwrite.
+	endpoint := fmt.Sprintf("https://%s:%d", constants.ControlPlaneAlias, apiServerPort(k8s))
+	stale := k.staleKubeconfigs(endpoint)
+	migrate := len(stale) > 0
+	if migrate {
+		glog.Infof("regenerating kubeconfigs for %s: %v", endpoint, stale)
+		if err := k.c.Run(fmt.Sprintf("sudo rm -f %s", strings.Join(stale, " "))); err != nil {
+			return errors.Wrap(err, "removing kubeconfigs")
+		}
+	}
+
 	baseCmd := fmt.Sprintf("%s %s", invokeKubeadm(k8s.KubernetesVersion), phase)
 	cmds := []string{
 		fmt.Sprintf("%s phase certs all --config %s", baseCmd, yamlConfigPath),
@@ -426,7 +492,7 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 
 	// Run commands one at a time so that it is easier to root cause failures.
 	for _, cmd := range cmds {
-		if err := k.c.Run(cmd); err != nil {
+		if _, err := k.c.RunCmd(ctx, command.Cmd{Command: cmd}); err != nil {
 			return errors.Wrapf(err, "running cmd: %s", cmd)
 		}
 	}
@@ -434,8 +500,17 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 	if err := k.waitForAPIServer(k8s); err != nil {
 		return errors.Wrap(err, "waiting for apiserver")
 	}
+	if migrate {
+		client, err := k.client(k8s)
+		if err != nil {
+			return errors.Wrap(err, "client")
+		}
+		if err := updateClusterInfo(client, endpoint); err != nil {
+			glog.Warningf("unable to update cluster-info, nodes may fail to join: %v", err)
+		}
+	}
 	// restart the proxy and coredns
-	if err := k.c.Run(fmt.Sprintf("%s phase addon all --config %s", baseCmd, yamlConfigPath)); err != nil {
+	if _, err := k.c.RunCmd(ctx, command.Cmd{Command: fmt.Sprintf("%s phase addon all --config %s", baseCmd, yamlConfigPath)}); err != nil {
 		return errors.Wrapf(err, "addon phase")
 	}
 
@@ -445,6 +520,45 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 	return nil
 }
 
+// staleKubeconfigs returns the paths of the control plane kubeconfigs which are known to point at a server other than endpoint.
+// Kubeconfigs which are missing, or can not be read, are left for kubeadm to deal with.
+func (k *Bootstrapper) staleKubeconfigs(endpoint string) []string {
+	var stale []string
+	for _, path := range controlPlaneKubeconfigs {
+		out, err := k.c.CombinedOutput(fmt.Sprintf("sudo cat %s", path))
+		if err != nil {
+			glog.Infof("unable to read %s, skipping: %v", path, err)
+			continue
+		}
+		server := kubeconfigServer(out)
+		if server == "" || server == endpoint {
+			continue
+		}
+		glog.Infof("%s points at %s, expected %s", path, server, endpoint)
+		stale = append(stale, path)
+	}
+	return stale
+}
+
+// kubeconfigServer returns the server of the first cluster within a kubeconfig, or "" if there is none
+func kubeconfigServer(kubeconfig string) string {
+	for _, line := range strings.Split(kubeconfig, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "server:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "server:"))
+		}
+	}
+	return ""
+}
+
+// apiServerPort returns the port the apiserver listens on, defaulting to constants.APIServerPort
+func apiServerPort(k8s config.KubernetesConfig) int {
+	if k8s.NodePort <= 0 {
+		return constants.APIServerPort
+	}
+	return k8s.NodePort
+}
+
 // waitForAPIServer waits for the apiserver to start up
 func (k *Bootstrapper) waitForAPIServer(k8s config.KubernetesConfig) error {
 	start := time.Now()
@@ -507,8 +621,166 @@ func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	return nil
 }
 
-// PullImages downloads images that will be used by RestartCluster
-func (k *Bootstrapper) PullImages(k8s config.KubernetesConfig) error {
+// GenerateToken creates a short-lived bootstrap token, allowing additional nodes to join the cluster
+func (k *Bootstrapper) GenerateToken(k8s config.KubernetesConfig) (string, error) {
+	cmd := fmt.Sprintf("%s token create --ttl=%s", invokeKubeadm(k8s.KubernetesVersion), joinTokenTTL)
+	out, err := k.c.CombinedOutput(cmd)
+	if err != nil {
+		return "", errors.Wrapf(err, "cmd failed: %s\n%s\n", cmd, out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// JoinCluster configures the kubelet on this machine and joins it to the control plane described by k8s
+func (k *Bootstrapper) JoinCluster(k8s config.KubernetesConfig, n config.Node, token string) error {
+	start := time.Now()
+	glog.Infof("JoinCluster: %+v", n)
+	defer func() {
+		glog.Infof("JoinCluster complete in %s", time.Since(start))
+	}()
+
+	version, err := parseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	hash, err := caCertHash(localpath.MakeMiniPath("ca.crt"))
+	if err != nil {
+		return errors.Wrap(err, "ca hash")
+	}
+
+	// Clear any state left behind by a previous join, such as before the machine was restarted
+	if err := k.c.Run(`pgrep kubelet && sudo systemctl stop kubelet`); err != nil {
+		glog.Warningf("unable to stop kubelet: %s", err)
+	}
+	if err := k.c.Run(fmt.Sprintf("%s reset --force", invokeKubeadm(k8s.KubernetesVersion))); err != nil {
+		glog.Warningf("unable to reset node: %v", err)
+	}
+
+	r, err := k.configureNode(k8s, n)
+	if err != nil {
+		return err
+	}
+
+	ignore := []string{
+		fmt.Sprintf("DirAvailable-%s", strings.Replace(constants.GuestManifestsDir, "/", "-", -1)),
+		"FileAvailable--etc-kubernetes-kubelet.conf",
+		"FileAvailable--etc-kubernetes-bootstrap-kubelet.conf",
+		"Port-10250",
+		"Swap",
+	}
+	ignore = append(ignore, SkipAdditionalPreflights[r.Name()]...)
+	if version.LT(semver.MustParse("1.13.0")) {
+		ignore = append(ignore, "SystemVerification")
+	}
+
+	cmd := fmt.Sprintf("%s join %s:%d --token %s --discovery-token-ca-cert-hash sha256:%s --node-name %s --cri-socket %s --ignore-preflight-errors=%s",
+		invokeKubeadm(k8s.KubernetesVersion), constants.ControlPlaneAlias, apiServerPort(k8s), token, hash, n.Name, r.SocketPath(), strings.Join(ignore, ","))
+	out, err := k.c.CombinedOutput(cmd)
+	if err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s\n", cmd, out)
+	}
+	return nil
+}
+
+// RestartNode reconfigures and restarts the kubelet of a worker node which has already joined the control plane described by k8s
+func (k *Bootstrapper) RestartNode(k8s config.KubernetesConfig, n config.Node) error {
+	start := time.Now()
+	glog.Infof("RestartNode: %+v", n)
+	defer func() {
+		glog.Infof("RestartNode complete in %s", time.Since(start))
+	}()
+
+	if err := k.c.Run(fmt.Sprintf("sudo test -f %s", kubeletKubeconfig)); err != nil {
+		return errors.Wrapf(err, "%s is missing", kubeletKubeconfig)
+	}
+	if _, err := k.configureNode(k8s, n); err != nil {
+		return err
+	}
+	if err := k.c.Run("sudo systemctl restart kubelet"); err != nil {
+		return errors.Wrap(err, "restart kubelet")
+	}
+	return nil
+}
+
+// configureNode writes the kubelet configuration of a worker node, returning the container runtime it uses
+func (k *Bootstrapper) configureNode(k8s config.KubernetesConfig, n config.Node) (cruntime.Manager, error) {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Socket: k8s.CRISocket})
+	if err != nil {
+		return nil, errors.Wrap(err, "runtime")
+	}
+
+	// The kubelet on a worker uses its own name and address, but otherwise shares the control plane configuration
+	wk := k8s
+	wk.NodeName = n.Name
+	wk.NodeIP = n.IP
+	kubeletCfg, err := NewKubeletConfig(wk, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating kubelet config")
+	}
+	kubeletService, err := NewKubeletService(wk)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating kubelet service")
+	}
+
+	if err := transferBinaries(wk, k.c); err != nil {
+		return nil, errors.Wrap(err, "downloading binaries")
+	}
+	ca, err := assets.NewFileAsset(localpath.MakeMiniPath("ca.crt"), constants.GuestCertsDir, "ca.crt", "0644")
+	if err != nil {
+		return nil, errors.Wrap(err, "ca asset")
+	}
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget(kubeletCfg, constants.KubeletSystemdConfFile, "0640"),
+		assets.NewMemoryAssetTarget(kubeletService, constants.KubeletServiceFile, "0640"),
+		ca,
+	}
+	if wk.EnableDefaultCNI {
+		files = append(files, assets.NewMemoryAssetTarget([]byte(defaultCNIConfig), constants.DefaultCNIConfigPath, "0644"))
+	}
+	if err := k.c.CopyAll(files); err != nil {
+		return nil, errors.Wrapf(err, "copy")
+	}
+	if err := addHostAlias(k.c, constants.ControlPlaneAlias, k8s.NodeIP); err != nil {
+		return nil, errors.Wrap(err, "control plane alias")
+	}
+	if err := k.c.Run("sudo systemctl daemon-reload"); err != nil {
+		return nil, errors.Wrap(err, "daemon-reload")
+	}
+	return r, nil
+}
+
+// caCertHash returns the hex encoded SHA-256 hash of the public key of a CA certificate, as expected by kubeadm join
+func caCertHash(caPath string) (string, error) {
+	data, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		return "", errors.Wrap(err, "read")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", fmt.Errorf("no PEM data found in %s", caPath)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "parse")
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// addHostAlias maps a hostname to an IP within the guest's /etc/hosts, replacing any previous mapping
+func addHostAlias(c command.Runner, name string, ip string) error {
+	record := fmt.Sprintf("%s\t%s", ip, name)
+	cmd := fmt.Sprintf(`sudo sed -i '/\s%s$/d' /etc/hosts && printf '%s\n' | sudo tee -a /etc/hosts`,
+		strings.Replace(name, ".", `\.`, -1), record)
+	if err := c.Run(cmd); err != nil {
+		return errors.Wrapf(err, "update /etc/hosts")
+	}
+	return nil
+}
+
+// PullImages downloads images that will be used by RestartCluster, giving up if the context is done first
+func (k *Bootstrapper) PullImages(ctx context.Context, k8s config.KubernetesConfig) error {
 	version, err := parseKubernetesVersion(k8s.KubernetesVersion)
 	if err != nil {
 		return errors.Wrap(err, "parsing kubernetes version")
@@ -518,7 +790,7 @@ func (k *Bootstrapper) PullImages(k8s config.KubernetesConfig) error {
 	}
 
 	cmd := fmt.Sprintf("%s config images pull --config %s", invokeKubeadm(k8s.KubernetesVersion), yamlConfigPath)
-	if err := k.c.Run(cmd); err != nil {
+	if _, err := k.c.RunCmd(ctx, command.Cmd{Command: cmd}); err != nil {
 		return errors.Wrapf(err, "running cmd: %s", cmd)
 	}
 	return nil
@@ -548,6 +820,11 @@ func NewKubeletConfig(k8s config.KubernetesConfig, r cruntime.Manager) ([]byte,
 	if k8s.NetworkPlugin != "" {
 		extraOpts["network-plugin"] = k8s.NetworkPlugin
 	}
+	// Worker nodes must register using their own name and address, rather than those of the control plane
+	if k8s.NodeName != "" && k8s.NodeName != constants.DefaultNodeName {
+		extraOpts["hostname-override"] = k8s.NodeName
+		extraOpts["node-ip"] = k8s.NodeIP
+	}
 
 	podInfraContainerImage, _ := images.CachedImages(k8s.ImageRepository, k8s.KubernetesVersion)
 	if _, ok := extraOpts["pod-infra-container-image"]; !ok && k8s.ImageRepository != "" && podInfraContainerImage != "" {
@@ -585,7 +862,7 @@ func NewKubeletConfig(k8s config.KubernetesConfig, r cruntime.Manager) ([]byte,
 func (k *Bootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 	_, images := images.CachedImages(cfg.ImageRepository, cfg.KubernetesVersion)
 	if cfg.ShouldLoadCachedImages {
-		if err := machine.LoadImages(k.c, images, constants.ImageCacheDir); err != nil {
+		if err := machine.LoadImages(k.c, cfg, images, constants.ImageCacheDir); err != nil {
 			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
 		}
 	}
@@ -619,13 +896,15 @@ func (k *Bootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 		return errors.Wrap(err, "downloading binaries")
 	}
 	files := configFiles(cfg, kubeadmCfg, kubeletCfg, kubeletService)
-	if err := addAddons(&files, assets.GenerateTemplateData(cfg)); err != nil {
+	if err := addAddons(&files, cfg); err != nil {
 		return errors.Wrap(err, "adding addons")
 	}
-	for _, f := range files {
-		if err := k.c.Copy(f); err != nil {
-			return errors.Wrapf(err, "copy")
-		}
+	if err := k.c.CopyAll(files); err != nil {
+		return errors.Wrapf(err, "copy")
+	}
+
+	if err := addHostAlias(k.c, constants.ControlPlaneAlias, cfg.NodeIP); err != nil {
+		return errors.Wrap(err, "control plane alias")
 	}
 
 	if err := k.c.Run(`sudo systemctl daemon-reload && sudo systemctl start kubelet`); err != nil {
@@ -671,40 +950,38 @@ func generateConfig(k8s config.KubernetesConfig, r cruntime.Manager) ([]byte, er
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
 
-	// In case of no port assigned, use util.APIServerPort
-	nodePort := k8s.NodePort
-	if nodePort <= 0 {
-		nodePort = constants.APIServerPort
-	}
+	nodePort := apiServerPort(k8s)
 
 	opts := struct {
-		CertDir           string
-		ServiceCIDR       string
-		PodSubnet         string
-		AdvertiseAddress  string
-		APIServerPort     int
-		KubernetesVersion string
-		EtcdDataDir       string
-		NodeName          string
-		CRISocket         string
-		ImageRepository   string
-		ExtraArgs         []ComponentExtraArgs
-		FeatureArgs       map[string]bool
-		NoTaintMaster     bool
+		CertDir             string
+		ServiceCIDR         string
+		PodSubnet           string
+		AdvertiseAddress    string
+		ControlPlaneAddress string
+		APIServerPort       int
+		KubernetesVersion   string
+		EtcdDataDir         string
+		NodeName            string
+		CRISocket           string
+		ImageRepository     string
+		ExtraArgs           []ComponentExtraArgs
+		FeatureArgs         map[string]bool
+		NoTaintMaster       bool
 	}{
-		CertDir:           constants.GuestCertsDir,
-		ServiceCIDR:       util.DefaultServiceCIDR,
-		PodSubnet:         k8s.ExtraOptions.Get("pod-network-cidr", Kubeadm),
-		AdvertiseAddress:  k8s.NodeIP,
-		APIServerPort:     nodePort,
-		KubernetesVersion: k8s.KubernetesVersion,
-		EtcdDataDir:       etcdDataDir(),
-		NodeName:          k8s.NodeName,
-		CRISocket:         r.SocketPath(),
-		ImageRepository:   k8s.ImageRepository,
-		ExtraArgs:         extraComponentConfig,
-		FeatureArgs:       kubeadmFeatureArgs,
-		NoTaintMaster:     false, // That does not work with k8s 1.12+
+		CertDir:             constants.GuestCertsDir,
+		ServiceCIDR:         util.DefaultServiceCIDR,
+		PodSubnet:           k8s.ExtraOptions.Get("pod-network-cidr", Kubeadm),
+		AdvertiseAddress:    k8s.NodeIP,
+		ControlPlaneAddress: constants.ControlPlaneAlias,
+		APIServerPort:       nodePort,
+		KubernetesVersion:   k8s.KubernetesVersion,
+		EtcdDataDir:         etcdDataDir(),
+		NodeName:            k8s.NodeName,
+		CRISocket:           r.SocketPath(),
+		ImageRepository:     k8s.ImageRepository,
+		ExtraArgs:           extraComponentConfig,
+		FeatureArgs:         kubeadmFeatureArgs,
+		NoTaintMaster:       false, // That does not work with k8s 1.12+
 	}
 
 	if k8s.ServiceCIDR != "" {
@@ -764,23 +1041,28 @@ func invokeKubeadm(version string) string {
 	return fmt.Sprintf("sudo env PATH=%s:$PATH kubeadm", binRoot(version))
 }
 
-// transferBinaries transfers all required Kubernetes binaries
+// transferBinaries transfers all required Kubernetes binaries, unless they are already up to date
 func transferBinaries(cfg config.KubernetesConfig, c command.Runner) error {
 	var g errgroup.Group
-	for _, name := range constants.KubeadmBinaries {
-		name := name
+	files := make([]assets.CopyableFile, len(constants.KubeadmBinaries))
+	for i, name := range constants.KubeadmBinaries {
+		i, name := i, name
 		g.Go(func() error {
 			src, err := machine.CacheBinary(name, cfg.KubernetesVersion, "linux", runtime.GOARCH)
 			if err != nil {
 				return errors.Wrapf(err, "downloading %s", name)
 			}
 
-			dst := path.Join(binRoot(cfg.KubernetesVersion), name)
-			if err := machine.CopyBinary(c, src, dst); err != nil {
-				return errors.Wrapf(err, "copybinary %s -> %s", src, dst)
+			f, err := assets.NewFileAsset(src, binRoot(cfg.KubernetesVersion), name, "0755")
+			if err != nil {
+				return errors.Wrapf(err, "new file asset %s", src)
 			}
+			files[i] = f
 			return nil
 		})
 	}
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return c.CopyAll(files)
 }
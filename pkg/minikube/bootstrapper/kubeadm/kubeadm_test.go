@@ -17,12 +17,15 @@ limitations under the License.
 package kubeadm
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -105,6 +108,24 @@ Wants=docker.socket
 ExecStart=
 ExecStart=/var/lib/minikube/binaries/v1.16.0/kubelet --authorization-mode=Webhook --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --cgroup-driver=cgroupfs --client-ca-file=/var/lib/minikube/certs/ca.crt --cluster-dns=10.96.0.10 --cluster-domain=cluster.local --container-runtime=docker --fail-swap-on=false --hostname-override=minikube --kubeconfig=/etc/kubernetes/kubelet.conf --pod-infra-container-image=docker-proxy-image.io/google_containers/pause:3.1 --pod-manifest-path=/etc/kubernetes/manifests
 
+[Install]
+`,
+		},
+		{
+			description: "docker worker node",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: constants.DefaultKubernetesVersion,
+				NodeName:          "minikube-m02",
+				ContainerRuntime:  "docker",
+			},
+			expected: `[Unit]
+Wants=docker.socket
+
+[Service]
+ExecStart=
+ExecStart=/var/lib/minikube/binaries/v1.16.0/kubelet --authorization-mode=Webhook --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --cgroup-driver=cgroupfs --client-ca-file=/var/lib/minikube/certs/ca.crt --cluster-dns=10.96.0.10 --cluster-domain=cluster.local --container-runtime=docker --fail-swap-on=false --hostname-override=minikube-m02 --kubeconfig=/etc/kubernetes/kubelet.conf --node-ip=192.168.1.101 --pod-manifest-path=/etc/kubernetes/manifests
+
 [Install]
 `,
 		},
@@ -277,3 +298,52 @@ func TestGenerateConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestStaleKubeconfigs(t *testing.T) {
+	kubeconfig := func(server string) string {
+		return fmt.Sprintf("apiVersion: v1\nclusters:\n- cluster:\n    certificate-authority-data: abc\n    server: %s\n  name: kubernetes\n", server)
+	}
+	endpoint := fmt.Sprintf("https://%s:%d", constants.ControlPlaneAlias, constants.APIServerPort)
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo cat /etc/kubernetes/admin.conf":              kubeconfig("https://192.168.39.2:8443"),
+		"sudo cat /etc/kubernetes/kubelet.conf":            kubeconfig(endpoint),
+		"sudo cat /etc/kubernetes/controller-manager.conf": "",
+		// scheduler.conf can not be read, so it is left alone
+	})
+	k := &Bootstrapper{c: f}
+
+	got := k.staleKubeconfigs(endpoint)
+	want := []string{"/etc/kubernetes/admin.conf"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("staleKubeconfigs() = %v, want %v", got, want)
+	}
+}
+
+func TestAPIServerPort(t *testing.T) {
+	var tests = []struct {
+		port int
+		want int
+	}{
+		{port: 0, want: constants.APIServerPort},
+		{port: -1, want: constants.APIServerPort},
+		{port: 6443, want: 6443},
+	}
+	for _, tc := range tests {
+		if got := apiServerPort(config.KubernetesConfig{NodePort: tc.port}); got != tc.want {
+			t.Errorf("apiServerPort(%d) = %d, want %d", tc.port, got, tc.want)
+		}
+	}
+}
+
+func TestStartClusterCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	k := &Bootstrapper{c: command.NewFakeCommandRunner()}
+
+	err := k.StartCluster(ctx, config.KubernetesConfig{KubernetesVersion: constants.DefaultKubernetesVersion, ContainerRuntime: "docker"})
+	if errors.Cause(err) != context.Canceled {
+		t.Errorf("StartCluster() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	// WARNING: Do not use path/filepath in this package unless you want bizarre Windows paths
+	"path"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+const (
+	// snapshotFile is the name of a snapshot within the etcd data dir, the only host directory shared with the etcd container
+	snapshotFile = "minikube-snapshot.db"
+	// restoreDir is where a snapshot is restored to within the etcd data dir, before it replaces the data dir
+	restoreDir = "minikube-restore"
+)
+
+// etcdctl returns an etcdctl command line which talks to the local etcd member
+func etcdctl(args string) string {
+	certs := path.Join(constants.GuestCertsDir, "etcd")
+	return fmt.Sprintf("ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 --cacert=%s --cert=%s --key=%s %s",
+		path.Join(certs, "ca.crt"), path.Join(certs, "healthcheck-client.crt"), path.Join(certs, "healthcheck-client.key"), args)
+}
+
+// etcdContainer returns the runtime and the ID of the running etcd container
+func (k *Bootstrapper) etcdContainer(k8s config.KubernetesConfig) (cruntime.Manager, string, error) {
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Socket: k8s.CRISocket, Runner: k.c})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "runtime")
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running, Name: "etcd"})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "list etcd")
+	}
+	if len(ids) == 0 {
+		return nil, "", fmt.Errorf("etcd is not running")
+	}
+	return cr, ids[0], nil
+}
+
+// SnapshotCluster saves a snapshot of the etcd database to a file on the host
+func (k *Bootstrapper) SnapshotCluster(k8s config.KubernetesConfig, dst string) error {
+	cr, id, err := k.etcdContainer(k8s)
+	if err != nil {
+		return err
+	}
+
+	guestPath := path.Join(etcdDataDir(), snapshotFile)
+	defer func() {
+		if err := k.c.Run(fmt.Sprintf("sudo rm -f %s", guestPath)); err != nil {
+			glog.Warningf("unable to remove %s: %v", guestPath, err)
+		}
+	}()
+
+	cmd := cr.ContainerExecCmd(id, etcdctl(fmt.Sprintf("snapshot save %s", guestPath)))
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s", cmd, out)
+	}
+	return k.download(guestPath, dst)
+}
+
+// download copies a file from the guest to the host, verifying its checksum
+func (k *Bootstrapper) download(src string, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if err := k.c.ReadFile(src, io.MultiWriter(f, h)); err != nil {
+		return errors.Wrapf(err, "reading %s", src)
+	}
+
+	out, err := k.c.CombinedOutput(fmt.Sprintf("sudo sha256sum %s", src))
+	if err != nil {
+		return errors.Wrapf(err, "checksum %s", src)
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return fmt.Errorf("unexpected sha256sum output: %q", out)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != fields[0] {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", src, got, fields[0])
+	}
+	return nil
+}
+
+// RestoreCluster replaces the etcd database with a snapshot from the host, and restarts the control plane
+func (k *Bootstrapper) RestoreCluster(k8s config.KubernetesConfig, src string) error {
+	cr, id, err := k.etcdContainer(k8s)
+	if err != nil {
+		return err
+	}
+
+	f, err := assets.NewFileAsset(src, etcdDataDir(), snapshotFile, "0600")
+	if err != nil {
+		return errors.Wrap(err, "snapshot")
+	}
+	guestPath := path.Join(etcdDataDir(), snapshotFile)
+	defer func() {
+		if err := k.c.Run(fmt.Sprintf("sudo rm -f %s", guestPath)); err != nil {
+			glog.Warningf("unable to remove %s: %v", guestPath, err)
+		}
+	}()
+	if err := k.c.Copy(f); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+
+	// etcdctl is only available within the etcd container, so restore into a directory it can see
+	restore := path.Join(etcdDataDir(), restoreDir)
+	if err := k.c.Run(fmt.Sprintf("sudo rm -rf %s", restore)); err != nil {
+		return errors.Wrap(err, "cleanup")
+	}
+	peerURL := fmt.Sprintf("https://%s:2380", k8s.NodeIP)
+	args := fmt.Sprintf("snapshot restore %s --data-dir=%s --name=%s --initial-cluster=%s=%s --initial-advertise-peer-urls=%s",
+		guestPath, restore, k8s.NodeName, k8s.NodeName, peerURL, peerURL)
+	cmd := cr.ContainerExecCmd(id, etcdctl(args))
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s", cmd, out)
+	}
+
+	// Stop the control plane, so that nothing writes to etcd while its data is replaced
+	if err := k.StopKubelet(); err != nil {
+		return err
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+	if err != nil {
+		return errors.Wrap(err, "list running")
+	}
+	if err := cr.StopContainers(ids); err != nil {
+		return errors.Wrap(err, "stop containers")
+	}
+
+	old := etcdDataDir() + ".old"
+	cmd = fmt.Sprintf("sudo rm -rf %s && sudo mv %s %s && sudo mv %s %s && sudo rm -rf %s",
+		old, etcdDataDir(), old, path.Join(old, restoreDir), etcdDataDir(), old)
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s", cmd, out)
+	}
+
+	if err := k.StartKubelet(); err != nil {
+		return err
+	}
+	return k.RestartCluster(context.Background(), k8s)
+}
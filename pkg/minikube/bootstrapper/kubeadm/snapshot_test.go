@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestSnapshotCluster(t *testing.T) {
+	data := "etcd snapshot"
+	sum := sha256.Sum256([]byte(data))
+
+	var tests = []struct {
+		description string
+		checksum    string
+		shouldErr   bool
+	}{
+		{description: "valid checksum", checksum: hex.EncodeToString(sum[:])},
+		{description: "checksum mismatch", checksum: "deadbeef", shouldErr: true},
+	}
+
+	guestPath := "/var/lib/minikube/etcd/minikube-snapshot.db"
+	save := fmt.Sprintf(`docker exec abc0 /bin/sh -c %q`, etcdctl("snapshot save "+guestPath))
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "snapshot")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			f := command.NewFakeCommandRunner()
+			f.SetCommandToOutput(map[string]string{
+				`docker ps -a --filter="name=k8s_etcd" --filter="status=running" --filter="status=paused" --format="{{.ID}}"`: "abc0\n",
+				save:                          "Snapshot saved at " + guestPath,
+				"sudo cat " + guestPath:       data,
+				"sudo sha256sum " + guestPath: test.checksum + "  " + guestPath,
+				"sudo rm -f " + guestPath:     "",
+			})
+
+			k := &Bootstrapper{c: f}
+			dst := filepath.Join(dir, "etcd.db")
+			err = k.SnapshotCluster(config.KubernetesConfig{ContainerRuntime: "docker"}, dst)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("SnapshotCluster: %v", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("SnapshotCluster succeeded, expected error")
+			}
+			if test.shouldErr {
+				return
+			}
+
+			got, err := ioutil.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != data {
+				t.Errorf("snapshot = %q, want %q", got, data)
+			}
+		})
+	}
+}
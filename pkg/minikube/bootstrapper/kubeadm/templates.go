@@ -31,7 +31,7 @@ kind: MasterConfiguration
 api:
   advertiseAddress: {{.AdvertiseAddress}}
   bindPort: {{.APIServerPort}}
-  controlPlaneEndpoint: localhost
+  controlPlaneEndpoint: {{.ControlPlaneAddress}}
 kubernetesVersion: {{.KubernetesVersion}}
 certificatesDir: {{.CertDir}}
 networking:
@@ -78,7 +78,7 @@ kind: ClusterConfiguration
 {{end -}}
 certificatesDir: {{.CertDir}}
 clusterName: kubernetes
-controlPlaneEndpoint: localhost:{{.APIServerPort}}
+controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 etcd:
   local:
     dataDir: {{.EtcdDataDir}}
@@ -130,7 +130,7 @@ kind: ClusterConfiguration
 {{end -}}{{end -}}
 certificatesDir: {{.CertDir}}
 clusterName: kubernetes
-controlPlaneEndpoint: localhost:{{.APIServerPort}}
+controlPlaneEndpoint: {{.ControlPlaneAddress}}:{{.APIServerPort}}
 dns:
   type: CoreDNS
 etcd:
@@ -153,7 +153,7 @@ evictionHard:
 
 // kubeletSystemdTemplate hosts the override kubelet flags, written to constants.KubeletSystemdConfFile
 var kubeletSystemdTemplate = template.Must(template.New("kubeletSystemdTemplate").Parse(`[Unit]
-{{if or (eq .ContainerRuntime "cri-o") (eq .ContainerRuntime "cri")}}Wants=crio.service{{else if eq .ContainerRuntime "containerd"}}Wants=containerd.service{{else}}Wants=docker.socket{{end}}
+{{if or (eq .ContainerRuntime "cri-o") (eq .ContainerRuntime "cri") (eq .ContainerRuntime "podman")}}Wants=crio.service{{else if eq .ContainerRuntime "containerd"}}Wants=containerd.service{{else}}Wants=docker.socket{{end}}
 
 [Service]
 ExecStart=
@@ -25,6 +25,7 @@ import (
 	rbac "k8s.io/api/rbac/v1beta1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -67,3 +68,28 @@ func elevateKubeSystemPrivileges(client kubernetes.Interface) error {
 	glog.Infof("duration metric: took %s to wait for elevateKubeSystemPrivileges.", time.Since(start))
 	return nil
 }
+
+// updateClusterInfo points the cluster-info ConfigMap, which joining nodes use for discovery, at the given server.
+// The bootstrap signer will re-sign the updated content on its own.
+func updateClusterInfo(client kubernetes.Interface, server string) error {
+	cm, err := client.CoreV1().ConfigMaps(meta.NamespacePublic).Get("cluster-info", meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "get cluster-info")
+	}
+	kc, err := clientcmd.Load([]byte(cm.Data["kubeconfig"]))
+	if err != nil {
+		return errors.Wrap(err, "load kubeconfig")
+	}
+	for _, c := range kc.Clusters {
+		c.Server = server
+	}
+	data, err := clientcmd.Write(*kc)
+	if err != nil {
+		return errors.Wrap(err, "write kubeconfig")
+	}
+	cm.Data["kubeconfig"] = string(data)
+	if _, err := client.CoreV1().ConfigMaps(meta.NamespacePublic).Update(cm); err != nil {
+		return errors.Wrap(err, "update cluster-info")
+	}
+	return nil
+}
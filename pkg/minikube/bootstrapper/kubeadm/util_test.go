@@ -0,0 +1,68 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const staleClusterInfo = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://localhost:8443
+  name: ""
+contexts: []
+current-context: ""
+kind: Config
+preferences: {}
+users: []
+`
+
+func TestUpdateClusterInfo(t *testing.T) {
+	client := fake.NewSimpleClientset(&core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Name: "cluster-info", Namespace: meta.NamespacePublic},
+		Data:       map[string]string{"kubeconfig": staleClusterInfo},
+	})
+
+	want := "https://control-plane.minikube.internal:8443"
+	if err := updateClusterInfo(client, want); err != nil {
+		t.Fatalf("updateClusterInfo: %v", err)
+	}
+
+	cm, err := client.CoreV1().ConfigMaps(meta.NamespacePublic).Get("cluster-info", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	kc, err := clientcmd.Load([]byte(cm.Data["kubeconfig"]))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for name, c := range kc.Clusters {
+		if c.Server != want {
+			t.Errorf("cluster %q server = %q, want %q", name, c.Server, want)
+		}
+		if string(c.CertificateAuthorityData) != "ca" {
+			t.Errorf("cluster %q CA data was not preserved: %q", name, c.CertificateAuthorityData)
+		}
+	}
+}
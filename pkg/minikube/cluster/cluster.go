@@ -86,9 +86,10 @@ func CacheISO(config cfg.MachineConfig) error {
 
 // StartHost starts a host VM.
 func StartHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	machineName := config.MachineName()
+	exists, err := api.Exists(machineName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "exists: %s", cfg.GetMachineName())
+		return nil, errors.Wrapf(err, "exists: %s", machineName)
 	}
 	if !exists {
 		glog.Infoln("Machine does not exist... provisioning new machine")
@@ -98,12 +99,12 @@ func StartHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error)
 
 	glog.Infoln("Skipping create...Using existing machine configuration")
 
-	h, err := api.Load(cfg.GetMachineName())
+	h, err := api.Load(machineName)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading existing host. Please try running [minikube delete], then run [minikube start] again.")
 	}
 
-	if exists && cfg.GetMachineName() == constants.DefaultMachineName {
+	if exists && machineName == constants.DefaultMachineName {
 		out.T(out.Tip, "Tip: Use 'minikube start -p <name>' to create a new cluster, or 'minikube delete' to delete this one.")
 	}
 
@@ -114,9 +115,9 @@ func StartHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error)
 	}
 
 	if s == state.Running {
-		out.T(out.Running, `Using the running {{.driver_name}} "{{.profile_name}}" VM ...`, out.V{"driver_name": config.VMDriver, "profile_name": cfg.GetMachineName()})
+		out.T(out.Running, `Using the running {{.driver_name}} "{{.profile_name}}" VM ...`, out.V{"driver_name": config.VMDriver, "profile_name": machineName})
 	} else {
-		out.T(out.Restarting, `Starting existing {{.driver_name}} VM for "{{.profile_name}}" ...`, out.V{"driver_name": config.VMDriver, "profile_name": cfg.GetMachineName()})
+		out.T(out.Restarting, `Starting existing {{.driver_name}} VM for "{{.profile_name}}" ...`, out.V{"driver_name": config.VMDriver, "profile_name": machineName})
 		if err := h.Driver.Start(); err != nil {
 			return nil, errors.Wrap(err, "start")
 		}
@@ -236,7 +237,7 @@ func trySSHPowerOff(h *host.Host) error {
 		return nil
 	}
 
-	out.T(out.Shutdown, `Powering off "{{.profile_name}}" via SSH ...`, out.V{"profile_name": cfg.GetMachineName()})
+	out.T(out.Shutdown, `Powering off "{{.profile_name}}" via SSH ...`, out.V{"profile_name": h.Name})
 	out, err := h.RunSSHCommand("sudo poweroff")
 	// poweroff always results in an error, since the host disconnects.
 	glog.Infof("poweroff result: out=%s, err=%v", out, err)
@@ -244,13 +245,13 @@ func trySSHPowerOff(h *host.Host) error {
 }
 
 // StopHost stops the host VM, saving state to disk.
-func StopHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+func StopHost(api libmachine.API, machineName string) error {
+	host, err := api.Load(machineName)
 	if err != nil {
 		return errors.Wrapf(err, "load")
 	}
 
-	out.T(out.Stopping, `Stopping "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": cfg.GetMachineName(), "driver_name": host.DriverName})
+	out.T(out.Stopping, `Stopping "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": machineName, "driver_name": host.DriverName})
 	if host.DriverName == constants.DriverHyperv {
 		glog.Infof("As there are issues with stopping Hyper-V VMs using API, trying to shut down using SSH")
 		if err := trySSHPowerOff(host); err != nil {
@@ -263,14 +264,14 @@ func StopHost(api libmachine.API) error {
 		if ok && alreadyInStateError.State == state.Stopped {
 			return nil
 		}
-		return &retry.RetriableError{Err: errors.Wrapf(err, "Stop: %s", cfg.GetMachineName())}
+		return &retry.RetriableError{Err: errors.Wrapf(err, "Stop: %s", machineName)}
 	}
 	return nil
 }
 
 // DeleteHost deletes the host VM.
-func DeleteHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+func DeleteHost(api libmachine.API, machineName string) error {
+	host, err := api.Load(machineName)
 	if err != nil {
 		return errors.Wrap(err, "load")
 	}
@@ -281,27 +282,27 @@ func DeleteHost(api libmachine.API) error {
 		}
 	}
 
-	out.T(out.DeletingHost, `Deleting "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": cfg.GetMachineName(), "driver_name": host.DriverName})
+	out.T(out.DeletingHost, `Deleting "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": machineName, "driver_name": host.DriverName})
 	if err := host.Driver.Remove(); err != nil {
 		return errors.Wrap(err, "host remove")
 	}
-	if err := api.Remove(cfg.GetMachineName()); err != nil {
+	if err := api.Remove(machineName); err != nil {
 		return errors.Wrap(err, "api remove")
 	}
 	return nil
 }
 
 // GetHostStatus gets the status of the host VM.
-func GetHostStatus(api libmachine.API) (string, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+func GetHostStatus(api libmachine.API, machineName string) (string, error) {
+	exists, err := api.Exists(machineName)
 	if err != nil {
-		return "", errors.Wrapf(err, "%s exists", cfg.GetMachineName())
+		return "", errors.Wrapf(err, "%s exists", machineName)
 	}
 	if !exists {
 		return state.None.String(), nil
 	}
 
-	host, err := api.Load(cfg.GetMachineName())
+	host, err := api.Load(machineName)
 	if err != nil {
 		return "", errors.Wrapf(err, "load")
 	}
@@ -342,7 +343,8 @@ func engineOptions(config cfg.MachineConfig) *engine.Options {
 	return &o
 }
 
-type hostInfo struct {
+// HostInfo describes the resources of the host
+type HostInfo struct {
 	Memory   int
 	CPUs     int
 	DiskSize int
@@ -352,7 +354,8 @@ func megs(bytes uint64) int {
 	return int(bytes / 1024 / 1024)
 }
 
-func getHostInfo() (*hostInfo, error) {
+// GetHostInfo returns the resources of the host, with the memory and disk size in MB
+func GetHostInfo() (*HostInfo, error) {
 	i, err := cpu.Info()
 	if err != nil {
 		glog.Warningf("Unable to get CPU info: %v", err)
@@ -369,7 +372,7 @@ func getHostInfo() (*hostInfo, error) {
 		return nil, err
 	}
 
-	var info hostInfo
+	var info HostInfo
 	info.CPUs = len(i)
 	info.Memory = megs(v.Total)
 	info.DiskSize = megs(d.Total)
@@ -420,7 +423,7 @@ func createHost(api libmachine.API, config cfg.MachineConfig) (*host.Host, error
 	if !localDriver(config.VMDriver) {
 		out.T(out.StartingVM, "Creating {{.driver_name}} VM (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"driver_name": config.VMDriver, "number_of_cpus": config.CPUs, "memory_size": config.Memory, "disk_size": config.DiskSize})
 	} else {
-		info, err := getHostInfo()
+		info, err := GetHostInfo()
 		if err == nil {
 			out.T(out.StartingNone, "Running on localhost (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"number_of_cpus": info.CPUs, "memory_size": info.Memory, "disk_size": info.DiskSize})
 		}
@@ -569,8 +572,7 @@ func CheckIfHostExistsAndLoad(api libmachine.API, machineName string) (*host.Hos
 }
 
 // CreateSSHShell creates a new SSH shell / client
-func CreateSSHShell(api libmachine.API, args []string) error {
-	machineName := cfg.GetMachineName()
+func CreateSSHShell(api libmachine.API, machineName string, args []string) error {
 	host, err := CheckIfHostExistsAndLoad(api, machineName)
 	if err != nil {
 		return errors.Wrap(err, "host exists and load")
@@ -595,7 +597,7 @@ func CreateSSHShell(api libmachine.API, args []string) error {
 // EnsureMinikubeRunningOrExit checks that minikube has a status available and that
 // the status is `Running`, otherwise it will exit
 func EnsureMinikubeRunningOrExit(api libmachine.API, exitStatus int) {
-	s, err := GetHostStatus(api)
+	s, err := GetHostStatus(api, cfg.GetMachineName())
 	if err != nil {
 		exit.WithError("Error getting machine status", err)
 	}
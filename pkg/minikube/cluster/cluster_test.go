@@ -244,7 +244,7 @@ func TestStartHostConfig(t *testing.T) {
 func TestStopHostError(t *testing.T) {
 	RegisterMockDriver(t)
 	api := tests.NewMockAPI(t)
-	if err := StopHost(api); err == nil {
+	if err := StopHost(api, config.GetMachineName()); err == nil {
 		t.Fatal("An error should be thrown when stopping non-existing machine.")
 	}
 }
@@ -257,7 +257,7 @@ func TestStopHost(t *testing.T) {
 		t.Errorf("createHost failed: %v", err)
 	}
 
-	if err := StopHost(api); err != nil {
+	if err := StopHost(api, config.GetMachineName()); err != nil {
 		t.Fatal("An error should be thrown when stopping non-existing machine.")
 	}
 	if s, _ := h.Driver.GetState(); s != state.Stopped {
@@ -272,7 +272,7 @@ func TestDeleteHost(t *testing.T) {
 		t.Errorf("createHost failed: %v", err)
 	}
 
-	if err := DeleteHost(api); err != nil {
+	if err := DeleteHost(api, config.GetMachineName()); err != nil {
 		t.Fatalf("Unexpected error deleting host: %v", err)
 	}
 }
@@ -288,7 +288,7 @@ func TestDeleteHostErrorDeletingVM(t *testing.T) {
 	d := &tests.MockDriver{RemoveError: true, T: t}
 	h.Driver = d
 
-	if err := DeleteHost(api); err == nil {
+	if err := DeleteHost(api, config.GetMachineName()); err == nil {
 		t.Fatal("Expected error deleting host.")
 	}
 }
@@ -301,7 +301,7 @@ func TestDeleteHostErrorDeletingFiles(t *testing.T) {
 		t.Errorf("createHost failed: %v", err)
 	}
 
-	if err := DeleteHost(api); err == nil {
+	if err := DeleteHost(api, config.GetMachineName()); err == nil {
 		t.Fatal("Expected error deleting host.")
 	}
 }
@@ -311,7 +311,7 @@ func TestGetHostStatus(t *testing.T) {
 	api := tests.NewMockAPI(t)
 
 	checkState := func(expected string) {
-		s, err := GetHostStatus(api)
+		s, err := GetHostStatus(api, config.GetMachineName())
 		if err != nil {
 			t.Fatalf("Unexpected error getting status: %v", err)
 		}
@@ -328,7 +328,7 @@ func TestGetHostStatus(t *testing.T) {
 
 	checkState(state.Running.String())
 
-	if err := StopHost(api); err != nil {
+	if err := StopHost(api, config.GetMachineName()); err != nil {
 		t.Errorf("StopHost failed: %v", err)
 	}
 	checkState(state.Stopped.String())
@@ -419,7 +419,7 @@ func TestCreateSSHShell(t *testing.T) {
 	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
 
 	cliArgs := []string{"exit"}
-	if err := CreateSSHShell(api, cliArgs); err != nil {
+	if err := CreateSSHShell(api, config.GetMachineName(), cliArgs); err != nil {
 		t.Fatalf("Error running ssh command: %v", err)
 	}
 
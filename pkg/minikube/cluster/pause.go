@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// Pause stops the kubelet and freezes every running Kubernetes container, returning the paused ID's
+func Pause(cr cruntime.Manager, bs bootstrapper.Bootstrapper) ([]string, error) {
+	// The kubelet must be stopped first, otherwise it will attempt to replace the frozen containers.
+	if err := bs.StopKubelet(); err != nil {
+		return nil, errors.Wrap(err, "kubelet stop")
+	}
+
+	running, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+	if err != nil {
+		return nil, errors.Wrap(err, "list running")
+	}
+	paused, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Paused})
+	if err != nil {
+		return nil, errors.Wrap(err, "list paused")
+	}
+	// running containers include the paused ones, which can not be paused again
+	var ids []string
+	for _, id := range running {
+		if !contains(paused, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		glog.Warningf("no running containers to pause")
+		return ids, nil
+	}
+	return ids, cr.PauseContainers(ids)
+}
+
+// Unpause thaws every paused Kubernetes container and restarts the kubelet, returning the unpaused ID's
+func Unpause(cr cruntime.Manager, bs bootstrapper.Bootstrapper) ([]string, error) {
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Paused})
+	if err != nil {
+		return nil, errors.Wrap(err, "list paused")
+	}
+
+	if len(ids) == 0 {
+		glog.Warningf("no paused containers found")
+	} else if err := cr.UnpauseContainers(ids); err != nil {
+		return nil, errors.Wrap(err, "unpause")
+	}
+
+	if err := bs.StartKubelet(); err != nil {
+		return nil, errors.Wrap(err, "kubelet start")
+	}
+	return ids, nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaused returns whether the Kubernetes control plane has been paused
+func IsPaused(cr cruntime.Manager) (bool, error) {
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Paused, Name: "kube-apiserver"})
+	if err != nil {
+		return false, errors.Wrap(err, "list paused")
+	}
+	return len(ids) > 0, nil
+}
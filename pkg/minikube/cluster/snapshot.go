@@ -0,0 +1,231 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+const (
+	// snapshotMetadata is the file describing a snapshot
+	snapshotMetadata = "snapshot.json"
+	// snapshotDB is the etcd snapshot within a snapshot directory
+	snapshotDB = "etcd.db"
+	// snapshotCerts is the directory of certificates within a snapshot directory
+	snapshotCerts = "certs"
+)
+
+// sharedCerts are the certificate authorities within localpath.MiniPath(), which are used by every profile
+var sharedCerts = []string{"ca.crt", "ca.key", "proxy-client-ca.crt", "proxy-client-ca.key"}
+
+// validSnapshotName matches names which are safe to use as a directory name
+var validSnapshotName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Snapshot describes a saved copy of the cluster state
+type Snapshot struct {
+	Name              string
+	KubernetesVersion string
+	Created           time.Time
+}
+
+// SnapshotDir returns the directory where the snapshots of a profile are stored
+func SnapshotDir(profile string) string {
+	return localpath.MakeMiniPath("profiles", profile, "snapshots")
+}
+
+// SaveSnapshot saves the cluster state, along with the certificates it was issued with, as a named snapshot.
+// An existing snapshot with the same name is replaced.
+func SaveSnapshot(bs bootstrapper.Bootstrapper, cc *cfg.Config, profile string, name string) (*Snapshot, error) {
+	if !validSnapshotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: may only contain letters, digits, '.', '_' and '-'", name)
+	}
+
+	dir := filepath.Join(SnapshotDir(profile), name)
+	tmp := dir + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, errors.Wrap(err, "cleanup")
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := os.MkdirAll(filepath.Join(tmp, snapshotCerts), 0700); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	if err := bs.SnapshotCluster(cc.KubernetesConfig, filepath.Join(tmp, snapshotDB)); err != nil {
+		return nil, errors.Wrap(err, "snapshot")
+	}
+	if err := copyCerts(localpath.MiniPath(), filepath.Join(tmp, snapshotCerts)); err != nil {
+		return nil, errors.Wrap(err, "certs")
+	}
+
+	s := &Snapshot{Name: name, KubernetesVersion: cc.KubernetesConfig.KubernetesVersion, Created: time.Now()}
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, snapshotMetadata), data, 0600); err != nil {
+		return nil, errors.Wrap(err, "write")
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Wrapf(err, "removing %s", dir)
+	}
+	return s, os.Rename(tmp, dir)
+}
+
+// RestoreSnapshot replaces the cluster state and certificates with those of a named snapshot.
+// As the certificate authorities are shared by every profile, replacing them requires force, and the current ones are backed up first.
+func RestoreSnapshot(bs bootstrapper.Bootstrapper, cc *cfg.Config, profile string, name string, force bool) error {
+	s, err := loadSnapshot(profile, name)
+	if err != nil {
+		return err
+	}
+	if s.KubernetesVersion != cc.KubernetesConfig.KubernetesVersion {
+		return fmt.Errorf("snapshot %q was taken with Kubernetes %s, but the cluster is running %s", name, s.KubernetesVersion, cc.KubernetesConfig.KubernetesVersion)
+	}
+
+	dir := filepath.Join(SnapshotDir(profile), name)
+	changed, err := changedFiles(filepath.Join(dir, snapshotCerts), localpath.MiniPath(), sharedCerts)
+	if err != nil {
+		return errors.Wrap(err, "compare certs")
+	}
+	if len(changed) > 0 {
+		if !force {
+			return fmt.Errorf("snapshot %q was taken with a different certificate authority (%s), which is shared by every profile: use --force to replace it", name, strings.Join(changed, ", "))
+		}
+		backup := filepath.Join(localpath.MiniPath(), "backups", fmt.Sprintf("certs-%s", time.Now().Format("20060102150405")))
+		if err := os.MkdirAll(backup, 0700); err != nil {
+			return errors.Wrap(err, "mkdir")
+		}
+		if err := copyFiles(localpath.MiniPath(), backup, sharedCerts); err != nil {
+			return errors.Wrap(err, "backup certs")
+		}
+		out.T(out.Notice, "The previous certificate authority was backed up to {{.path}}", out.V{"path": backup})
+	}
+	if err := copyCerts(filepath.Join(dir, snapshotCerts), localpath.MiniPath()); err != nil {
+		return errors.Wrap(err, "certs")
+	}
+	if err := bs.SetupCerts(cc.KubernetesConfig); err != nil {
+		return errors.Wrap(err, "setup certs")
+	}
+	return bs.RestoreCluster(cc.KubernetesConfig, filepath.Join(dir, snapshotDB))
+}
+
+// ListSnapshots returns the snapshots of a profile, oldest first
+func ListSnapshots(profile string) ([]*Snapshot, error) {
+	items, err := ioutil.ReadDir(SnapshotDir(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, f := range items {
+		if !f.IsDir() || strings.HasSuffix(f.Name(), ".tmp") {
+			continue
+		}
+		s, err := loadSnapshot(profile, f.Name())
+		if err != nil {
+			glog.Warningf("skipping invalid snapshot %q: %v", f.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Created.Before(snapshots[j].Created) })
+	return snapshots, nil
+}
+
+// loadSnapshot reads the metadata of a named snapshot
+func loadSnapshot(profile string, name string) (*Snapshot, error) {
+	if !validSnapshotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(SnapshotDir(profile), name, snapshotMetadata))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q does not exist", name)
+		}
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return s, nil
+}
+
+// changedFiles returns the names of the files which exist in dst, but differ from those in src
+func changedFiles(src string, dst string, names []string) ([]string, error) {
+	var changed []string
+	for _, name := range names {
+		want, err := ioutil.ReadFile(filepath.Join(src, name))
+		if err != nil {
+			return nil, err
+		}
+		got, err := ioutil.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !bytes.Equal(want, got) {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+// copyCerts copies the certificates used by bootstrapper.SetupCerts between directories
+func copyCerts(src string, dst string) error {
+	return copyFiles(src, dst, bootstrapper.CertFiles())
+}
+
+// copyFiles copies the named certificates between directories
+func copyFiles(src string, dst string, names []string) error {
+	for _, cert := range names {
+		data, err := ioutil.ReadFile(filepath.Join(src, cert))
+		if err != nil {
+			return err
+		}
+		perms := os.FileMode(0644)
+		if strings.HasSuffix(cert, ".key") {
+			perms = 0600
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, cert), data, perms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
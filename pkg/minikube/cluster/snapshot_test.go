@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+// snapshotBootstrapper implements the snapshot methods of a bootstrapper, by copying a local file
+type snapshotBootstrapper struct {
+	bootstrapper.Bootstrapper
+	db       string
+	restored string
+}
+
+func (b *snapshotBootstrapper) SnapshotCluster(k8s cfg.KubernetesConfig, dst string) error {
+	return ioutil.WriteFile(dst, []byte(b.db), 0600)
+}
+
+func (b *snapshotBootstrapper) RestoreCluster(k8s cfg.KubernetesConfig, src string) error {
+	data, err := ioutil.ReadFile(src)
+	b.restored = string(data)
+	return err
+}
+
+func (b *snapshotBootstrapper) SetupCerts(k8s cfg.KubernetesConfig) error {
+	return nil
+}
+
+func TestSnapshots(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	for _, cert := range bootstrapper.CertFiles() {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, cert), []byte("original"), 0600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	cc := &cfg.Config{KubernetesConfig: cfg.KubernetesConfig{KubernetesVersion: "v1.16.2"}}
+	bs := &snapshotBootstrapper{db: "first"}
+	if _, err := SaveSnapshot(bs, cc, "p1", "first"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	bs.db = "second"
+	if _, err := SaveSnapshot(bs, cc, "p1", "second"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	if _, err := SaveSnapshot(bs, cc, "p1", "../escape"); err == nil {
+		t.Errorf("SaveSnapshot with an invalid name succeeded, expected error")
+	}
+
+	snapshots, err := ListSnapshots("p1")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	var names []string
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Errorf("ListSnapshots = %v, want [first second]", names)
+	}
+	if snapshots, err := ListSnapshots("p2"); err != nil || len(snapshots) != 0 {
+		t.Errorf("ListSnapshots(p2) = %v, %v, want no snapshots", snapshots, err)
+	}
+
+	// Certificates changed since the snapshot should be put back
+	apiserver := filepath.Join(tempDir, "apiserver.crt")
+	if err := ioutil.WriteFile(apiserver, []byte("changed"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := RestoreSnapshot(bs, cc, "p1", "first", false); err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if bs.restored != "first" {
+		t.Errorf("restored %q, want %q", bs.restored, "first")
+	}
+	if data, err := ioutil.ReadFile(apiserver); err != nil || string(data) != "original" {
+		t.Errorf("apiserver.crt = %q, %v, want %q", data, err, "original")
+	}
+
+	// The CA is shared by every profile, so it is only replaced with force, after being backed up
+	ca := filepath.Join(tempDir, "ca.crt")
+	if err := ioutil.WriteFile(ca, []byte("changed"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	bs.restored = ""
+	if err := RestoreSnapshot(bs, cc, "p1", "second", false); err == nil {
+		t.Errorf("RestoreSnapshot with another CA succeeded, expected error")
+	}
+	if bs.restored != "" {
+		t.Errorf("restored %q, want nothing", bs.restored)
+	}
+	if data, err := ioutil.ReadFile(ca); err != nil || string(data) != "changed" {
+		t.Errorf("ca.crt = %q, %v, want %q", data, err, "changed")
+	}
+	if err := RestoreSnapshot(bs, cc, "p1", "second", true); err != nil {
+		t.Fatalf("RestoreSnapshot with force: %v", err)
+	}
+	if data, err := ioutil.ReadFile(ca); err != nil || string(data) != "original" {
+		t.Errorf("ca.crt = %q, %v, want %q", data, err, "original")
+	}
+	backups, err := filepath.Glob(filepath.Join(tempDir, "backups", "*", "ca.crt"))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v, %v, want one backup", backups, err)
+	}
+	if data, err := ioutil.ReadFile(backups[0]); err != nil || string(data) != "changed" {
+		t.Errorf("backup ca.crt = %q, %v, want %q", data, err, "changed")
+	}
+
+	if err := RestoreSnapshot(bs, cc, "p1", "missing", false); err == nil {
+		t.Errorf("RestoreSnapshot of a missing snapshot succeeded, expected error")
+	}
+	upgraded := &cfg.Config{KubernetesConfig: cfg.KubernetesConfig{KubernetesVersion: "v1.17.0"}}
+	if err := RestoreSnapshot(bs, upgraded, "p1", "first", false); err == nil {
+		t.Errorf("RestoreSnapshot across Kubernetes versions succeeded, expected error")
+	}
+}
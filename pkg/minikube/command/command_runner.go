@@ -17,9 +17,13 @@ limitations under the License.
 package command
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"os"
 	"path"
+	"strings"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 )
@@ -49,10 +53,71 @@ type Runner interface {
 	// Copy is a convenience method that runs a command to copy a file
 	Copy(assets.CopyableFile) error
 
+	// CopyAll is a convenience method that copies many files at once,
+	// skipping the files whose contents are already up to date on the target
+	CopyAll([]assets.CopyableFile) error
+
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
+
+	// ReadFile is a convenience method that writes the contents of a file on the target to w,
+	// such as to download it. Unlike the output of CombinedOutputTo, the contents are written unaltered.
+	ReadFile(path string, w io.Writer) error
+
+	// RunCmd runs a command and waits for it to complete, capturing its standard
+	// output and standard error separately. If the context is cancelled or its
+	// deadline is exceeded first, the command is killed and the error of the
+	// context is returned. A typical usage is:
+	//
+	//          ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	//          defer cancel()
+	//          rr, err := RunCmd(ctx, Cmd{Command: "sudo systemctl restart docker"})
+	RunCmd(ctx context.Context, cmd Cmd) (*RunResult, error)
+}
+
+// Cmd is a command to run with RunCmd
+type Cmd struct {
+	// Command is the command line, which is interpreted by a shell as with Run
+	Command string
+	// Env are additional environment variables for the command, in the form key=value
+	Env []string
+	// Stdin is the standard input of the command. If nil, the command reads from an empty input.
+	Stdin io.Reader
+}
+
+// RunResult is the outcome of a command run with RunCmd
+type RunResult struct {
+	Stdout bytes.Buffer
+	Stderr bytes.Buffer
+	// ExitCode is the exit code of the command, if it exited on its own
+	ExitCode int
+}
+
+// Output returns the standard output of the command, followed by its standard error
+func (rr *RunResult) Output() string {
+	return rr.Stdout.String() + rr.Stderr.String()
 }
 
 func getDeleteFileCommand(f assets.CopyableFile) string {
 	return fmt.Sprintf("sudo rm %s", path.Join(f.GetTargetDir(), f.GetTargetName()))
 }
+
+// ShellQuote quotes a string so that it is passed to a command as a single argument by the shell
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// FileMode converts the octal mode of chmod to a FileMode, whose setuid, setgid and sticky bits are elsewhere
+func FileMode(mode uint64) os.FileMode {
+	m := os.FileMode(mode).Perm()
+	if mode&04000 != 0 {
+		m |= os.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		m |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		m |= os.ModeSticky
+	}
+	return m
+}
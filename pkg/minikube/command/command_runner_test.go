@@ -0,0 +1,56 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"os"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want string
+	}{
+		{"example.com/app:v1", `'example.com/app:v1'`},
+		{"/tmp/my dir", `'/tmp/my dir'`},
+		{"it's; rm -rf /", `'it'\''s; rm -rf /'`},
+	}
+	for _, tc := range tests {
+		if got := ShellQuote(tc.in); got != tc.want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	var tests = []struct {
+		mode uint64
+		want os.FileMode
+	}{
+		{0644, 0644},
+		{04755, 0755 | os.ModeSetuid},
+		{02750, 0750 | os.ModeSetgid},
+		{01777, 0777 | os.ModeSticky},
+		{06711, 0711 | os.ModeSetuid | os.ModeSetgid},
+	}
+	for _, tc := range tests {
+		if got := FileMode(tc.mode); got != tc.want {
+			t.Errorf("FileMode(%04o) = %v, want %v", tc.mode, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// targetPath returns the path of a file on the target
+func targetPath(f assets.CopyableFile) string {
+	return path.Join(f.GetTargetDir(), f.GetTargetName())
+}
+
+// localChecksum returns the sha256 checksum of the contents of a file to copy.
+// Files which are not on disk have to be read to be checksummed, so a file to copy in their place is returned.
+func localChecksum(f assets.CopyableFile) (string, assets.CopyableFile, error) {
+	h := sha256.New()
+	if fa, ok := f.(*assets.FileAsset); ok {
+		src, err := os.Open(fa.GetAssetName())
+		if err != nil {
+			return "", nil, err
+		}
+		defer src.Close()
+		if _, err := io.Copy(h, src); err != nil {
+			return "", nil, errors.Wrapf(err, "reading %s", fa.GetAssetName())
+		}
+		return hex.EncodeToString(h.Sum(nil)), f, nil
+	}
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "reading %s", f.GetAssetName())
+	}
+	h.Write(data)
+	m := assets.NewMemoryAsset(data, f.GetTargetDir(), f.GetTargetName(), f.GetPermissions())
+	m.AssetName = f.GetAssetName()
+	return hex.EncodeToString(h.Sum(nil)), m, nil
+}
+
+// targetChecksums returns the sha256 checksums of files on the target, by path. Missing files are omitted.
+func targetChecksums(r Runner, paths []string) (map[string]string, error) {
+	sums := map[string]string{}
+	if len(paths) == 0 {
+		return sums, nil
+	}
+	// sha256sum fails if any of the files is missing, but still prints the checksums of the others
+	rr, err := r.RunCmd(context.Background(), Cmd{Command: fmt.Sprintf("sudo sha256sum %s 2>/dev/null || true", strings.Join(paths, " "))})
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		sums[fields[1]] = fields[0]
+	}
+	return sums, nil
+}
+
+// outdatedFiles returns the files whose contents differ from those already on the target.
+// The returned files should be copied in place of the given ones, which may have been read.
+func outdatedFiles(r Runner, files []assets.CopyableFile) ([]assets.CopyableFile, error) {
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, targetPath(f))
+	}
+	current, err := targetChecksums(r, paths)
+	if err != nil {
+		return nil, errors.Wrap(err, "target checksums")
+	}
+
+	var outdated []assets.CopyableFile
+	for _, f := range files {
+		sum, c, err := localChecksum(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "checksum")
+		}
+		if current[targetPath(f)] == sum {
+			glog.Infof("skipping %s, which is up to date", targetPath(f))
+			continue
+		}
+		outdated = append(outdated, c)
+	}
+	return outdated, nil
+}
+
+// writeTar writes files to w as a tar archive, with paths relative to / and owned by root
+func writeTar(w io.Writer, files []assets.CopyableFile) error {
+	tw := tar.NewWriter(w)
+	now := time.Now()
+	for _, f := range files {
+		perms, err := strconv.ParseInt(f.GetPermissions(), 8, 0)
+		if err != nil {
+			return errors.Wrapf(err, "error converting permissions %s to integer", f.GetPermissions())
+		}
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(targetPath(f), "/"),
+			Mode:     perms,
+			Size:     int64(f.GetLength()),
+			ModTime:  now,
+			Uname:    "root",
+			Gname:    "root",
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.Wrapf(err, "header for %s", hdr.Name)
+		}
+		if hdr.Size == 0 {
+			continue
+		}
+		if _, err := io.Copy(tw, f); err != nil {
+			return errors.Wrapf(err, "copying %s", f.GetAssetName())
+		}
+	}
+	return tw.Close()
+}
+
+// copyAllWithTar copies the outdated files within files to the target as a single tar archive,
+// which is extracted as root so that the files are owned by root and keep their permissions.
+func copyAllWithTar(r Runner, files []assets.CopyableFile) error {
+	outdated, err := outdatedFiles(r, files)
+	if err != nil {
+		return err
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+	glog.Infof("Transferring %d of %d files in a single archive", len(outdated), len(files))
+
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := writeTar(pw, outdated)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+	_, err = r.RunCmd(context.Background(), Cmd{Command: "sudo tar -x -C / -f -", Stdin: pr})
+	// Unblock the writer, should tar have exited early
+	pr.Close()
+	werr := <-written
+	if err != nil {
+		return err
+	}
+	if werr != nil {
+		return errors.Wrap(werr, "writing archive")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func TestOutdatedFiles(t *testing.T) {
+	sum := sha256.Sum256([]byte("current"))
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo sha256sum /etc/current /etc/changed /etc/missing 2>/dev/null || true": hex.EncodeToString(sum[:]) + "  /etc/current\n" +
+			hex.EncodeToString(sum[:]) + "  /etc/changed\n",
+	})
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte("current"), "/etc/current", "0644"),
+		assets.NewMemoryAssetTarget([]byte("new"), "/etc/changed", "0644"),
+		assets.NewMemoryAssetTarget([]byte("new"), "/etc/missing", "0644"),
+	}
+
+	outdated, err := outdatedFiles(f, files)
+	if err != nil {
+		t.Fatalf("outdatedFiles: %v", err)
+	}
+	var got []string
+	for _, o := range outdated {
+		got = append(got, targetPath(o))
+		data, err := ioutil.ReadAll(o)
+		if err != nil || string(data) != "new" {
+			t.Errorf("contents of %s = %q, %v, want %q", targetPath(o), data, err, "new")
+		}
+	}
+	if len(got) != 2 || got[0] != "/etc/changed" || got[1] != "/etc/missing" {
+		t.Errorf("outdatedFiles = %v, want [/etc/changed /etc/missing]", got)
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte("key"), "/var/lib/minikube/certs/ca.key", "0600"),
+		assets.NewMemoryAssetTarget([]byte{}, "/etc/empty", "0644"),
+	}
+	var b bytes.Buffer
+	if err := writeTar(&b, files); err != nil {
+		t.Fatalf("writeTar: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		mode     int64
+		contents string
+	}{
+		{"var/lib/minikube/certs/ca.key", 0600, "key"},
+		{"etc/empty", 0644, ""},
+	}
+	r := tar.NewReader(&b)
+	for _, tc := range tests {
+		hdr, err := r.Next()
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if hdr.Name != tc.name || hdr.Mode != tc.mode || hdr.Uid != 0 || hdr.Gid != 0 {
+			t.Errorf("header = %s %o %d:%d, want %s %o 0:0", hdr.Name, hdr.Mode, hdr.Uid, hdr.Gid, tc.name, tc.mode)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil || string(data) != tc.contents {
+			t.Errorf("contents of %s = %q, %v, want %q", tc.name, data, err, tc.contents)
+		}
+	}
+}
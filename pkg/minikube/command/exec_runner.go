@@ -18,6 +18,8 @@ package command
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
@@ -72,6 +74,44 @@ func (e *ExecRunner) CombinedOutput(cmd string) (string, error) {
 
 }
 
+// RunCmd runs a command in a bash shell, killing it along with its children if the context is done first.
+func (*ExecRunner) RunCmd(ctx context.Context, cmd Cmd) (*RunResult, error) {
+	glog.Infoln("Run:", cmd.Command)
+	c := exec.Command("/bin/bash", "-c", cmd.Command)
+	c.Env = append(os.Environ(), cmd.Env...)
+	c.Stdin = cmd.Stdin
+	rr := &RunResult{}
+	c.Stdout = &rr.Stdout
+	c.Stderr = &rr.Stderr
+	setProcessGroup(c)
+	if err := c.Start(); err != nil {
+		return rr, errors.Wrapf(err, "starting command: %s", cmd.Command)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		if err := killProcessGroup(c); err != nil {
+			glog.Warningf("kill %s: %v", cmd.Command, err)
+		}
+		<-done
+		return rr, errors.Wrapf(ctx.Err(), "running command: %s", cmd.Command)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			rr.ExitCode = exitErr.ExitCode()
+		}
+		return rr, errors.Wrapf(err, "running command: %s\nstdout: %s\nstderr: %s", cmd.Command, rr.Stdout.String(), rr.Stderr.String())
+	}
+	return rr, nil
+}
+
 // Copy copies a file and its permissions
 func (*ExecRunner) Copy(f assets.CopyableFile) error {
 	if err := os.MkdirAll(f.GetTargetDir(), os.ModePerm); err != nil {
@@ -88,11 +128,11 @@ func (*ExecRunner) Copy(f assets.CopyableFile) error {
 	if err != nil {
 		return errors.Wrapf(err, "error creating file at %s", targetPath)
 	}
-	perms, err := strconv.ParseInt(f.GetPermissions(), 8, 0)
+	perms, err := strconv.ParseUint(f.GetPermissions(), 8, 32)
 	if err != nil {
 		return errors.Wrapf(err, "error converting permissions %s to integer", f.GetPermissions())
 	}
-	if err := os.Chmod(targetPath, os.FileMode(perms)); err != nil {
+	if err := os.Chmod(targetPath, FileMode(perms)); err != nil {
 		return errors.Wrapf(err, "error changing file permissions for %s", targetPath)
 	}
 
@@ -104,6 +144,34 @@ do you have the correct permissions?`,
 	return target.Close()
 }
 
+// CopyAll copies the files whose contents are not up to date, and their permissions
+func (e *ExecRunner) CopyAll(files []assets.CopyableFile) error {
+	outdated, err := outdatedFiles(e, files)
+	if err != nil {
+		return err
+	}
+	for _, f := range outdated {
+		if err := e.Copy(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile writes the contents of a file to w, reading it as root like the other runners
+func (*ExecRunner) ReadFile(p string, w io.Writer) error {
+	cmd := fmt.Sprintf("sudo cat %s", ShellQuote(p))
+	glog.Infoln("Run:", cmd)
+	var errB bytes.Buffer
+	c := exec.Command("/bin/bash", "-c", cmd)
+	c.Stdout = w
+	c.Stderr = &errB
+	if err := c.Run(); err != nil {
+		return errors.Wrapf(err, "command failed: %s\nstderr: %s", cmd, errB.String())
+	}
+	return nil
+}
+
 // Remove removes a file
 func (e *ExecRunner) Remove(f assets.CopyableFile) error {
 	targetPath := filepath.Join(f.GetTargetDir(), f.GetTargetName())
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestExecRunnerRunCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires bash")
+	}
+	r := &ExecRunner{}
+	rr, err := r.RunCmd(context.Background(), Cmd{
+		Command: `read line; echo "$line $GREETING"; echo oops >&2; exit 3`,
+		Env:     []string{"GREETING=world"},
+		Stdin:   strings.NewReader("hello\n"),
+	})
+	if err == nil {
+		t.Fatalf("RunCmd succeeded, want an error for the exit code")
+	}
+	if got := rr.Stdout.String(); got != "hello world\n" {
+		t.Errorf("stdout = %q, want %q", got, "hello world\n")
+	}
+	if got := rr.Stderr.String(); got != "oops\n" {
+		t.Errorf("stderr = %q, want %q", got, "oops\n")
+	}
+	if rr.ExitCode != 3 {
+		t.Errorf("exit code = %d, want 3", rr.ExitCode)
+	}
+}
+
+func TestExecRunnerRunCmdTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires bash")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	// The children of the shell hold its output open, so they must be killed too for RunCmd to return
+	_, err := (&ExecRunner{}).RunCmd(ctx, Cmd{Command: "sleep 30 | cat; echo done"})
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Errorf("RunCmd error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("RunCmd returned after %s, want it to return once the deadline is exceeded", elapsed)
+	}
+}
@@ -18,6 +18,7 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 
@@ -73,6 +74,20 @@ func (f *FakeCommandRunner) CombinedOutput(cmd string) (string, error) {
 	return out.(string), nil
 }
 
+// RunCmd returns the set output for a given command text as its standard output.
+func (f *FakeCommandRunner) RunCmd(ctx context.Context, cmd Cmd) (*RunResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := f.cmdMap.Load(cmd.Command)
+	if !ok {
+		return nil, fmt.Errorf("unavailable command: %s", cmd.Command)
+	}
+	rr := &RunResult{}
+	rr.Stdout.WriteString(out.(string))
+	return rr, nil
+}
+
 // Copy adds the filename, file contents key value pair to the stored map.
 func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	var b bytes.Buffer
@@ -84,12 +99,32 @@ func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyAll adds the filename, file contents key value pairs of all files to the stored map.
+func (f *FakeCommandRunner) CopyAll(files []assets.CopyableFile) error {
+	for _, file := range files {
+		if err := f.Copy(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Remove removes the filename, file contents key value pair from the stored map
 func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	f.fileMap.Delete(file.GetAssetName())
 	return nil
 }
 
+// ReadFile writes the stored contents of a file to w.
+func (f *FakeCommandRunner) ReadFile(path string, w io.Writer) error {
+	contents, ok := f.fileMap.Load(path)
+	if !ok {
+		return fmt.Errorf("unavailable file: %s", path)
+	}
+	_, err := fmt.Fprint(w, contents)
+	return err
+}
+
 // SetFileToContents stores the file to contents map for the FakeCommandRunner
 func (f *FakeCommandRunner) SetFileToContents(fileToContents map[string]string) {
 	for k, v := range fileToContents {
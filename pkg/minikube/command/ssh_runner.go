@@ -18,10 +18,13 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"path"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -55,6 +58,25 @@ func (s *SSHRunner) Remove(f assets.CopyableFile) error {
 	return sess.Run(cmd)
 }
 
+// ReadFile writes the contents of a file on the remote to w.
+func (s *SSHRunner) ReadFile(p string, w io.Writer) error {
+	sess, err := s.c.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "NewSession")
+	}
+	defer sess.Close()
+
+	var errB bytes.Buffer
+	sess.Stdout = w
+	sess.Stderr = &errB
+	cmd := fmt.Sprintf("sudo cat %s", ShellQuote(p))
+	glog.Infof("SSH: %s", cmd)
+	if err := sess.Run(cmd); err != nil {
+		return errors.Wrapf(err, "command failed: %s\nstderr: %s", cmd, errB.String())
+	}
+	return nil
+}
+
 type singleWriter struct {
 	b  bytes.Buffer
 	mu sync.Mutex
@@ -151,6 +173,94 @@ func (s *SSHRunner) CombinedOutput(cmd string) (string, error) {
 	return out, nil
 }
 
+// RunCmd runs a command on the remote and waits for it to return. If the context is done first,
+// the processes started by the command are killed and the session is closed.
+func (s *SSHRunner) RunCmd(ctx context.Context, cmd Cmd) (*RunResult, error) {
+	glog.Infof("SSH: %s", cmd.Command)
+	sess, err := s.c.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "NewSession")
+	}
+	defer func() {
+		if err := sess.Close(); err != nil {
+			if err != io.EOF {
+				glog.Errorf("session close: %v", err)
+			}
+		}
+	}()
+
+	rr := &RunResult{}
+	sess.Stdin = cmd.Stdin
+	sess.Stdout = &rr.Stdout
+	sess.Stderr = &rr.Stderr
+
+	// sshd starts the shell of a session without a terminal in a new process group, so its id identifies
+	// every process started by the command. It is recorded so that they can be killed on cancellation.
+	pidFile := fmt.Sprintf("/tmp/minikube-run-%d.pid", time.Now().UnixNano())
+	script := fmt.Sprintf("echo $$ > %s\n%s%s\nrc=$?; rm -f %s; exit $rc", pidFile, exportEnv(cmd.Env), cmd.Command, pidFile)
+	if err := sess.Start(script); err != nil {
+		return rr, errors.Wrapf(err, "starting command: %s", cmd.Command)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sess.Wait()
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		if err := s.killProcessGroup(pidFile); err != nil {
+			glog.Warningf("kill %s: %v", cmd.Command, err)
+		}
+		if err := sess.Close(); err != nil && err != io.EOF {
+			glog.Warningf("session close: %v", err)
+		}
+		<-done
+		return rr, errors.Wrapf(ctx.Err(), "command cancelled: %s", cmd.Command)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*ssh.ExitError); ok {
+			rr.ExitCode = exitErr.ExitStatus()
+		}
+		return rr, errors.Wrapf(err, "command failed: %s\nstdout: %s\nstderr: %s", cmd.Command, rr.Stdout.String(), rr.Stderr.String())
+	}
+	return rr, nil
+}
+
+// killProcessGroup kills the process group whose id was written to pidFile by RunCmd
+func (s *SSHRunner) killProcessGroup(pidFile string) error {
+	sess, err := s.c.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "NewSession")
+	}
+	defer sess.Close()
+	kill := fmt.Sprintf("test -s %[1]s && sudo kill -KILL -- -$(cat %[1]s); sudo rm -f %[1]s", pidFile)
+	if out, err := sess.CombinedOutput(kill); err != nil {
+		return errors.Wrapf(err, "output: %s", out)
+	}
+	return nil
+}
+
+// exportEnv returns shell statements exporting environment variables given in the form key=value
+func exportEnv(env []string) string {
+	var b strings.Builder
+	for _, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fmt.Fprintf(&b, "export %s='%s'\n", parts[0], strings.Replace(parts[1], "'", `'"'"'`, -1))
+	}
+	return b.String()
+}
+
+// CopyAll copies many files to the remote as a single tar archive, which is much faster than
+// copying them one by one over separate SSH sessions. Files which are up to date are skipped.
+func (s *SSHRunner) CopyAll(files []assets.CopyableFile) error {
+	return copyAllWithTar(s, files)
+}
+
 // Copy copies a file to the remote over SSH.
 func (s *SSHRunner) Copy(f assets.CopyableFile) error {
 	sess, err := s.c.NewSession()
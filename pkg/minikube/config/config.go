@@ -132,6 +132,14 @@ func GetMachineName() string {
 	return viper.GetString(MachineProfile)
 }
 
+// MachineName returns the name of the libmachine host described by this config
+func (c MachineConfig) MachineName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return GetMachineName()
+}
+
 // Load loads the kubernetes and machine config for the current machine
 func Load() (*Config, error) {
 	return DefaultLoader.LoadConfigFromFile(GetMachineName())
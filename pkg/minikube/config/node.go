@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+)
+
+// NodeName returns the name of the nth node of a profile, where the control plane is node 1
+func NodeName(profile string, n int) string {
+	return fmt.Sprintf("%s-m%02d", profile, n)
+}
+
+// NextNodeName returns the first node name that is not yet in use within a profile
+func (c *Config) NextNodeName(profile string) string {
+	for n := 2; ; n++ {
+		name := NodeName(profile, n)
+		if _, ok := c.FindNode(name); !ok {
+			return name
+		}
+	}
+}
+
+// FindNode returns the index of the named worker node, and whether it was found
+func (c *Config) FindNode(name string) (int, bool) {
+	for i, n := range c.Nodes {
+		if n.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// RemoveNode removes the named worker node, returning false if it was not found
+func (c *Config) RemoveNode(name string) bool {
+	i, ok := c.FindNode(name)
+	if !ok {
+		return false
+	}
+	c.Nodes = append(c.Nodes[:i], c.Nodes[i+1:]...)
+	return true
+}
+
+// NodeMachineConfig returns the machine configuration for a worker node, based on that of the control plane
+func (c *Config) NodeMachineConfig(n Node) MachineConfig {
+	mc := c.MachineConfig
+	mc.Name = n.Name
+	// Each machine must have a distinct UUID, so let the driver generate one
+	mc.UUID = ""
+	return mc
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+)
+
+func TestNextNodeName(t *testing.T) {
+	var tests = []struct {
+		description string
+		nodes       []Node
+		expected    string
+	}{
+		{description: "no workers", nodes: nil, expected: "p1-m02"},
+		{description: "one worker", nodes: []Node{{Name: "p1-m02"}}, expected: "p1-m03"},
+		{description: "reuse a gap", nodes: []Node{{Name: "p1-m03"}}, expected: "p1-m02"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := &Config{Nodes: test.nodes}
+			if got := c.NextNodeName("p1"); got != test.expected {
+				t.Errorf("NextNodeName() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestRemoveNode(t *testing.T) {
+	c := &Config{Nodes: []Node{{Name: "p1-m02"}, {Name: "p1-m03"}, {Name: "p1-m04"}}}
+	if !c.RemoveNode("p1-m03") {
+		t.Fatalf("RemoveNode(p1-m03) = false, want true")
+	}
+	if c.RemoveNode("p1-m03") {
+		t.Errorf("RemoveNode(p1-m03) succeeded twice")
+	}
+	if len(c.Nodes) != 2 || c.Nodes[0].Name != "p1-m02" || c.Nodes[1].Name != "p1-m04" {
+		t.Errorf("unexpected nodes after removal: %+v", c.Nodes)
+	}
+}
+
+func TestNodeMachineConfig(t *testing.T) {
+	c := &Config{MachineConfig: MachineConfig{VMDriver: "hyperkit", Memory: 2048, UUID: "abc"}}
+	mc := c.NodeMachineConfig(Node{Name: "p1-m02"})
+	if mc.MachineName() != "p1-m02" {
+		t.Errorf("MachineName() = %q, want p1-m02", mc.MachineName())
+	}
+	if mc.UUID != "" {
+		t.Errorf("UUID = %q, want it to be cleared", mc.UUID)
+	}
+	if mc.Memory != 2048 || mc.VMDriver != "hyperkit" {
+		t.Errorf("machine parameters were not inherited: %+v", mc)
+	}
+	if c.MachineConfig.Name != "" {
+		t.Errorf("control plane config was modified: %+v", c.MachineConfig)
+	}
+}
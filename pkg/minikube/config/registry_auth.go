@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// registryAuthFile is the file within a profile directory holding the credentials of private registries
+const registryAuthFile = "registry-auth.json"
+
+// RegistryCredential is the username and password used to pull images from a private registry
+type RegistryCredential struct {
+	Username string
+	Password string
+}
+
+// LoadRegistryCredentials returns the credentials of private registries stored for a profile, keyed by registry host.
+// If no credentials were ever stored, nil is returned.
+func LoadRegistryCredentials(profile string, miniHome ...string) (map[string]RegistryCredential, error) {
+	data, err := ioutil.ReadFile(filepath.Join(profileFolderPath(profile, miniHome...), registryAuthFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	creds := map[string]RegistryCredential{}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// SaveRegistryCredentials stores the credentials of private registries for a profile, in a file only readable by the user
+func SaveRegistryCredentials(profile string, creds map[string]RegistryCredential, miniHome ...string) error {
+	data, err := json.MarshalIndent(creds, "", "    ")
+	if err != nil {
+		return err
+	}
+	dir := profileFolderPath(profile, miniHome...)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, registryAuthFile), data, 0600)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistryCredentials(t *testing.T) {
+	miniHome, err := ioutil.TempDir("", "registry-auth")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(miniHome)
+
+	creds, err := LoadRegistryCredentials("p1", miniHome)
+	if err != nil || creds != nil {
+		t.Errorf("LoadRegistryCredentials() = %v, %v, want nil for a profile without credentials", creds, err)
+	}
+
+	want := map[string]RegistryCredential{"harbor.example.com": {Username: "dev", Password: "secret"}}
+	if err := SaveRegistryCredentials("p1", want, miniHome); err != nil {
+		t.Fatalf("SaveRegistryCredentials: %v", err)
+	}
+	fi, err := os.Stat(filepath.Join(miniHome, "profiles", "p1", registryAuthFile))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("credentials file mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	got, err := LoadRegistryCredentials("p1", miniHome)
+	if err != nil {
+		t.Fatalf("LoadRegistryCredentials: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("credentials differ: (-want +got)\n%s", diff)
+	}
+}
@@ -33,12 +33,21 @@ type Profile struct {
 type Config struct {
 	MachineConfig    MachineConfig
 	KubernetesConfig KubernetesConfig
+	Nodes            []Node // Additional worker nodes joined to the control plane
+}
+
+// Node contains the parameters of a worker node
+type Node struct {
+	Name              string // Used both as the libmachine host name and the Kubernetes node name
+	IP                string
+	KubernetesVersion string
 }
 
 // MachineConfig contains the parameters used to start a cluster.
 type MachineConfig struct {
-	KeepContext         bool // used by start and profile command to or not to switch kubectl's current context
-	EmbedCerts          bool // used by kubeconfig.Setup
+	Name                string `json:",omitempty"` // libmachine host name, defaults to the profile name
+	KeepContext         bool   // used by start and profile command to or not to switch kubectl's current context
+	EmbedCerts          bool   // used by kubeconfig.Setup
 	MinikubeISO         string
 	Memory              int
 	CPUs                int
@@ -50,6 +59,7 @@ type MachineConfig struct {
 	DockerEnv           []string // Each entry is formatted as KEY=VALUE.
 	InsecureRegistry    []string
 	RegistryMirror      []string
+	RegistryMirrorCache bool   // Pull Docker Hub images through the registry mirror of the host
 	HostOnlyCIDR        string // Only used by the virtualbox driver
 	HypervVirtualSwitch string
 	KVMNetwork          string             // Only used by the KVM driver
@@ -87,8 +97,14 @@ type KubernetesConfig struct {
 
 	ShouldLoadCachedImages bool
 	EnableDefaultCNI       bool
+
+	// AddonValues are user-supplied values for addon templates, keyed by addon name
+	AddonValues map[string]AddonValues `json:",omitempty"`
 }
 
+// AddonValues are values rendered into the manifests of an addon, set using "minikube addons configure"
+type AddonValues map[string]interface{}
+
 // VersionedExtraOption holds information on flags to apply to a specific range
 // of versions
 type VersionedExtraOption struct {
@@ -81,6 +81,9 @@ const DefaultMachineName = "minikube"
 // DefaultNodeName is the default name for the kubeadm node within the VM
 const DefaultNodeName = "minikube"
 
+// ControlPlaneAlias is the hostname every node uses to reach the Kubernetes control plane
+const ControlPlaneAlias = "control-plane.minikube.internal"
+
 // DefaultStorageClassProvisioner is the name of the default storage class provisioner
 const DefaultStorageClassProvisioner = "standard"
 
@@ -90,6 +93,12 @@ const Cache = "cache"
 // MountProcessFileName is the filename of the mount process
 var MountProcessFileName = ".mount-process"
 
+// RegistryMirrorProcessFileName is the prefix of the filenames of the registry mirror processes, one for each host address
+var RegistryMirrorProcessFileName = ".registry-mirror-process"
+
+// RegistryMirrorPort is the port on which the registry mirror of the host prefers to listen
+const RegistryMirrorPort = 5050
+
 const (
 	// DefaultEmbedCerts  is if the certs should be embedded in the kubeconfig file
 	DefaultEmbedCerts = false
@@ -118,7 +127,7 @@ apiserver: {{.APIServer}}
 kubectl: {{.Kubeconfig}}
 `
 	// DefaultAddonListFormat is the default format of addon list
-	DefaultAddonListFormat = "- {{.AddonName}}: {{.AddonStatus}}\n"
+	DefaultAddonListFormat = "- {{.AddonName}}: {{.AddonStatus}}{{if .AddonHealth}}, {{.AddonHealth}}{{end}}{{if .AddonConfigured}} (configured){{end}}\n"
 	// DefaultConfigViewFormat is the default format of config view
 	DefaultConfigViewFormat = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
 	// DefaultCacheListFormat is the default format of cache list
@@ -18,16 +18,27 @@ package cruntime
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+// containerdRuncRoot is the runc state directory used by containerd, needed to pause containers
+const containerdRuncRoot = "/run/containerd/runc/k8s.io"
+
 // Containerd contains containerd runtime state
 type Containerd struct {
-	Socket string
-	Runner CommandRunner
+	Socket      string
+	Runner      CommandRunner
+	Mirror      string
+	Credentials map[string]config.RegistryCredential
 }
 
 // Name is a human readable name for containerd
@@ -92,10 +103,115 @@ func (r *Containerd) Enable(disOthers bool) error {
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	if r.Mirror != "" {
+		if err := configureContainerdMirror(r.Runner, r.Mirror); err != nil {
+			return errors.Wrap(err, "registry mirror")
+		}
+	}
+	if r.Credentials != nil {
+		if _, err := configureContainerdCredentials(r.Runner, r.Credentials); err != nil {
+			return errors.Wrap(err, "registry credentials")
+		}
+	}
 	// Otherwise, containerd will fail API requests with 'Unimplemented'
 	return r.Runner.Run("sudo systemctl restart containerd")
 }
 
+// UpdateCredentials replaces the credentials of private registries, restarting containerd if its configuration changed
+func (r *Containerd) UpdateCredentials(creds map[string]config.RegistryCredential) error {
+	changed, err := configureContainerdCredentials(r.Runner, creds)
+	if err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	if !changed {
+		return nil
+	}
+	return r.Runner.Run("sudo systemctl restart containerd")
+}
+
+// configureContainerdMirror adds a registry mirror in front of the Docker Hub endpoint of the containerd configuration
+func configureContainerdMirror(cr CommandRunner, mirror string) error {
+	cPath := "/etc/containerd/config.toml"
+	endpoint := fmt.Sprintf(`endpoint = ["%s", "https://registry-1.docker.io"]`, mirror)
+	glog.Infof("Configuring containerd to use the registry mirror at %s", mirror)
+	return cr.Run(fmt.Sprintf(`sudo sed -i 's|^\( *\)endpoint = \[.*"https://registry-1.docker.io"\]$|\1%s|' %s`, endpoint, cPath))
+}
+
+// containerdTable matches the header of a table within the containerd configuration, such as [plugins.cri.registry]
+var containerdTable = regexp.MustCompile(`^(\s*)\[\s*([^\[\]]+?)\s*\]\s*$`)
+
+// configureContainerdCredentials replaces the registry credentials within the containerd configuration, and returns whether it changed
+func configureContainerdCredentials(cr CommandRunner, creds map[string]config.RegistryCredential) (bool, error) {
+	if err := writeKubeletCredentials(cr, creds); err != nil {
+		return false, err
+	}
+	cPath := "/etc/containerd/config.toml"
+	current, err := cr.CombinedOutput(fmt.Sprintf("sudo cat %s", cPath))
+	if err != nil {
+		return false, errors.Wrapf(err, "reading %s", cPath)
+	}
+	return updateFile(cr, cPath, []byte(containerdAuthConfig(current, creds)), "0644")
+}
+
+// containerdAuthConfig returns a containerd configuration whose registry section has the auths of creds,
+// replacing the auths it had before
+func containerdAuthConfig(current string, creds map[string]config.RegistryCredential) string {
+	hosts := make([]string, 0, len(creds))
+	for host := range creds {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	auths := func(indent string) []string {
+		var lines []string
+		for _, host := range hosts {
+			lines = append(lines,
+				fmt.Sprintf("%s  [plugins.cri.registry.auths.%q]", indent, "https://"+host),
+				fmt.Sprintf("%s    username = %q", indent, creds[host].Username),
+				fmt.Sprintf("%s    password = %q", indent, creds[host].Password))
+		}
+		return lines
+	}
+
+	var lines []string
+	registry := false
+	// The auths are written after the keys of the registry table, before the next table
+	pending := false
+	indent := ""
+	skip := false
+	for _, line := range strings.Split(strings.TrimRight(current, "\n"), "\n") {
+		m := containerdTable.FindStringSubmatch(line)
+		if m == nil {
+			if !skip {
+				lines = append(lines, line)
+			}
+			continue
+		}
+		if pending {
+			lines = append(lines, auths(indent)...)
+			pending = false
+		}
+		name := m[2]
+		skip = name == "plugins.cri.registry.auths" || strings.HasPrefix(name, "plugins.cri.registry.auths.")
+		if skip {
+			continue
+		}
+		if name == "plugins.cri.registry" {
+			registry = true
+			pending = true
+			indent = m[1]
+		}
+		lines = append(lines, line)
+	}
+	if !registry && len(hosts) > 0 {
+		lines = append(lines, "[plugins.cri.registry]")
+		pending = true
+	}
+	if pending {
+		lines = append(lines, auths(indent)...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
 // Disable idempotently disables containerd on a host
 func (r *Containerd) Disable() error {
 	return r.Runner.Run("sudo systemctl stop containerd")
@@ -104,7 +220,44 @@ func (r *Containerd) Disable() error {
 // LoadImage loads an image into this runtime
 func (r *Containerd) LoadImage(path string) error {
 	glog.Infof("Loading image: %s", path)
-	return r.Runner.Run(fmt.Sprintf("sudo ctr -n=k8s.io images import %s", path))
+	return r.Runner.Run(fmt.Sprintf("sudo ctr -n=k8s.io images import %s", command.ShellQuote(path)))
+}
+
+// ListImages returns the names of the images known to the runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from the runtime
+func (r *Containerd) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image from a build context directory with podman, which is included in the minikube ISO,
+// and imports it into the k8s.io namespace. The image is exported to a temporary file outside of the build context.
+func (r *Containerd) BuildImage(dir string, tag string) error {
+	glog.Infof("Building image %s from %s", tag, dir)
+	if err := r.Runner.Run(fmt.Sprintf("sudo podman build -t %s %s", command.ShellQuote(tag), command.ShellQuote(dir))); err != nil {
+		return errors.Wrap(err, "podman build")
+	}
+	dst, err := r.Runner.CombinedOutput("sudo mktemp -p /var/tmp minikube-image.XXXXXX")
+	if err != nil {
+		return errors.Wrap(err, "mktemp")
+	}
+	dst = strings.TrimSpace(dst)
+	defer func() {
+		if err := r.Runner.Run(fmt.Sprintf("sudo rm -f %s", command.ShellQuote(dst))); err != nil {
+			glog.Warningf("unable to remove %s: %v", dst, err)
+		}
+	}()
+	if err := r.Runner.Run(fmt.Sprintf("sudo podman save -o %s %s", command.ShellQuote(dst), command.ShellQuote(tag))); err != nil {
+		return errors.Wrap(err, "podman save")
+	}
+	// The image is only needed by containerd, so do not keep a second copy in the podman storage
+	if err := r.Runner.Run(fmt.Sprintf("sudo podman rmi %s", command.ShellQuote(tag))); err != nil {
+		glog.Warningf("unable to remove %s from podman: %v", tag, err)
+	}
+	return r.LoadImage(dst)
 }
 
 // KubeletOptions returns kubelet options for a containerd
@@ -118,8 +271,8 @@ func (r *Containerd) KubeletOptions() map[string]string {
 }
 
 // ListContainers returns a list of managed by this container runtime
-func (r *Containerd) ListContainers(filter string) ([]string, error) {
-	return listCRIContainers(r.Runner, filter)
+func (r *Containerd) ListContainers(o ListOptions) ([]string, error) {
+	return listCRIContainers(r.Runner, containerdRuncRoot, o)
 }
 
 // KillContainers removes containers based on ID
@@ -132,11 +285,26 @@ func (r *Containerd) StopContainers(ids []string) error {
 	return stopCRIContainers(r.Runner, ids)
 }
 
+// PauseContainers pauses a running container based on ID
+func (r *Containerd) PauseContainers(ids []string) error {
+	return pauseCRIContainers(r.Runner, containerdRuncRoot, ids)
+}
+
+// UnpauseContainers unpauses a paused container based on ID
+func (r *Containerd) UnpauseContainers(ids []string) error {
+	return unpauseCRIContainers(r.Runner, containerdRuncRoot, ids)
+}
+
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
 func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
 	return criContainerLogCmd(id, len, follow)
 }
 
+// ContainerExecCmd returns the command to run a shell command inside a container based on ID
+func (r *Containerd) ContainerExecCmd(id string, cmd string) string {
+	return criContainerExecCmd(id, cmd)
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *Containerd) SystemLogCmd(len int) string {
 	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// kubeletCredentialsPath is where the kubelet reads the credentials it passes to the runtime when pulling the images of pods
+const kubeletCredentialsPath = "/var/lib/kubelet/config.json"
+
+// dockerAuth is an entry of the auths of a docker config.json
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+// DockerConfigJSON returns credentials of private registries in the format of a docker config.json,
+// which is also read by the kubelet and CRI-O, and used by imagePullSecrets
+func DockerConfigJSON(creds map[string]config.RegistryCredential) ([]byte, error) {
+	auths := map[string]dockerAuth{}
+	for host, c := range creds {
+		auths[host] = dockerAuth{Auth: base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))}
+	}
+	return json.Marshal(map[string]map[string]dockerAuth{"auths": auths})
+}
+
+// dockerCredentialsPath is where the docker client of the root user reads the credentials it pulls images with
+const dockerCredentialsPath = "/root/.docker/config.json"
+
+// writeKubeletCredentials writes the credentials of private registries where the kubelet reads them
+func writeKubeletCredentials(cr CommandRunner, creds map[string]config.RegistryCredential) error {
+	data, err := DockerConfigJSON(creds)
+	if err != nil {
+		return err
+	}
+	_, err = updateFile(cr, kubeletCredentialsPath, data, "0600")
+	return err
+}
+
+// writeDockerCredentials replaces the auths of the docker client configuration, keeping its other settings
+func writeDockerCredentials(cr CommandRunner, creds map[string]config.RegistryCredential) error {
+	// The file may not exist yet, in which case the output is ignored
+	current, err := cr.CombinedOutput(fmt.Sprintf("sudo cat %s", dockerCredentialsPath))
+	if err != nil {
+		current = ""
+	}
+	data, err := mergeDockerCredentials(current, creds)
+	if err != nil {
+		return errors.Wrapf(err, "parsing %s", dockerCredentialsPath)
+	}
+	_, err = updateFile(cr, dockerCredentialsPath, data, "0600")
+	return err
+}
+
+// mergeDockerCredentials returns the docker client configuration of current, with the auths of creds added or replaced
+// per registry, so that the other registries and settings of current are kept
+func mergeDockerCredentials(current string, creds map[string]config.RegistryCredential) ([]byte, error) {
+	cfg := map[string]interface{}{}
+	if strings.TrimSpace(current) != "" {
+		if err := json.Unmarshal([]byte(current), &cfg); err != nil {
+			return nil, err
+		}
+	}
+	auths, ok := cfg["auths"].(map[string]interface{})
+	if !ok {
+		auths = map[string]interface{}{}
+	}
+	for host, c := range creds {
+		auths[host] = dockerAuth{Auth: base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))}
+	}
+	cfg["auths"] = auths
+	return json.Marshal(cfg)
+}
+
+// updateFile writes data to path unless it already has this content, and returns whether it was written
+func updateFile(cr CommandRunner, path string, data []byte, perms string) (bool, error) {
+	current, err := cr.CombinedOutput(fmt.Sprintf("sudo cat %s", path))
+	if err == nil && strings.TrimSpace(current) == strings.TrimSpace(string(data)) {
+		return false, nil
+	}
+	if err := cr.Copy(assets.NewMemoryAssetTarget(data, path, perms)); err != nil {
+		return false, errors.Wrapf(err, "copy %s", path)
+	}
+	return true, nil
+}
@@ -18,24 +18,31 @@ package cruntime
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"path"
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
 )
 
 // listCRIContainers returns a list of containers using crictl
-func listCRIContainers(cr CommandRunner, filter string) ([]string, error) {
-	var content string
-	var err error
-	state := "Running"
-	if filter != "" {
-		content, err = cr.CombinedOutput(fmt.Sprintf(`sudo crictl ps -a --name=%s --state=%s --quiet`, filter, state))
-	} else {
-		content, err = cr.CombinedOutput(fmt.Sprintf(`sudo crictl ps -a --state=%s --quiet`, state))
+func listCRIContainers(cr CommandRunner, root string, o ListOptions) ([]string, error) {
+	var args strings.Builder
+	args.WriteString("sudo crictl ps -a ")
+	if o.Name != "" {
+		args.WriteString(fmt.Sprintf("--name=%s ", o.Name))
 	}
+	// crictl has no notion of a paused container: they are reported as running.
+	if o.State != All {
+		args.WriteString("--state=Running ")
+	}
+	args.WriteString("--quiet")
+
+	content, err := cr.CombinedOutput(args.String())
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +52,68 @@ func listCRIContainers(cr CommandRunner, filter string) ([]string, error) {
 			ids = append(ids, line)
 		}
 	}
-	return ids, nil
+	if o.State != Paused || len(ids) == 0 {
+		return ids, nil
+	}
+
+	// only telling the paused containers apart requires runc, which the none driver may not have
+	paused, err := pausedRuncContainers(cr, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "paused")
+	}
+	var filtered []string
+	for _, id := range ids {
+		if paused[id] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+// pausedRuncContainers returns the set of container ID's which runc reports as paused
+func pausedRuncContainers(cr CommandRunner, root string) (map[string]bool, error) {
+	content, err := cr.CombinedOutput(fmt.Sprintf("sudo runc --root %s list -f json", root))
+	if err != nil {
+		return nil, err
+	}
+	type container struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	// runc prints "null" if there are no containers
+	var cs []container
+	if err := json.Unmarshal([]byte(content), &cs); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal %q", content)
+	}
+	paused := map[string]bool{}
+	for _, c := range cs {
+		if c.Status == "paused" {
+			paused[c.ID] = true
+		}
+	}
+	return paused, nil
+}
+
+// pauseCRIContainers pauses a list of containers using runc, as the CRI has no pause operation
+func pauseCRIContainers(cr CommandRunner, root string, ids []string) error {
+	glog.Infof("Pausing containers: %s", ids)
+	for _, id := range ids {
+		if err := cr.Run(fmt.Sprintf("sudo runc --root %s pause %s", root, id)); err != nil {
+			return errors.Wrapf(err, "pause %s", id)
+		}
+	}
+	return nil
+}
+
+// unpauseCRIContainers unpauses a list of containers using runc
+func unpauseCRIContainers(cr CommandRunner, root string, ids []string) error {
+	glog.Infof("Unpausing containers: %s", ids)
+	for _, id := range ids {
+		if err := cr.Run(fmt.Sprintf("sudo runc --root %s resume %s", root, id)); err != nil {
+			return errors.Wrapf(err, "resume %s", id)
+		}
+	}
+	return nil
 }
 
 // criCRIContainers kills a list of containers using crictl
@@ -98,3 +166,35 @@ func criContainerLogCmd(id string, len int, follow bool) string {
 	cmd.WriteString(id)
 	return cmd.String()
 }
+
+// criContainerExecCmd returns the command to run a shell command inside a container based on ID
+func criContainerExecCmd(id string, cmd string) string {
+	return fmt.Sprintf("sudo crictl exec %s /bin/sh -c %q", id, cmd)
+}
+
+// listCRIImages returns the tagged images known to a CRI runtime
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	content, err := cr.CombinedOutput("sudo crictl images -o json")
+	if err != nil {
+		return nil, errors.Wrapf(err, "crictl images: %s", content)
+	}
+	var list struct {
+		Images []struct {
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal([]byte(content), &list); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	var images []string
+	for _, i := range list.Images {
+		images = append(images, i.RepoTags...)
+	}
+	return images, nil
+}
+
+// removeCRIImage removes an image from a CRI runtime
+func removeCRIImage(cr CommandRunner, name string) error {
+	glog.Infof("Removing image: %s", name)
+	return cr.Run(fmt.Sprintf("sudo crictl rmi %s", command.ShellQuote(name)))
+}
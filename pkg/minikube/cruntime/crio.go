@@ -18,16 +18,26 @@ package cruntime
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+// crioRuncRoot is the runc state directory used by CRI-O, needed to pause containers
+const crioRuncRoot = "/run/runc"
+
 // CRIO contains CRIO runtime state
 type CRIO struct {
-	Socket string
-	Runner CommandRunner
+	Socket      string
+	Runner      CommandRunner
+	Mirror      string
+	Credentials map[string]config.RegistryCredential
 }
 
 // Name is a human readable name for CRIO
@@ -90,9 +100,204 @@ func (r *CRIO) Enable(disOthers bool) error {
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	if r.Mirror != "" {
+		if err := configureCRIOMirror(r.Runner, r.Mirror); err != nil {
+			return errors.Wrap(err, "registry mirror")
+		}
+	}
+	if r.Credentials != nil {
+		if _, err := configureCRIOCredentials(r.Runner, r.Credentials); err != nil {
+			return errors.Wrap(err, "registry credentials")
+		}
+	}
+	return r.Runner.Run("sudo systemctl restart crio")
+}
+
+// UpdateCredentials replaces the credentials of private registries, restarting CRI-O if its configuration changed
+func (r *CRIO) UpdateCredentials(creds map[string]config.RegistryCredential) error {
+	changed, err := configureCRIOCredentials(r.Runner, creds)
+	if err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	if !changed {
+		return nil
+	}
 	return r.Runner.Run("sudo systemctl restart crio")
 }
 
+// crioMirrorBegin and crioMirrorEnd delimit the registry mirror written by minikube within registries.conf
+const (
+	crioMirrorBegin = "# BEGIN minikube registry mirror"
+	crioMirrorEnd   = "# END minikube registry mirror"
+)
+
+// configureCRIOMirror adds a registry mirror for Docker Hub to registries.conf, keeping the other registries it configures
+func configureCRIOMirror(cr CommandRunner, mirror string) error {
+	cPath := "/etc/containers/registries.conf"
+	// The file may not exist yet, in which case the output is ignored
+	current, err := cr.CombinedOutput(fmt.Sprintf("sudo cat %s", cPath))
+	if err != nil {
+		current = ""
+	}
+	conf, err := mergeCRIOMirror(current, mirror)
+	if err != nil {
+		out.WarningT("Unable to configure CRI-O to use the registry mirror: {{.error}}", out.V{"error": err})
+		return nil
+	}
+	if conf == current {
+		return nil
+	}
+	glog.Infof("Configuring CRI-O to use the registry mirror at %s", mirror)
+	if err := cr.Copy(assets.NewMemoryAssetTarget([]byte(conf), cPath, "0644")); err != nil {
+		return errors.Wrapf(err, "copy %s", cPath)
+	}
+	return nil
+}
+
+// mergeCRIOMirror returns the registries.conf content with a mirror for Docker Hub, replacing the mirror previously
+// written by minikube. Configuration in the v1 format is converted to the v2 format, as the two can not be mixed.
+func mergeCRIOMirror(current string, mirror string) (string, error) {
+	u, err := url.Parse(mirror)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", mirror)
+	}
+
+	var lines []string
+	managed := false
+	for _, line := range strings.Split(current, "\n") {
+		switch strings.TrimSpace(line) {
+		case crioMirrorBegin:
+			managed = true
+			continue
+		case crioMirrorEnd:
+			managed = false
+			continue
+		}
+		if !managed {
+			lines = append(lines, line)
+		}
+	}
+	conf := strings.TrimSpace(strings.Join(lines, "\n"))
+	if isV1RegistriesConf(conf) {
+		conf = convertV1RegistriesConf(conf)
+	}
+	for _, line := range strings.Split(conf, "\n") {
+		f := strings.Fields(strings.Replace(line, "=", " = ", 1))
+		if len(f) >= 3 && (f[0] == "prefix" || f[0] == "location") && strings.Trim(f[2], `'"`) == "docker.io" {
+			return "", fmt.Errorf("docker.io is already configured, so the registry mirror can not be added")
+		}
+	}
+	// Top-level keys must precede the tables
+	if !strings.Contains(conf, "unqualified-search-registries") {
+		conf = strings.TrimSpace("unqualified-search-registries = ['docker.io']\n\n" + conf)
+	}
+
+	block := fmt.Sprintf(`%s
+[[registry]]
+prefix = 'docker.io'
+location = 'registry-1.docker.io'
+
+[[registry.mirror]]
+location = '%s'
+insecure = %t
+%s
+`, crioMirrorBegin, u.Host, u.Scheme == "http", crioMirrorEnd)
+	return conf + "\n\n" + block, nil
+}
+
+// isV1RegistriesConf returns whether a registries.conf uses the v1 format, such as the one installed in the minikube ISO
+func isV1RegistriesConf(conf string) bool {
+	for _, line := range strings.Split(conf, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "[registries.") {
+			return true
+		}
+	}
+	return false
+}
+
+// convertV1RegistriesConf converts the search, insecure and block lists of a v1 registries.conf into the v2 format.
+// Comments are kept, and Docker Hub entries are dropped, as Docker Hub is configured along with the mirror.
+func convertV1RegistriesConf(conf string) string {
+	lists := map[string][]string{}
+	var comments []string
+	section := ""
+	var value strings.Builder
+	for _, line := range strings.Split(conf, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case value.Len() > 0:
+			value.WriteString(trimmed)
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, line)
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			section = strings.Trim(trimmed, "[]")
+			continue
+		case strings.HasPrefix(trimmed, "registries"):
+			value.WriteString(strings.TrimSpace(strings.SplitN(trimmed, "=", 2)[1]))
+		default:
+			glog.Warningf("dropping unsupported registries.conf line: %s", line)
+			continue
+		}
+		if !strings.HasSuffix(value.String(), "]") {
+			continue
+		}
+		for _, item := range strings.Split(strings.Trim(value.String(), "[]"), ",") {
+			item = strings.Trim(strings.TrimSpace(item), `'"`)
+			if item != "" && (section == "registries.search" || (item != "docker.io" && item != "registry-1.docker.io")) {
+				lists[section] = append(lists[section], item)
+			}
+		}
+		value.Reset()
+	}
+
+	var quoted []string
+	for _, r := range lists["registries.search"] {
+		quoted = append(quoted, fmt.Sprintf("'%s'", r))
+	}
+	out := append(comments, fmt.Sprintf("unqualified-search-registries = [%s]", strings.Join(quoted, ", ")))
+	for _, r := range lists["registries.insecure"] {
+		out = append(out, "", "[[registry]]", fmt.Sprintf("location = '%s'", r), "insecure = true")
+	}
+	for _, r := range lists["registries.block"] {
+		out = append(out, "", "[[registry]]", fmt.Sprintf("location = '%s'", r), "blocked = true")
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// configureCRIOCredentials writes the credentials of private registries to an auth.json used for every pull by CRI-O,
+// and returns whether the configuration of CRI-O changed
+func configureCRIOCredentials(cr CommandRunner, creds map[string]config.RegistryCredential) (bool, error) {
+	if err := writeKubeletCredentials(cr, creds); err != nil {
+		return false, err
+	}
+	authPath := "/etc/crio/auth.json"
+	data, err := DockerConfigJSON(creds)
+	if err != nil {
+		return false, err
+	}
+	changed, err := updateFile(cr, authPath, data, "0600")
+	if err != nil {
+		return false, err
+	}
+	cPath := "/etc/crio/crio.conf"
+	setting := fmt.Sprintf(`global_auth_file = "%s"`, authPath)
+	if err := cr.Run(fmt.Sprintf("sudo grep -qx '%s' %s", setting, cPath)); err == nil {
+		return changed, nil
+	}
+	if err := cr.Run(fmt.Sprintf(`sudo sed -i 's|^global_auth_file = .*$|%s|' %s`, setting, cPath)); err != nil {
+		return false, err
+	}
+	// sed succeeds without changes when there is no global_auth_file setting to replace
+	if err := cr.Run(fmt.Sprintf("sudo grep -qx '%s' %s", setting, cPath)); err != nil {
+		glog.Warningf("%s has no global_auth_file setting, unable to configure %s", cPath, authPath)
+		return changed, nil
+	}
+	return true, nil
+}
+
 // Disable idempotently disables CRIO on a host
 func (r *CRIO) Disable() error {
 	return r.Runner.Run("sudo systemctl stop crio")
@@ -104,6 +309,22 @@ func (r *CRIO) LoadImage(path string) error {
 	return r.Runner.Run(fmt.Sprintf("sudo podman load -i %s", path))
 }
 
+// ListImages returns the names of the images known to the runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from the runtime
+func (r *CRIO) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// BuildImage builds an image from a build context directory, and tags it
+func (r *CRIO) BuildImage(dir string, tag string) error {
+	glog.Infof("Building image %s from %s", tag, dir)
+	return r.Runner.Run(fmt.Sprintf("sudo podman build -t %s %s", command.ShellQuote(tag), command.ShellQuote(dir)))
+}
+
 // KubeletOptions returns kubelet options for a runtime.
 func (r *CRIO) KubeletOptions() map[string]string {
 	return map[string]string{
@@ -115,8 +336,8 @@ func (r *CRIO) KubeletOptions() map[string]string {
 }
 
 // ListContainers returns a list of managed by this container runtime
-func (r *CRIO) ListContainers(filter string) ([]string, error) {
-	return listCRIContainers(r.Runner, filter)
+func (r *CRIO) ListContainers(o ListOptions) ([]string, error) {
+	return listCRIContainers(r.Runner, crioRuncRoot, o)
 }
 
 // KillContainers removes containers based on ID
@@ -129,11 +350,26 @@ func (r *CRIO) StopContainers(ids []string) error {
 	return stopCRIContainers(r.Runner, ids)
 }
 
+// PauseContainers pauses a running container based on ID
+func (r *CRIO) PauseContainers(ids []string) error {
+	return pauseCRIContainers(r.Runner, crioRuncRoot, ids)
+}
+
+// UnpauseContainers unpauses a paused container based on ID
+func (r *CRIO) UnpauseContainers(ids []string) error {
+	return unpauseCRIContainers(r.Runner, crioRuncRoot, ids)
+}
+
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
 func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
 	return criContainerLogCmd(id, len, follow)
 }
 
+// ContainerExecCmd returns the command to run a shell command inside a container based on ID
+func (r *CRIO) ContainerExecCmd(id string, cmd string) string {
+	return criContainerExecCmd(id, cmd)
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *CRIO) SystemLogCmd(len int) string {
 	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
@@ -22,13 +22,41 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+// ContainerState is the run state of a container
+type ContainerState int
+
+const (
+	// All is all of the containers
+	All ContainerState = iota
+	// Running is only running containers, including the paused ones
+	Running
+	// Paused is only paused containers
+	Paused
+)
+
+// String converts a ContainerState to a string
+func (cs ContainerState) String() string {
+	return [...]string{"all", "running", "paused"}[cs]
+}
+
+// ListOptions are the options to use for listing containers
+type ListOptions struct {
+	// State is the container state to filter by (All, Running, Paused)
+	State ContainerState
+	// Name is a name filter
+	Name string
+}
+
 // CommandRunner is the subset of command.Runner this package consumes
 type CommandRunner interface {
 	Run(string) error
 	CombinedOutput(string) (string, error)
+	Copy(assets.CopyableFile) error
 }
 
 // Manager is a common interface for container runtimes
@@ -39,6 +67,8 @@ type Manager interface {
 	Version() (string, error)
 	// Enable idempotently enables this runtime on a host
 	Enable(bool) error
+	// UpdateCredentials replaces the credentials of private registries, restarting the runtime only if needed
+	UpdateCredentials(map[string]config.RegistryCredential) error
 	// Disable idempotently disables this runtime on a host
 	Disable() error
 	// Active returns whether or not a runtime is active on a host
@@ -57,15 +87,27 @@ type Manager interface {
 
 	// Load an image idempotently into the runtime on a host
 	LoadImage(string) error
+	// ListImages returns the names of the images known to the runtime, such as k8s.gcr.io/pause:3.1
+	ListImages() ([]string, error)
+	// RemoveImage removes an image from the runtime
+	RemoveImage(string) error
+	// BuildImage builds an image from a build context directory on the host, and tags it
+	BuildImage(dir string, tag string) error
 
 	// ListContainers returns a list of managed by this container runtime
-	ListContainers(string) ([]string, error)
+	ListContainers(ListOptions) ([]string, error)
 	// KillContainers removes containers based on ID
 	KillContainers([]string) error
 	// StopContainers stops containers based on ID
 	StopContainers([]string) error
+	// PauseContainers pauses running containers based on ID
+	PauseContainers([]string) error
+	// UnpauseContainers unpauses paused containers based on ID
+	UnpauseContainers([]string) error
 	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
 	ContainerLogCmd(string, int, bool) string
+	// ContainerExecCmd returns the command to run a shell command inside a container based on ID
+	ContainerExecCmd(string, string) string
 	// SystemLogCmd returns the command to return the system logs
 	SystemLogCmd(int) string
 }
@@ -78,17 +120,24 @@ type Config struct {
 	Socket string
 	// Runner is the CommandRunner object to execute commands with
 	Runner CommandRunner
+	// Mirror is the URL of a registry mirror for Docker Hub images, configured when the runtime is enabled
+	Mirror string
+	// Credentials are the credentials of private registries by host, configured when the runtime is enabled.
+	// If nil, the credentials configured previously are left untouched.
+	Credentials map[string]config.RegistryCredential
 }
 
 // New returns an appropriately configured runtime
 func New(c Config) (Manager, error) {
 	switch c.Type {
 	case "", "docker":
-		return &Docker{Socket: c.Socket, Runner: c.Runner}, nil
+		return &Docker{Socket: c.Socket, Runner: c.Runner, Mirror: c.Mirror, Credentials: c.Credentials}, nil
 	case "crio", "cri-o":
-		return &CRIO{Socket: c.Socket, Runner: c.Runner}, nil
+		return &CRIO{Socket: c.Socket, Runner: c.Runner, Mirror: c.Mirror, Credentials: c.Credentials}, nil
 	case "containerd":
-		return &Containerd{Socket: c.Socket, Runner: c.Runner}, nil
+		return &Containerd{Socket: c.Socket, Runner: c.Runner, Mirror: c.Mirror, Credentials: c.Credentials}, nil
+	case "podman":
+		return &Podman{Socket: c.Socket, Runner: c.Runner, Mirror: c.Mirror, Credentials: c.Credentials}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
 	}
@@ -97,15 +146,15 @@ func New(c Config) (Manager, error) {
 // disableOthers disables all other runtimes except for me.
 func disableOthers(me Manager, cr CommandRunner) error {
 	// valid values returned by manager.Name()
-	runtimes := []string{"containerd", "crio", "docker"}
+	runtimes := []string{"containerd", "crio", "docker", "podman"}
 	for _, name := range runtimes {
 		r, err := New(Config{Type: name, Runner: cr})
 		if err != nil {
 			return fmt.Errorf("runtime(%s): %v", name, err)
 		}
 
-		// Don't disable myself.
-		if r.Name() == me.Name() {
+		// Don't disable myself, or a runtime serving my socket, as CRI-O does for podman.
+		if r.Name() == me.Name() || r.SocketPath() == me.SocketPath() {
 			continue
 		}
 		// runtime is already disabled, nothing to do.
@@ -17,12 +17,17 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"path"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
 )
 
 func TestName(t *testing.T) {
@@ -35,6 +40,7 @@ func TestName(t *testing.T) {
 		{"crio", "CRI-O"},
 		{"cri-o", "CRI-O"},
 		{"containerd", "containerd"},
+		{"podman", "Podman"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -68,6 +74,12 @@ func TestKubeletOptions(t *testing.T) {
 			"image-service-endpoint":     "unix:///run/containerd/containerd.sock",
 			"runtime-request-timeout":    "15m",
 		}},
+		{"podman", map[string]string{
+			"container-runtime":          "remote",
+			"container-runtime-endpoint": "/var/run/crio/crio.sock",
+			"image-service-endpoint":     "/var/run/crio/crio.sock",
+			"runtime-request-timeout":    "15m",
+		}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -84,12 +96,66 @@ func TestKubeletOptions(t *testing.T) {
 	}
 }
 
+func TestContainerExecCmd(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    string
+	}{
+		{"docker", `docker exec abc0 /bin/sh -c "etcdctl version"`},
+		{"crio", `sudo crictl exec abc0 /bin/sh -c "etcdctl version"`},
+		{"containerd", `sudo crictl exec abc0 /bin/sh -c "etcdctl version"`},
+		{"podman", `sudo crictl exec abc0 /bin/sh -c "etcdctl version"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			r, err := New(Config{Type: tc.runtime})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if got := r.ContainerExecCmd("abc0", "etcdctl version"); got != tc.want {
+				t.Errorf("ContainerExecCmd(%s) = %q, want %q", tc.runtime, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	for _, runtime := range []string{"docker", "crio", "containerd", "podman"} {
+		t.Run(runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.images = map[string]bool{"k8s.gcr.io/pause:3.1": true, "busybox:latest": true}
+			r, err := New(Config{Type: runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", runtime, err)
+			}
+
+			if err := r.RemoveImage("busybox:latest"); err != nil {
+				t.Fatalf("RemoveImage: %v", err)
+			}
+			got, err := r.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			if diff := cmp.Diff([]string{"k8s.gcr.io/pause:3.1"}, got); diff != "" {
+				t.Errorf("ListImages(%s) returned diff (-want +got):\n%s", runtime, diff)
+			}
+
+			if err := r.BuildImage("/var/lib/minikube/build/app", "example.com/app:v1"); err != nil {
+				t.Fatalf("BuildImage: %v", err)
+			}
+			if !runner.images["example.com/app:v1"] {
+				t.Errorf("BuildImage(%s) did not add the image, images: %v", runtime, runner.images)
+			}
+		})
+	}
+}
+
 type serviceState int
 
 const (
-	Exited serviceState = iota
-	Running
-	Restarted
+	SvcExited serviceState = iota
+	SvcRunning
+	SvcRestarted
 )
 
 // FakeRunner is a command runner that isn't very smart.
@@ -97,6 +163,9 @@ type FakeRunner struct {
 	cmds       []string
 	services   map[string]serviceState
 	containers map[string]string
+	paused     map[string]bool
+	images     map[string]bool
+	files      map[string]string
 	t          *testing.T
 }
 
@@ -107,6 +176,9 @@ func NewFakeRunner(t *testing.T) *FakeRunner {
 		cmds:       []string{},
 		t:          t,
 		containers: map[string]string{},
+		paused:     map[string]bool{},
+		images:     map[string]bool{},
+		files:      map[string]string{},
 	}
 }
 
@@ -133,6 +205,16 @@ func (f *FakeRunner) CombinedOutput(cmd string) (string, error) {
 		return f.crio(args, root)
 	case "containerd":
 		return f.containerd(args, root)
+	case "runc":
+		return f.runc(args, root)
+	case "podman":
+		return f.podman(args, root)
+	case "ctr":
+		return f.ctr(args, root)
+	case "mktemp":
+		return "/var/tmp/minikube-image.abc123\n", nil
+	case "cat":
+		return f.files[args[0]], nil
 	default:
 		return "", nil
 	}
@@ -144,20 +226,38 @@ func (f *FakeRunner) Run(cmd string) error {
 	return err
 }
 
+// Copy a fake file!
+func (f *FakeRunner) Copy(file assets.CopyableFile) error {
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	f.files[path.Join(file.GetTargetDir(), file.GetTargetName())] = string(data)
+	return nil
+}
+
 // docker is a fake implementation of docker
 func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
-	switch cmd := args[0]; cmd {
+	cmd := strings.Join(args, " ")
+	switch args[0] {
 	case "ps":
 		// ps -a --filter="name=apiserver" --format="{{.ID}}"
 		if args[1] == "-a" && strings.HasPrefix(args[2], "--filter") {
 			filter := strings.Split(args[2], `"`)[1]
 			fname := strings.Split(filter, "=")[1]
 			ids := []string{}
+			// status filters are OR'ed, as with docker
+			wantPaused := strings.Contains(cmd, "status=paused")
+			wantRunning := strings.Contains(cmd, "status=running")
 			f.t.Logf("fake docker: Looking for containers matching %q", fname)
 			for id, cname := range f.containers {
-				if strings.Contains(cname, fname) {
-					ids = append(ids, id)
+				if !strings.Contains(cname, fname) {
+					continue
 				}
+				if (wantPaused || wantRunning) && !(wantPaused && f.paused[id]) && !(wantRunning && !f.paused[id]) {
+					continue
+				}
+				ids = append(ids, id)
 			}
 			f.t.Logf("fake docker: Found containers: %v", ids)
 			return strings.Join(ids, "\n"), nil
@@ -180,11 +280,93 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 			delete(f.containers, id)
 
 		}
+	case "pause":
+		for _, id := range args[1:] {
+			f.t.Logf("fake docker: Pausing id %q", id)
+			f.paused[id] = true
+		}
+	case "unpause":
+		for _, id := range args[1:] {
+			f.t.Logf("fake docker: Unpausing id %q", id)
+			delete(f.paused, id)
+		}
 	case "version":
 		if args[1] == "--format" && args[2] == "'{{.Server.Version}}'" {
 			return "18.06.2-ce", nil
 		}
+	case "images":
+		// images --format="{{.Repository}}:{{.Tag}}"
+		names := []string{"<none>:<none>"}
+		for name := range f.images {
+			names = append(names, name)
+		}
+		return strings.Join(names, "\n"), nil
+	case "rmi":
+		for _, name := range args[1:] {
+			name = strings.Trim(name, "'")
+			f.t.Logf("fake docker: Removing image %q", name)
+			if !f.images[name] {
+				return "", fmt.Errorf("no such image")
+			}
+			delete(f.images, name)
+		}
+	case "build":
+		// build -t 'tag' 'dir'
+		tag := strings.Trim(args[2], "'")
+		f.t.Logf("fake docker: Building image %q from %s", tag, args[3])
+		f.images[tag] = true
+
+	}
+	return "", nil
+}
 
+// podman is a fake implementation of podman
+func (f *FakeRunner) podman(args []string, _ bool) (string, error) {
+	switch cmd := args[0]; cmd {
+	case "--version":
+		return "podman version 1.4.4", nil
+	case "images":
+		// images --format="{{.Repository}}:{{.Tag}}"
+		names := []string{"<none>:<none>"}
+		for name := range f.images {
+			names = append(names, name)
+		}
+		return strings.Join(names, "\n"), nil
+	case "rmi":
+		for _, name := range args[1:] {
+			name = strings.Trim(name, "'")
+			f.t.Logf("fake podman: Removing image %q", name)
+			if !f.images[name] {
+				return "", fmt.Errorf("no such image")
+			}
+			delete(f.images, name)
+		}
+	case "build":
+		// build -t 'tag' 'dir'
+		tag := strings.Trim(args[2], "'")
+		f.t.Logf("fake podman: Building image %q from %s", tag, args[3])
+		f.images[tag] = true
+	case "save":
+		// save -o 'path' 'tag'
+		tag := strings.Trim(args[3], "'")
+		if !f.images[tag] {
+			return "", fmt.Errorf("no such image")
+		}
+		f.files[strings.Trim(args[2], "'")] = tag
+	}
+	return "", nil
+}
+
+// ctr is a fake implementation of ctr
+func (f *FakeRunner) ctr(args []string, _ bool) (string, error) {
+	// ctr -n=k8s.io images import 'path'
+	if len(args) == 4 && args[1] == "images" && args[2] == "import" {
+		name := f.files[strings.Trim(args[3], "'")]
+		if name == "" {
+			return "", fmt.Errorf("no such file")
+		}
+		f.t.Logf("fake ctr: Importing image %q", name)
+		f.images[name] = true
 	}
 	return "", nil
 }
@@ -247,7 +429,53 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 			delete(f.containers, id)
 
 		}
+	case "images":
+		// crictl images -o json
+		var tags []string
+		for name := range f.images {
+			tags = append(tags, fmt.Sprintf("%q", name))
+		}
+		return fmt.Sprintf(`{"images": [{"repoTags": [%s]}, {"repoTags": []}]}`, strings.Join(tags, ",")), nil
+	case "rmi":
+		for _, name := range args[1:] {
+			name = strings.Trim(name, "'")
+			f.t.Logf("fake crictl: Removing image %q", name)
+			if !f.images[name] {
+				return "", fmt.Errorf("no such image")
+			}
+			delete(f.images, name)
+		}
+
+	}
+	return "", nil
+}
 
+// runc is a fake implementation of runc
+func (f *FakeRunner) runc(args []string, _ bool) (string, error) {
+	// runc --root /run/runc <command> [args]
+	if args[0] == "--root" {
+		args = args[2:]
+	}
+	switch args[0] {
+	case "list":
+		var cs []string
+		for id := range f.containers {
+			status := "running"
+			if f.paused[id] {
+				status = "paused"
+			}
+			cs = append(cs, fmt.Sprintf(`{"id": %q, "status": %q}`, id, status))
+		}
+		if len(cs) == 0 {
+			return "null", nil
+		}
+		return "[" + strings.Join(cs, ",") + "]", nil
+	case "pause":
+		f.t.Logf("fake runc: Pausing id %q", args[1])
+		f.paused[args[1]] = true
+	case "resume":
+		f.t.Logf("fake runc: Resuming id %q", args[1])
+		delete(f.paused, args[1])
 	}
 	return "", nil
 }
@@ -276,23 +504,23 @@ func (f *FakeRunner) systemctl(args []string, root bool) (string, error) {
 			if !root {
 				return out, fmt.Errorf("not root")
 			}
-			f.services[svc] = Exited
+			f.services[svc] = SvcExited
 			f.t.Logf("fake systemctl: stopped %s", svc)
 		case "start":
 			if !root {
 				return out, fmt.Errorf("not root")
 			}
-			f.services[svc] = Running
+			f.services[svc] = SvcRunning
 			f.t.Logf("fake systemctl: started %s", svc)
 		case "restart":
 			if !root {
 				return out, fmt.Errorf("not root")
 			}
-			f.services[svc] = Restarted
+			f.services[svc] = SvcRestarted
 			f.t.Logf("fake systemctl: restarted %s", svc)
 		case "is-active":
 			f.t.Logf("fake systemctl: %s is-status: %v", svc, state)
-			if state == Running {
+			if state == SvcRunning {
 				return out, nil
 			}
 			return out, fmt.Errorf("%s in state: %v", svc, state)
@@ -311,6 +539,7 @@ func TestVersion(t *testing.T) {
 		{"docker", "18.06.2-ce"},
 		{"cri-o", "1.13.0"},
 		{"containerd", "1.2.0"},
+		{"podman", "1.4.4"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -332,11 +561,12 @@ func TestVersion(t *testing.T) {
 
 // defaultServices reflects the default boot state for the minikube VM
 var defaultServices = map[string]serviceState{
-	"docker":        Running,
-	"docker.socket": Running,
-	"crio":          Exited,
-	"crio-shutdown": Exited,
-	"containerd":    Exited,
+	"docker":           SvcRunning,
+	"docker.socket":    SvcRunning,
+	"crio":             SvcExited,
+	"crio-shutdown":    SvcExited,
+	"containerd":       SvcExited,
+	"io.podman.socket": SvcExited,
 }
 
 func TestDisable(t *testing.T) {
@@ -347,6 +577,7 @@ func TestDisable(t *testing.T) {
 		{"docker", []string{"sudo systemctl stop docker docker.socket"}},
 		{"crio", []string{"sudo systemctl stop crio"}},
 		{"containerd", []string{"sudo systemctl stop containerd"}},
+		{"podman", []string{"sudo systemctl stop io.podman.socket"}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.runtime, func(t *testing.T) {
@@ -375,25 +606,36 @@ func TestEnable(t *testing.T) {
 		want    map[string]serviceState
 	}{
 		{"docker", map[string]serviceState{
-			"docker":        Running,
-			"docker.socket": Running,
-			"containerd":    Exited,
-			"crio":          Exited,
-			"crio-shutdown": Exited,
+			"docker":           SvcRunning,
+			"docker.socket":    SvcRunning,
+			"containerd":       SvcExited,
+			"crio":             SvcExited,
+			"crio-shutdown":    SvcExited,
+			"io.podman.socket": SvcExited,
 		}},
 		{"containerd", map[string]serviceState{
-			"docker":        Exited,
-			"docker.socket": Exited,
-			"containerd":    Restarted,
-			"crio":          Exited,
-			"crio-shutdown": Exited,
+			"docker":           SvcExited,
+			"docker.socket":    SvcExited,
+			"containerd":       SvcRestarted,
+			"crio":             SvcExited,
+			"crio-shutdown":    SvcExited,
+			"io.podman.socket": SvcExited,
 		}},
 		{"crio", map[string]serviceState{
-			"docker":        Exited,
-			"docker.socket": Exited,
-			"containerd":    Exited,
-			"crio":          Restarted,
-			"crio-shutdown": Exited,
+			"docker":           SvcExited,
+			"docker.socket":    SvcExited,
+			"containerd":       SvcExited,
+			"crio":             SvcRestarted,
+			"crio-shutdown":    SvcExited,
+			"io.podman.socket": SvcExited,
+		}},
+		{"podman", map[string]serviceState{
+			"docker":           SvcExited,
+			"docker.socket":    SvcExited,
+			"containerd":       SvcExited,
+			"crio":             SvcRestarted,
+			"crio-shutdown":    SvcExited,
+			"io.podman.socket": SvcRunning,
 		}},
 	}
 	for _, tc := range tests {
@@ -417,6 +659,289 @@ func TestEnable(t *testing.T) {
 	}
 }
 
+func TestEnableMirror(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		config  string
+	}{
+		{"docker", "/etc/docker/daemon.json"},
+		{"containerd", "/etc/containerd/config.toml"},
+		{"crio", "/etc/containers/registries.conf"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			for k, v := range defaultServices {
+				runner.services[k] = v
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner, Mirror: "http://192.168.39.1:5050"})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.Enable(true); err != nil {
+				t.Errorf("%s enable unexpected error: %v", tc.runtime, err)
+			}
+
+			configured := strings.Contains(runner.files[tc.config], "192.168.39.1:5050")
+			for _, cmd := range runner.cmds {
+				if strings.Contains(cmd, tc.config) && strings.Contains(cmd, "192.168.39.1:5050") {
+					configured = true
+				}
+			}
+			if !configured {
+				t.Errorf("%s was not configured to use the mirror, commands: %v", tc.config, runner.cmds)
+			}
+			if runner.services[tc.runtime] != SvcRestarted {
+				t.Errorf("%s was not restarted: %v", tc.runtime, runner.services[tc.runtime])
+			}
+		})
+	}
+}
+
+func TestMergeDockerMirror(t *testing.T) {
+	var tests = []struct {
+		description string
+		current     string
+		want        string
+	}{
+		{"no configuration", "", `{"registry-mirrors":["http://192.168.39.1:5050"]}`},
+		{
+			description: "other settings are kept",
+			current:     `{"log-driver": "journald", "registry-mirrors": ["https://mirror.example.com"]}`,
+			want:        `{"log-driver":"journald","registry-mirrors":["http://192.168.39.1:5050","https://mirror.example.com"]}`,
+		},
+		{
+			description: "a previous mirror on the host is replaced",
+			current:     `{"registry-mirrors": ["http://192.168.39.1:5051"]}`,
+			want:        `{"registry-mirrors":["http://192.168.39.1:5050"]}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := mergeDockerMirror(tc.current, "http://192.168.39.1:5050")
+			if err != nil {
+				t.Fatalf("mergeDockerMirror: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("mergeDockerMirror() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+	if _, err := mergeDockerMirror("{", "http://192.168.39.1:5050"); err == nil {
+		t.Errorf("mergeDockerMirror of invalid JSON succeeded, expected error")
+	}
+}
+
+func TestMergeCRIOMirror(t *testing.T) {
+	mirror := `# BEGIN minikube registry mirror
+[[registry]]
+prefix = 'docker.io'
+location = 'registry-1.docker.io'
+
+[[registry.mirror]]
+location = '192.168.39.1:5050'
+insecure = true
+# END minikube registry mirror
+`
+	var tests = []struct {
+		description string
+		current     string
+		want        string
+	}{
+		{"no configuration", "", "unqualified-search-registries = ['docker.io']\n\n" + mirror},
+		{
+			description: "v1 configuration of the ISO",
+			current:     "# registries\n[registries.search]\nregistries = ['docker.io', 'quay.io']\n\n[registries.insecure]\nregistries = []\n",
+			want:        "# registries\nunqualified-search-registries = ['docker.io', 'quay.io']\n\n" + mirror,
+		},
+		{
+			description: "v1 insecure and blocked registries",
+			current:     "[registries.search]\nregistries = ['docker.io']\n[registries.insecure]\nregistries = [\n  'registry.local:5000',\n  'docker.io',\n]\n[registries.block]\nregistries = ['evil.example.com']\n",
+			want: "unqualified-search-registries = ['docker.io']\n\n[[registry]]\nlocation = 'registry.local:5000'\ninsecure = true\n\n" +
+				"[[registry]]\nlocation = 'evil.example.com'\nblocked = true\n\n" + mirror,
+		},
+		{
+			description: "v2 configuration with a previous mirror",
+			current:     "unqualified-search-registries = ['quay.io']\n\n[[registry]]\nlocation = 'registry.local:5000'\ninsecure = true\n\n" + strings.Replace(mirror, "5050", "5051", 1),
+			want:        "unqualified-search-registries = ['quay.io']\n\n[[registry]]\nlocation = 'registry.local:5000'\ninsecure = true\n\n" + mirror,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := mergeCRIOMirror(tc.current, "http://192.168.39.1:5050")
+			if err != nil {
+				t.Fatalf("mergeCRIOMirror: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("mergeCRIOMirror() diff (-want +got):\n%s", diff)
+			}
+			again, err := mergeCRIOMirror(got, "http://192.168.39.1:5050")
+			if err != nil || again != got {
+				t.Errorf("mergeCRIOMirror() is not idempotent: %q, %v", again, err)
+			}
+		})
+	}
+	if _, err := mergeCRIOMirror("[[registry]]\nprefix = 'docker.io'\nlocation = 'hub.example.com'\n", "http://192.168.39.1:5050"); err == nil {
+		t.Errorf("mergeCRIOMirror with docker.io already configured succeeded, expected error")
+	}
+}
+
+func TestEnableCredentials(t *testing.T) {
+	creds := map[string]config.RegistryCredential{"harbor.example.com": {Username: "dev", Password: "secret"}}
+	auth := base64.StdEncoding.EncodeToString([]byte("dev:secret"))
+	var tests = []struct {
+		runtime string
+		files   []string
+	}{
+		{"docker", []string{"/var/lib/kubelet/config.json", "/root/.docker/config.json"}},
+		{"containerd", []string{"/var/lib/kubelet/config.json"}},
+		{"crio", []string{"/var/lib/kubelet/config.json", "/etc/crio/auth.json"}},
+		{"podman", []string{"/var/lib/kubelet/config.json", "/etc/crio/auth.json"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			for k, v := range defaultServices {
+				runner.services[k] = v
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner, Credentials: creds})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.Enable(true); err != nil {
+				t.Errorf("%s enable unexpected error: %v", tc.runtime, err)
+			}
+			for _, f := range tc.files {
+				if !strings.Contains(runner.files[f], auth) {
+					t.Errorf("%s = %q, want it to contain the credentials", f, runner.files[f])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCredentials(t *testing.T) {
+	creds := map[string]config.RegistryCredential{"harbor.example.com": {Username: "dev", Password: "secret"}}
+	var tests = []struct {
+		runtime string
+		service string
+	}{
+		{"docker", ""},
+		{"containerd", "containerd"},
+		{"crio", "crio"},
+		{"podman", "crio"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			for k, v := range defaultServices {
+				runner.services[k] = v
+			}
+			runner.files["/etc/containerd/config.toml"] = "[plugins.cri.registry]\n"
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.UpdateCredentials(creds); err != nil {
+				t.Fatalf("%s UpdateCredentials unexpected error: %v", tc.runtime, err)
+			}
+			for svc, state := range runner.services {
+				want := defaultServices[svc]
+				if svc == tc.service {
+					want = SvcRestarted
+				}
+				if state != want {
+					t.Errorf("%s is %v after the first update, want %v", svc, state, want)
+				}
+			}
+
+			// The runtime is left alone when the credentials did not change
+			for k, v := range defaultServices {
+				runner.services[k] = v
+			}
+			if err := cr.UpdateCredentials(creds); err != nil {
+				t.Fatalf("%s UpdateCredentials unexpected error: %v", tc.runtime, err)
+			}
+			if diff := cmp.Diff(defaultServices, runner.services); diff != "" {
+				t.Errorf("services changed by an update with the same credentials (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeDockerCredentials(t *testing.T) {
+	creds := map[string]config.RegistryCredential{"harbor.example.com": {Username: "dev", Password: "secret"}}
+	auth := base64.StdEncoding.EncodeToString([]byte("dev:secret"))
+	current := `{"auths":{"old.example.com":{"auth":"b2xkOm9sZA=="}},"credsStore":"secretservice"}`
+	want := `{"auths":{"harbor.example.com":{"auth":"` + auth + `"},"old.example.com":{"auth":"b2xkOm9sZA=="}},"credsStore":"secretservice"}`
+
+	got, err := mergeDockerCredentials(current, creds)
+	if err != nil {
+		t.Fatalf("mergeDockerCredentials: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mergeDockerCredentials() returned diff (-want +got):\n%s", diff)
+	}
+	current = `{"auths":{"harbor.example.com":{"auth":"b2xkOm9sZA=="}}}`
+	want = `{"auths":{"harbor.example.com":{"auth":"` + auth + `"}}}`
+	got, err = mergeDockerCredentials(current, creds)
+	if err != nil {
+		t.Fatalf("mergeDockerCredentials: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mergeDockerCredentials() of an existing registry returned diff (-want +got):\n%s", diff)
+	}
+	if _, err := mergeDockerCredentials("{", creds); err == nil {
+		t.Errorf("mergeDockerCredentials() of invalid JSON should fail")
+	}
+}
+
+func TestContainerdAuthConfig(t *testing.T) {
+	base := `[plugins]
+  [plugins.cri]
+    [plugins.cri.registry]
+      [plugins.cri.registry.mirrors]
+        [plugins.cri.registry.mirrors."docker.io"]
+          endpoint = ["https://registry-1.docker.io"]
+  [plugins.diff-service]
+    default = ["walking"]
+`
+	creds := map[string]config.RegistryCredential{"b.example.com": {Username: "u", Password: "p"}, "a.example.com:8443": {Username: "dev", Password: "s\"q"}}
+	want := `[plugins]
+  [plugins.cri]
+    [plugins.cri.registry]
+      [plugins.cri.registry.auths."https://a.example.com:8443"]
+        username = "dev"
+        password = "s\"q"
+      [plugins.cri.registry.auths."https://b.example.com"]
+        username = "u"
+        password = "p"
+      [plugins.cri.registry.mirrors]
+        [plugins.cri.registry.mirrors."docker.io"]
+          endpoint = ["https://registry-1.docker.io"]
+  [plugins.diff-service]
+    default = ["walking"]
+`
+
+	got := containerdAuthConfig(base, creds)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("containerdAuthConfig() returned diff (-want +got):\n%s", diff)
+	}
+	// Credentials are replaced rather than appended
+	if again := containerdAuthConfig(got, creds); again != want {
+		t.Errorf("containerdAuthConfig() is not idempotent:\n%s", again)
+	}
+	if cleared := containerdAuthConfig(got, nil); cleared != base {
+		t.Errorf("containerdAuthConfig(nil) = %q", cleared)
+	}
+	// A configuration without a registry table gets one
+	noRegistry := "[plugins.linux]\n  shim = \"containerd-shim\"\n"
+	wantAdded := noRegistry + "[plugins.cri.registry]\n  [plugins.cri.registry.auths.\"https://b.example.com\"]\n    username = \"u\"\n    password = \"p\"\n"
+	if added := containerdAuthConfig(noRegistry, map[string]config.RegistryCredential{"b.example.com": {Username: "u", Password: "p"}}); added != wantAdded {
+		t.Errorf("containerdAuthConfig() without a registry table = %q, want %q", added, wantAdded)
+	}
+}
+
 func TestContainerFunctions(t *testing.T) {
 	var tests = []struct {
 		runtime string
@@ -424,6 +949,7 @@ func TestContainerFunctions(t *testing.T) {
 		{"docker"},
 		{"crio"},
 		{"containerd"},
+		{"podman"},
 	}
 
 	sortSlices := cmpopts.SortSlices(func(a, b string) bool { return a < b })
@@ -445,7 +971,7 @@ func TestContainerFunctions(t *testing.T) {
 			}
 
 			// Get the list of apiservers
-			got, err := cr.ListContainers("apiserver")
+			got, err := cr.ListContainers(ListOptions{Name: "apiserver"})
 			if err != nil {
 				t.Fatalf("ListContainers: %v", err)
 			}
@@ -458,7 +984,7 @@ func TestContainerFunctions(t *testing.T) {
 			if err := cr.StopContainers(got); err != nil {
 				t.Fatalf("stop failed: %v", err)
 			}
-			got, err = cr.ListContainers("apiserver")
+			got, err = cr.ListContainers(ListOptions{Name: "apiserver"})
 			if err != nil {
 				t.Fatalf("ListContainers: %v", err)
 			}
@@ -468,7 +994,7 @@ func TestContainerFunctions(t *testing.T) {
 			}
 
 			// Get the list of everything else.
-			got, err = cr.ListContainers("")
+			got, err = cr.ListContainers(ListOptions{})
 			if err != nil {
 				t.Fatalf("ListContainers: %v", err)
 			}
@@ -481,7 +1007,7 @@ func TestContainerFunctions(t *testing.T) {
 			if err := cr.KillContainers(got); err != nil {
 				t.Errorf("KillContainers: %v", err)
 			}
-			got, err = cr.ListContainers("")
+			got, err = cr.ListContainers(ListOptions{})
 			if err != nil {
 				t.Fatalf("ListContainers: %v", err)
 			}
@@ -491,3 +1017,100 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestPauseFunctions(t *testing.T) {
+	var tests = []struct {
+		runtime string
+	}{
+		{"docker"},
+		{"crio"},
+		{"containerd"},
+		{"podman"},
+	}
+
+	sortSlices := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			prefix := ""
+			if tc.runtime == "docker" {
+				prefix = "k8s_"
+			}
+			runner.containers = map[string]string{
+				"abc0": prefix + "apiserver",
+				"fgh1": prefix + "coredns",
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+
+			running, err := cr.ListContainers(ListOptions{State: Running})
+			if err != nil {
+				t.Fatalf("ListContainers: %v", err)
+			}
+			if diff := cmp.Diff([]string{"abc0", "fgh1"}, running, sortSlices); diff != "" {
+				t.Errorf("ListContainers(running) unexpected results, diff (-want +got): %s", diff)
+			}
+
+			if err := cr.PauseContainers(running); err != nil {
+				t.Fatalf("PauseContainers: %v", err)
+			}
+			paused, err := cr.ListContainers(ListOptions{State: Paused, Name: "apiserver"})
+			if err != nil {
+				t.Fatalf("ListContainers: %v", err)
+			}
+			if diff := cmp.Diff([]string{"abc0"}, paused); diff != "" {
+				t.Errorf("ListContainers(paused apiserver) unexpected results, diff (-want +got): %s", diff)
+			}
+			// paused containers are still running
+			running, err = cr.ListContainers(ListOptions{State: Running})
+			if err != nil {
+				t.Fatalf("ListContainers: %v", err)
+			}
+			if diff := cmp.Diff([]string{"abc0", "fgh1"}, running, sortSlices); diff != "" {
+				t.Errorf("ListContainers(running) of paused containers unexpected results, diff (-want +got): %s", diff)
+			}
+
+			paused, err = cr.ListContainers(ListOptions{State: Paused})
+			if err != nil {
+				t.Fatalf("ListContainers: %v", err)
+			}
+			if err := cr.UnpauseContainers(paused); err != nil {
+				t.Fatalf("UnpauseContainers: %v", err)
+			}
+			paused, err = cr.ListContainers(ListOptions{State: Paused})
+			if err != nil {
+				t.Fatalf("ListContainers: %v", err)
+			}
+			if len(paused) > 0 {
+				t.Errorf("ListContainers(paused) = %v, want 0 items", paused)
+			}
+		})
+	}
+}
+
+// TestListContainersWithoutRunc checks that only listing paused containers requires runc, which the none driver
+// may not have
+func TestListContainersWithoutRunc(t *testing.T) {
+	for _, runtime := range []string{"crio", "containerd", "podman"} {
+		t.Run(runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.containers = map[string]string{"abc0": "apiserver"}
+			cr, err := New(Config{Type: runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", runtime, err)
+			}
+			for _, state := range []ContainerState{All, Running} {
+				if _, err := cr.ListContainers(ListOptions{State: state}); err != nil {
+					t.Fatalf("ListContainers(%s): %v", state, err)
+				}
+			}
+			for _, cmd := range runner.cmds {
+				if strings.Contains(cmd, "runc") {
+					t.Errorf("ListContainers() ran %q, want no runc commands", cmd)
+				}
+			}
+		})
+	}
+}
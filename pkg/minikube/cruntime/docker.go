@@ -17,11 +17,17 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os/exec"
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -30,8 +36,10 @@ const KubernetesContainerPrefix = "k8s_"
 
 // Docker contains Docker runtime state
 type Docker struct {
-	Socket string
-	Runner CommandRunner
+	Socket      string
+	Runner      CommandRunner
+	Mirror      string
+	Credentials map[string]config.RegistryCredential
 }
 
 // Name is a human readable name for Docker
@@ -84,9 +92,91 @@ func (r *Docker) Enable(disOthers bool) error {
 			glog.Warningf("disableOthers: %v", err)
 		}
 	}
+	if r.Credentials != nil {
+		if err := r.UpdateCredentials(r.Credentials); err != nil {
+			return err
+		}
+	}
+	if r.Mirror != "" {
+		changed, err := configureDockerMirror(r.Runner, r.Mirror)
+		if err != nil {
+			return errors.Wrap(err, "registry mirror")
+		}
+		if changed {
+			return r.Runner.Run("sudo systemctl restart docker")
+		}
+	}
 	return r.Runner.Run("sudo systemctl start docker")
 }
 
+// configureDockerMirror adds a registry mirror to the dockerd configuration, keeping its other settings,
+// and returns whether the configuration was changed
+func configureDockerMirror(cr CommandRunner, mirror string) (bool, error) {
+	cPath := "/etc/docker/daemon.json"
+	// The file may not exist yet, in which case the output is ignored
+	current, err := cr.CombinedOutput(fmt.Sprintf("sudo cat %s", cPath))
+	if err != nil {
+		current = ""
+	}
+	data, err := mergeDockerMirror(current, mirror)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing %s", cPath)
+	}
+	if strings.TrimSpace(current) == string(data) {
+		return false, nil
+	}
+	glog.Infof("Configuring docker to use the registry mirror at %s", mirror)
+	if err := cr.Copy(assets.NewMemoryAssetTarget(data, cPath, "0644")); err != nil {
+		return false, errors.Wrapf(err, "copy %s", cPath)
+	}
+	return true, nil
+}
+
+// mergeDockerMirror returns a dockerd configuration which uses mirror first, replacing any mirror previously
+// configured for the same host, and keeping the other settings of the current configuration
+func mergeDockerMirror(current string, mirror string) ([]byte, error) {
+	cfg := map[string]interface{}{}
+	if strings.TrimSpace(current) != "" {
+		if err := json.Unmarshal([]byte(current), &cfg); err != nil {
+			return nil, err
+		}
+	}
+	mirrors := []interface{}{mirror}
+	existing, _ := cfg["registry-mirrors"].([]interface{})
+	for _, m := range existing {
+		if s, ok := m.(string); ok && sameHostname(s, mirror) {
+			continue
+		}
+		mirrors = append(mirrors, m)
+	}
+	cfg["registry-mirrors"] = mirrors
+	return json.Marshal(cfg)
+}
+
+// sameHostname returns whether two URLs refer to the same host, regardless of their ports
+func sameHostname(a string, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Hostname() == ub.Hostname()
+}
+
+// UpdateCredentials replaces the credentials of private registries, which are read by the kubelet and the docker client on every pull
+func (r *Docker) UpdateCredentials(creds map[string]config.RegistryCredential) error {
+	if err := writeKubeletCredentials(r.Runner, creds); err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	if err := writeDockerCredentials(r.Runner, creds); err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	return nil
+}
+
 // Disable idempotently disables Docker on a host
 func (r *Docker) Disable() error {
 	return r.Runner.Run("sudo systemctl stop docker docker.socket")
@@ -98,6 +188,35 @@ func (r *Docker) LoadImage(path string) error {
 	return r.Runner.Run(fmt.Sprintf("docker load -i %s", path))
 }
 
+// ListImages returns the names of the images known to the runtime
+func (r *Docker) ListImages() ([]string, error) {
+	content, err := r.Runner.CombinedOutput(`docker images --format="{{.Repository}}:{{.Tag}}"`)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "<none>") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image from the runtime
+func (r *Docker) RemoveImage(name string) error {
+	glog.Infof("Removing image: %s", name)
+	return r.Runner.Run(fmt.Sprintf("docker rmi %s", command.ShellQuote(name)))
+}
+
+// BuildImage builds an image from a build context directory, and tags it
+func (r *Docker) BuildImage(dir string, tag string) error {
+	glog.Infof("Building image %s from %s", tag, dir)
+	return r.Runner.Run(fmt.Sprintf("docker build -t %s %s", command.ShellQuote(tag), command.ShellQuote(dir)))
+}
+
 // KubeletOptions returns kubelet options for a runtime.
 func (r *Docker) KubeletOptions() map[string]string {
 	return map[string]string{
@@ -106,9 +225,18 @@ func (r *Docker) KubeletOptions() map[string]string {
 }
 
 // ListContainers returns a list of containers
-func (r *Docker) ListContainers(filter string) ([]string, error) {
-	filter = KubernetesContainerPrefix + filter
-	content, err := r.Runner.CombinedOutput(fmt.Sprintf(`docker ps -a --filter="name=%s" --format="{{.ID}}"`, filter))
+func (r *Docker) ListContainers(o ListOptions) ([]string, error) {
+	var args strings.Builder
+	args.WriteString(fmt.Sprintf(`docker ps -a --filter="name=%s" `, KubernetesContainerPrefix+o.Name))
+	switch o.State {
+	case Running:
+		// as with crictl, paused containers are still running
+		args.WriteString(`--filter="status=running" --filter="status=paused" `)
+	case Paused:
+		args.WriteString(`--filter="status=paused" `)
+	}
+	args.WriteString(`--format="{{.ID}}"`)
+	content, err := r.Runner.CombinedOutput(args.String())
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +267,24 @@ func (r *Docker) StopContainers(ids []string) error {
 	return r.Runner.Run(fmt.Sprintf("docker stop %s", strings.Join(ids, " ")))
 }
 
+// PauseContainers pauses a running container based on ID
+func (r *Docker) PauseContainers(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Pausing containers: %s", ids)
+	return r.Runner.Run(fmt.Sprintf("docker pause %s", strings.Join(ids, " ")))
+}
+
+// UnpauseContainers unpauses a paused container based on ID
+func (r *Docker) UnpauseContainers(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Unpausing containers: %s", ids)
+	return r.Runner.Run(fmt.Sprintf("docker unpause %s", strings.Join(ids, " ")))
+}
+
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
 func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 	var cmd strings.Builder
@@ -154,6 +300,11 @@ func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 	return cmd.String()
 }
 
+// ContainerExecCmd returns the command to run a shell command inside a container based on ID
+func (r *Docker) ContainerExecCmd(id string, cmd string) string {
+	return fmt.Sprintf("docker exec %s /bin/sh -c %q", id, cmd)
+}
+
 // SystemLogCmd returns the command to retrieve system logs
 func (r *Docker) SystemLogCmd(len int) string {
 	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// podmanSocket is the systemd socket unit which starts the podman varlink service on demand
+const podmanSocket = "io.podman.socket"
+
+// podmanISOVersion is the first minikube ISO which includes the podman varlink units
+const podmanISOVersion = "v1.4.1"
+
+// Podman contains Podman runtime state.
+// As podman has no CRI implementation, the containers of the cluster are run by CRI-O, which shares its image storage.
+type Podman struct {
+	Socket      string
+	Runner      CommandRunner
+	Mirror      string
+	Credentials map[string]config.RegistryCredential
+}
+
+// Name is a human readable name for Podman
+func (r *Podman) Name() string {
+	return "Podman"
+}
+
+// Style is the console style for Podman
+func (r *Podman) Style() out.StyleEnum {
+	return out.Podman
+}
+
+// Version retrieves the current version of this runtime
+func (r *Podman) Version() (string, error) {
+	ver, err := r.Runner.CombinedOutput("podman --version")
+	if err != nil {
+		return "", err
+	}
+
+	// podman version 1.4.4
+	return strings.TrimSpace(strings.Replace(ver, "podman version ", "", 1)), nil
+}
+
+// SocketPath returns the path to the CRI socket used by the kubelet, which is the one of CRI-O
+func (r *Podman) SocketPath() string {
+	if r.Socket != "" {
+		return r.Socket
+	}
+	return "/var/run/crio/crio.sock"
+}
+
+// DefaultCNI returns whether to use CNI networking by default
+func (r *Podman) DefaultCNI() bool {
+	return true
+}
+
+// Available returns an error if it is not possible to use this runtime on a host
+func (r *Podman) Available() error {
+	return r.Runner.Run("command -v podman")
+}
+
+// Active returns if Podman is active on the host
+func (r *Podman) Active() bool {
+	err := r.Runner.Run(fmt.Sprintf("systemctl is-active --quiet service %s", podmanSocket))
+	return err == nil
+}
+
+// Enable idempotently enables Podman on a host, along with CRI-O for the kubelet
+func (r *Podman) Enable(disOthers bool) error {
+	// Older ISOs have podman, but not its varlink units
+	if err := r.Runner.Run(fmt.Sprintf("test -f /usr/lib/systemd/system/%s", podmanSocket)); err != nil {
+		return fmt.Errorf("%s is not installed: the podman runtime requires minikube ISO %s or later, use 'minikube delete' to recreate the VM with a newer ISO", podmanSocket, podmanISOVersion)
+	}
+	if disOthers {
+		if err := disableOthers(r, r.Runner); err != nil {
+			glog.Warningf("disableOthers: %v", err)
+		}
+	}
+	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
+		return err
+	}
+	if err := enableIPForwarding(r.Runner); err != nil {
+		return err
+	}
+	// podman reads the same registries.conf as CRI-O
+	if r.Mirror != "" {
+		if err := configureCRIOMirror(r.Runner, r.Mirror); err != nil {
+			return errors.Wrap(err, "registry mirror")
+		}
+	}
+	if r.Credentials != nil {
+		if _, err := configureCRIOCredentials(r.Runner, r.Credentials); err != nil {
+			return errors.Wrap(err, "registry credentials")
+		}
+	}
+	if err := r.Runner.Run("sudo systemctl restart crio"); err != nil {
+		return err
+	}
+	return r.Runner.Run(fmt.Sprintf("sudo systemctl start %s", podmanSocket))
+}
+
+// UpdateCredentials replaces the credentials of private registries, restarting CRI-O if its configuration changed
+func (r *Podman) UpdateCredentials(creds map[string]config.RegistryCredential) error {
+	changed, err := configureCRIOCredentials(r.Runner, creds)
+	if err != nil {
+		return errors.Wrap(err, "registry credentials")
+	}
+	if !changed {
+		return nil
+	}
+	return r.Runner.Run("sudo systemctl restart crio")
+}
+
+// Disable idempotently disables Podman on a host
+func (r *Podman) Disable() error {
+	return r.Runner.Run(fmt.Sprintf("sudo systemctl stop %s", podmanSocket))
+}
+
+// LoadImage loads an image into this runtime
+func (r *Podman) LoadImage(path string) error {
+	glog.Infof("Loading image: %s", path)
+	return r.Runner.Run(fmt.Sprintf("sudo podman load -i %s", path))
+}
+
+// ListImages returns the names of the images known to the runtime
+func (r *Podman) ListImages() ([]string, error) {
+	content, err := r.Runner.CombinedOutput(`sudo podman images --format="{{.Repository}}:{{.Tag}}"`)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "<none>") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image from the runtime
+func (r *Podman) RemoveImage(name string) error {
+	glog.Infof("Removing image: %s", name)
+	return r.Runner.Run(fmt.Sprintf("sudo podman rmi %s", command.ShellQuote(name)))
+}
+
+// BuildImage builds an image from a build context directory, and tags it
+func (r *Podman) BuildImage(dir string, tag string) error {
+	glog.Infof("Building image %s from %s", tag, dir)
+	return r.Runner.Run(fmt.Sprintf("sudo podman build -t %s %s", command.ShellQuote(tag), command.ShellQuote(dir)))
+}
+
+// KubeletOptions returns kubelet options for a runtime.
+func (r *Podman) KubeletOptions() map[string]string {
+	return map[string]string{
+		"container-runtime":          "remote",
+		"container-runtime-endpoint": r.SocketPath(),
+		"image-service-endpoint":     r.SocketPath(),
+		"runtime-request-timeout":    "15m",
+	}
+}
+
+// ListContainers returns a list of managed by this container runtime
+func (r *Podman) ListContainers(o ListOptions) ([]string, error) {
+	return listCRIContainers(r.Runner, crioRuncRoot, o)
+}
+
+// KillContainers removes containers based on ID
+func (r *Podman) KillContainers(ids []string) error {
+	return killCRIContainers(r.Runner, ids)
+}
+
+// StopContainers stops containers based on ID
+func (r *Podman) StopContainers(ids []string) error {
+	return stopCRIContainers(r.Runner, ids)
+}
+
+// PauseContainers pauses a running container based on ID
+func (r *Podman) PauseContainers(ids []string) error {
+	return pauseCRIContainers(r.Runner, crioRuncRoot, ids)
+}
+
+// UnpauseContainers unpauses a paused container based on ID
+func (r *Podman) UnpauseContainers(ids []string) error {
+	return unpauseCRIContainers(r.Runner, crioRuncRoot, ids)
+}
+
+// ContainerLogCmd returns the command to retrieve the log for a container based on ID
+func (r *Podman) ContainerLogCmd(id string, len int, follow bool) string {
+	return criContainerLogCmd(id, len, follow)
+}
+
+// ContainerExecCmd returns the command to run a shell command inside a container based on ID
+func (r *Podman) ContainerExecCmd(id string, cmd string) string {
+	return criContainerExecCmd(id, cmd)
+}
+
+// SystemLogCmd returns the command to retrieve system logs
+func (r *Podman) SystemLogCmd(len int) string {
+	return fmt.Sprintf("sudo journalctl -u crio -u io.podman -n %d", len)
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+	"k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/proxy"
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+const (
+	// kvmDevice exists if the kvm kernel module is loaded
+	kvmDevice = "/dev/kvm"
+	// libvirtSocket is the socket of the system libvirt daemon
+	libvirtSocket = "/var/run/libvirt/libvirt-sock"
+	// registryTimeout is how long to wait for the image registry to respond
+	registryTimeout = 10 * time.Second
+	// proxyDoc is the URL to proxy documentation
+	proxyDoc = "https://minikube.sigs.k8s.io/docs/reference/networking/proxy/"
+)
+
+// runtimeCommands are the commands which must be installed on the host to use a runtime with the none driver
+var runtimeCommands = map[string]string{
+	"":           "docker",
+	"docker":     "docker",
+	"crio":       "crio",
+	"cri-o":      "crio",
+	"containerd": "containerd",
+	"podman":     "podman",
+}
+
+func init() {
+	Register(Check{Name: "driver", Run: checkDriver})
+	Register(Check{Name: "kvm", Drivers: []string{constants.DriverKvm2}, Run: checkKVM})
+	Register(Check{Name: "host-only-cidr", Drivers: []string{constants.DriverVirtualbox}, Run: checkHostOnlyCIDR})
+	Register(Check{Name: "resources", Run: checkResources})
+	Register(Check{Name: "disk", Run: checkDisk})
+	Register(Check{Name: "runtime", Run: checkRuntime})
+	Register(Check{Name: "proxy", Run: checkProxy})
+	Register(Check{Name: "registry", Run: checkRegistry})
+	Register(Check{Name: "cache", Run: checkCache})
+}
+
+// checkDriver checks that the driver is supported, and that the software it requires is installed
+func checkDriver(cfg Config) Result {
+	if _, err := registry.Driver(cfg.Driver); err != nil {
+		return found(Fail, "DRIVER_NOT_FOUND", fmt.Sprintf("The %q driver is not supported on %s", cfg.Driver, runtime.GOOS), "Select an alternative --vm-driver")
+	}
+	switch cfg.Driver {
+	case constants.DriverVirtualbox:
+		if !vboxManageInstalled() {
+			return found(Fail, "VBOX_NOT_FOUND", "VBoxManage was not found", "")
+		}
+	case constants.DriverKvm2, constants.DriverHyperkit:
+		v, err := version.GetSemverVersion()
+		if err != nil {
+			return found(Warn, "", fmt.Sprintf("Unable to parse the minikube version: %v", err), "")
+		}
+		if err := drivers.ValidateDriver(cfg.Driver, v); err != nil {
+			return found(Warn, "DRIVER_OUTDATED", fmt.Sprintf("The %s driver plugin is missing or outdated: %v", cfg.Driver, err), "minikube start will download the latest driver plugin")
+		}
+	}
+	return passed(fmt.Sprintf("The %s driver is supported", cfg.Driver))
+}
+
+// vboxManageInstalled returns whether the VirtualBox command line tool can be found, as the virtualbox driver does
+func vboxManageInstalled() bool {
+	if _, err := exec.LookPath("VBoxManage"); err == nil {
+		return true
+	}
+	for _, env := range []string{"VBOX_INSTALL_PATH", "VBOX_MSI_INSTALL_PATH"} {
+		dir := os.Getenv(env)
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "VBoxManage.exe")); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKVM checks that KVM is available, and that libvirt can be used by the current user
+func checkKVM(cfg Config) Result {
+	if _, err := os.Stat(kvmDevice); err != nil {
+		return found(Fail, "KVM_UNAVAILABLE", fmt.Sprintf("%s does not exist: the kvm kernel module is not loaded", kvmDevice),
+			"Enable virtualization in your BIOS, and load the kvm_intel or kvm_amd kernel module. If you are running minikube within a VM, consider using --vm-driver=none")
+	}
+	c, err := net.Dial("unix", libvirtSocket)
+	if err != nil {
+		advice := "Install libvirt, and start the libvirtd service"
+		if strings.Contains(err.Error(), "permission denied") {
+			advice = "Add your user to the libvirt group: 'sudo usermod -a -G libvirt $(whoami)', and log in again"
+		}
+		return found(Fail, "KVM_CONNECTION_ERROR", fmt.Sprintf("Unable to connect to libvirt: %v", err), advice)
+	}
+	c.Close()
+	return passed("KVM is available, and libvirt is accessible")
+}
+
+// checkHostOnlyCIDR checks that the network of the VirtualBox host-only adapter is not used by another interface
+func checkHostOnlyCIDR(cfg Config) Result {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return found(Warn, "", fmt.Sprintf("Unable to list network interfaces: %v", err), "")
+	}
+	addrs := map[string][]net.Addr{}
+	for _, i := range ifaces {
+		a, err := i.Addrs()
+		if err != nil {
+			continue
+		}
+		addrs[i.Name] = a
+	}
+	name, err := cidrConflict(cfg.HostOnlyCIDR, addrs)
+	if err != nil {
+		return found(Fail, "HOST_CIDR_INVALID", err.Error(), "Specify a valid --host-only-cidr value, such as 192.168.99.1/24")
+	}
+	if name != "" {
+		return found(Fail, "HOST_CIDR_CONFLICT", fmt.Sprintf("%s overlaps with the network of the %s interface", cfg.HostOnlyCIDR, name), "")
+	}
+	return passed(fmt.Sprintf("%s is not used by another network interface", cfg.HostOnlyCIDR))
+}
+
+// cidrConflict returns the name of an interface with an address in the network of cidr,
+// other than a VirtualBox host-only adapter, which minikube may reuse
+func cidrConflict(cidr string, addrs map[string][]net.Addr) (string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for name := range addrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasPrefix(name, "vboxnet") || strings.Contains(name, "VirtualBox") {
+			continue
+		}
+		for _, a := range addrs[name] {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if network.Contains(ipnet.IP) || ipnet.Contains(network.IP) {
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkResources checks that the host has enough memory and CPUs for the cluster
+func checkResources(cfg Config) Result {
+	info, err := cluster.GetHostInfo()
+	if err != nil {
+		return found(Warn, "", fmt.Sprintf("Unable to get the resources of the host: %v", err), "")
+	}
+	return resourcesResult(cfg, info)
+}
+
+// resourcesResult compares the resources of the host with those required
+func resourcesResult(cfg Config, info *cluster.HostInfo) Result {
+	if cfg.Driver == constants.DriverNone {
+		if info.CPUs < constants.MinimumCPUS {
+			return found(Fail, "INSUFFICIENT_CPUS", fmt.Sprintf("The host has %d CPUs, but at least %d are required", info.CPUs, constants.MinimumCPUS), "Run minikube on a host with more CPUs")
+		}
+		if min := util.CalculateSizeInMB(constants.MinimumMemorySize); info.Memory < min {
+			return found(Fail, "INSUFFICIENT_MEMORY", fmt.Sprintf("The host has %dMB of memory, but at least %dMB is required", info.Memory, min), "Run minikube on a host with more memory")
+		}
+		return passed(fmt.Sprintf("The host has %d CPUs and %dMB of memory", info.CPUs, info.Memory))
+	}
+
+	if cfg.CPUs > info.CPUs {
+		return found(Fail, "INSUFFICIENT_CPUS", fmt.Sprintf("%d CPUs were requested, but the host has %d", cfg.CPUs, info.CPUs), "Pass a smaller --cpus value")
+	}
+	if cfg.Memory > info.Memory {
+		return found(Fail, "INSUFFICIENT_MEMORY", fmt.Sprintf("%dMB of memory was requested, but the host has %dMB", cfg.Memory, info.Memory), "Pass a smaller --memory value")
+	}
+	if cfg.Memory > info.Memory*3/4 {
+		return found(Warn, "INSUFFICIENT_MEMORY", fmt.Sprintf("%dMB of memory was requested, which is most of the %dMB of the host", cfg.Memory, info.Memory), "Pass a smaller --memory value, to leave enough memory for the host")
+	}
+	return passed(fmt.Sprintf("The host has %d CPUs and %dMB of memory, of which %d CPUs and %dMB are requested", info.CPUs, info.Memory, cfg.CPUs, cfg.Memory))
+}
+
+// checkDisk checks that there is enough free disk space for the minikube home directory
+func checkDisk(cfg Config) Result {
+	dir := existingParent(localpath.MiniPath())
+	u, err := disk.Usage(dir)
+	if err != nil {
+		return found(Warn, "", fmt.Sprintf("Unable to get the free disk space of %s: %v", dir, err), "")
+	}
+	return diskResult(cfg, dir, int(u.Free/1024/1024))
+}
+
+// existingParent returns the closest directory to dir which exists, including dir itself
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// diskResult compares the free disk space, in MB, with that required
+func diskResult(cfg Config, dir string, free int) Result {
+	advice := "Free up disk space, or set MINIKUBE_HOME to a directory on a larger disk"
+	if min := util.CalculateSizeInMB(constants.MinimumDiskSize); free < min {
+		return found(Fail, "INSUFFICIENT_DISK", fmt.Sprintf("%dMB of disk space is free in %s, but at least %dMB is required", free, dir, min), advice)
+	}
+	// VM disks are allocated as they are used, so only warn when they may grow larger than the free space
+	if cfg.Driver != constants.DriverNone && free < cfg.DiskSize {
+		return found(Warn, "INSUFFICIENT_DISK", fmt.Sprintf("The VM disk may grow to %dMB, but only %dMB of disk space is free in %s", cfg.DiskSize, free, dir), advice+", or pass a smaller --disk-size value")
+	}
+	return passed(fmt.Sprintf("%dMB of disk space is free in %s", free, dir))
+}
+
+// checkRuntime checks that the container runtime is supported, and installed when using the none driver
+func checkRuntime(cfg Config) Result {
+	if _, err := cruntime.New(cruntime.Config{Type: cfg.ContainerRuntime}); err != nil {
+		return found(Fail, "RUNTIME_UNKNOWN", err.Error(), "Select one of the supported runtimes with --container-runtime: docker, crio, containerd or podman")
+	}
+	if cfg.Driver == constants.DriverNone {
+		command := runtimeCommands[cfg.ContainerRuntime]
+		if _, err := exec.LookPath(command); err != nil {
+			return found(Fail, "RUNTIME_NOT_FOUND", fmt.Sprintf("%s was not found, which the none driver requires to use the %s runtime", command, cfg.ContainerRuntime),
+				fmt.Sprintf("Install %s, or select an alternative --container-runtime", command))
+		}
+	}
+	return passed(fmt.Sprintf("The %s runtime is supported", cfg.ContainerRuntime))
+}
+
+// checkProxy checks that the proxy settings are valid, and that the cluster is excluded from the proxy
+func checkProxy(cfg Config) Result {
+	var env, value string
+	for _, e := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		if v := os.Getenv(e); v != "" {
+			env, value = e, v
+			break
+		}
+	}
+	if value == "" {
+		return passed("No proxy is configured")
+	}
+
+	// As with net/http, a proxy without a scheme is assumed to be an HTTP proxy
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	// The value is not displayed, as it may contain credentials
+	if u, err := url.Parse(value); err != nil || u.Host == "" {
+		return found(Fail, "INVALID_PROXY_HOSTNAME", fmt.Sprintf("%s is not a valid proxy URL", env), "")
+	}
+	if cfg.Driver != constants.DriverNone && cfg.NodeIP != "" && !proxy.IsIPExcluded(cfg.NodeIP) {
+		r := found(Warn, "PROXY_NOT_EXCLUDED", fmt.Sprintf("%s is set, but NO_PROXY does not include the minikube IP %s", env, cfg.NodeIP),
+			fmt.Sprintf("Add the minikube IP to NO_PROXY, for example: export NO_PROXY=$NO_PROXY,%s", cfg.NodeIP))
+		r.URL = proxyDoc
+		return r
+	}
+	return passed(fmt.Sprintf("Using the proxy in %s", env))
+}
+
+// registryHost returns the host of the registry which Kubernetes images are pulled from
+func registryHost(imageRepository string) string {
+	if imageRepository == "" {
+		return "k8s.gcr.io"
+	}
+	return strings.SplitN(imageRepository, "/", 2)[0]
+}
+
+// checkRegistry checks that the registry which Kubernetes images are pulled from can be reached, through the proxy if any
+func checkRegistry(cfg Config) Result {
+	host := registryHost(cfg.ImageRepository)
+	client := &http.Client{Timeout: registryTimeout}
+	resp, err := client.Get(fmt.Sprintf("https://%s/v2/", host))
+	if err == nil {
+		resp.Body.Close()
+		return passed(fmt.Sprintf("%s is reachable", host))
+	}
+
+	status := Fail
+	// Images can still be loaded from the cache
+	if missing, _, cerr := missingCacheFiles(cfg); cerr == nil && len(missing) == 0 {
+		status = Warn
+	}
+	if host == "k8s.gcr.io" {
+		return found(status, "GCR_UNAVAILABLE", fmt.Sprintf("Unable to reach %s: %v", host, err), "")
+	}
+	return found(status, "REGISTRY_UNAVAILABLE", fmt.Sprintf("Unable to reach %s: %v", host, err), "Check that the registry is reachable, or select an alternative --image-repository")
+}
+
+// missingCacheFiles returns the files needed to start a cluster which are not cached, and the number of files needed
+func missingCacheFiles(cfg Config) ([]string, int, error) {
+	files, err := machine.BundleFiles(cfg.KubernetesVersion, cfg.ImageRepository, cfg.Bootstrapper, cfg.ISOURL)
+	if err != nil {
+		return nil, 0, err
+	}
+	var missing []string
+	total := 0
+	for _, f := range files {
+		// The none driver does not use the ISO
+		if cfg.Driver == constants.DriverNone && strings.HasPrefix(f, "cache/iso/") {
+			continue
+		}
+		total++
+		if _, err := os.Stat(filepath.Join(localpath.MiniPath(), filepath.FromSlash(f))); err != nil {
+			missing = append(missing, f)
+		}
+	}
+	return missing, total, nil
+}
+
+// checkCache checks whether the files needed to start a cluster are already cached
+func checkCache(cfg Config) Result {
+	missing, total, err := missingCacheFiles(cfg)
+	if err != nil {
+		return found(Warn, "", fmt.Sprintf("Unable to list the files needed to start: %v", err), "")
+	}
+	if len(missing) > 0 {
+		return found(Warn, "CACHE_INCOMPLETE", fmt.Sprintf("%d of %d files needed to start are not cached, and will be downloaded", len(missing), total),
+			"To start without network access, import a cache bundle with 'minikube cache import'")
+	}
+	return passed(fmt.Sprintf("All %d files needed to start are cached", total))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor runs preflight checks, which find problems with the host before they cause minikube start to fail.
+package doctor
+
+import (
+	"k8s.io/minikube/pkg/minikube/problem"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Pass means that no problem was found
+	Pass Status = "pass"
+	// Warn means that minikube may not work as expected, or that start will have to work around a problem
+	Warn Status = "warn"
+	// Fail means that minikube start is expected to fail
+	Fail Status = "fail"
+)
+
+// Config is the configuration the checks are run for
+type Config struct {
+	Driver            string
+	ContainerRuntime  string
+	KubernetesVersion string
+	ImageRepository   string
+	Bootstrapper      string
+	ISOURL            string
+	HostOnlyCIDR      string
+	// NodeIP is the IP address of an existing cluster, if known
+	NodeIP string
+	// Memory and DiskSize are in MB
+	Memory   int
+	CPUs     int
+	DiskSize int
+}
+
+// Result is the result of a check
+type Result struct {
+	Check   string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// ID identifies the problem found, as with problem.Problem
+	ID     string `json:"id,omitempty"`
+	Advice string `json:"advice,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Check is a preflight check
+type Check struct {
+	Name string
+	// Drivers are the drivers the check applies to, or nil for all drivers
+	Drivers []string
+	// Run runs the check. The name of the check is filled in by doctor.Run.
+	Run func(Config) Result
+}
+
+// checks are the registered checks, in the order they are run
+var checks []Check
+
+// Register adds a check to those run by Run
+func Register(c Check) {
+	checks = append(checks, c)
+}
+
+// appliesTo returns whether a check applies to a driver
+func (c Check) appliesTo(driver string) bool {
+	if len(c.Drivers) == 0 {
+		return true
+	}
+	for _, d := range c.Drivers {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the registered checks which apply to the driver, in the order they were registered
+func Run(cfg Config) []Result {
+	var results []Result
+	for _, c := range checks {
+		if !c.appliesTo(cfg.Driver) {
+			continue
+		}
+		r := c.Run(cfg)
+		r.Check = c.Name
+		results = append(results, r)
+	}
+	return results
+}
+
+// Worst returns the most severe status of the results
+func Worst(results []Result) Status {
+	worst := Pass
+	for _, r := range results {
+		switch {
+		case r.Status == Fail:
+			return Fail
+		case r.Status == Warn:
+			worst = Warn
+		}
+	}
+	return worst
+}
+
+// passed returns a passing result
+func passed(message string) Result {
+	return Result{Status: Pass, Message: message}
+}
+
+// found returns a result for a problem. If the ID is that of a known problem, its advice is used.
+func found(status Status, id string, message string, advice string) Result {
+	r := Result{Status: status, ID: id, Message: message, Advice: advice}
+	if p := problem.Lookup(id); p != nil {
+		r.URL = p.URL
+		if r.Advice == "" {
+			r.Advice = p.Advice
+		}
+	}
+	return r
+}
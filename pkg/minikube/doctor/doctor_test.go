@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestRun(t *testing.T) {
+	defer func(old []Check) { checks = old }(checks)
+	checks = nil
+	Register(Check{Name: "all", Run: func(Config) Result { return passed("ok") }})
+	Register(Check{Name: "kvm", Drivers: []string{constants.DriverKvm2}, Run: func(Config) Result {
+		return found(Fail, "KVM_UNAVAILABLE", "no kvm", "")
+	}})
+
+	results := Run(Config{Driver: constants.DriverVirtualbox})
+	if len(results) != 1 || results[0].Check != "all" || Worst(results) != Pass {
+		t.Errorf("Run(virtualbox) = %+v, want only a passing 'all' check", results)
+	}
+
+	results = Run(Config{Driver: constants.DriverKvm2})
+	if len(results) != 2 || Worst(results) != Fail {
+		t.Fatalf("Run(kvm2) = %+v, want 2 results with a failure", results)
+	}
+	kvm := results[1]
+	if kvm.Check != "kvm" || kvm.ID != "KVM_UNAVAILABLE" || kvm.Advice == "" || kvm.URL == "" {
+		t.Errorf("kvm result = %+v, want the advice of the known problem", kvm)
+	}
+}
+
+func TestCIDRConflict(t *testing.T) {
+	addr := func(s string) net.Addr {
+		ip, ipnet, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		ipnet.IP = ip
+		return ipnet
+	}
+	addrs := map[string][]net.Addr{
+		"eth0":     {addr("10.0.0.5/24")},
+		"vboxnet0": {addr("192.168.99.1/24")},
+		"wlan0":    {addr("192.168.5.20/24")},
+	}
+	var tests = []struct {
+		cidr string
+		want string
+	}{
+		{"192.168.99.1/24", ""},
+		{"10.0.0.1/24", "eth0"},
+		{"192.168.5.1/24", "wlan0"},
+		{"172.16.0.1/24", ""},
+	}
+	for _, tc := range tests {
+		got, err := cidrConflict(tc.cidr, addrs)
+		if err != nil {
+			t.Errorf("cidrConflict(%s): %v", tc.cidr, err)
+		}
+		if got != tc.want {
+			t.Errorf("cidrConflict(%s) = %q, want %q", tc.cidr, got, tc.want)
+		}
+	}
+	if _, err := cidrConflict("not-a-cidr", addrs); err == nil {
+		t.Errorf("cidrConflict(not-a-cidr) succeeded, want an error")
+	}
+}
+
+func TestResourcesResult(t *testing.T) {
+	host := &cluster.HostInfo{CPUs: 4, Memory: 8000}
+	var tests = []struct {
+		description string
+		cfg         Config
+		host        *cluster.HostInfo
+		want        Status
+	}{
+		{"fits", Config{Driver: constants.DriverKvm2, CPUs: 2, Memory: 2000}, host, Pass},
+		{"too many cpus", Config{Driver: constants.DriverKvm2, CPUs: 8, Memory: 2000}, host, Fail},
+		{"too much memory", Config{Driver: constants.DriverKvm2, CPUs: 2, Memory: 10000}, host, Fail},
+		{"most of the memory", Config{Driver: constants.DriverKvm2, CPUs: 2, Memory: 7000}, host, Warn},
+		{"none ignores requests", Config{Driver: constants.DriverNone, CPUs: 8, Memory: 10000}, host, Pass},
+		{"none with one cpu", Config{Driver: constants.DriverNone}, &cluster.HostInfo{CPUs: 1, Memory: 8000}, Fail},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := resourcesResult(tc.cfg, tc.host); got.Status != tc.want {
+				t.Errorf("resourcesResult = %+v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiskResult(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         Config
+		free        int
+		want        Status
+	}{
+		{"plenty", Config{Driver: constants.DriverKvm2, DiskSize: 20000}, 50000, Pass},
+		{"vm disk may fill the host", Config{Driver: constants.DriverKvm2, DiskSize: 20000}, 10000, Warn},
+		{"none has no vm disk", Config{Driver: constants.DriverNone, DiskSize: 20000}, 10000, Pass},
+		{"full", Config{Driver: constants.DriverNone}, 100, Fail},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := diskResult(tc.cfg, "/home", tc.free); got.Status != tc.want {
+				t.Errorf("diskResult = %+v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckProxy(t *testing.T) {
+	for _, e := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY"} {
+		defer func(e string, v string) { os.Setenv(e, v) }(e, os.Getenv(e))
+		os.Unsetenv(e)
+	}
+	cfg := Config{Driver: constants.DriverKvm2, NodeIP: "192.168.39.10"}
+	if got := checkProxy(cfg); got.Status != Pass {
+		t.Errorf("checkProxy without a proxy = %+v, want pass", got)
+	}
+
+	os.Setenv("HTTPS_PROXY", "proxy.example.com:3128")
+	if got := checkProxy(cfg); got.Status != Warn || got.ID != "PROXY_NOT_EXCLUDED" {
+		t.Errorf("checkProxy without NO_PROXY = %+v, want PROXY_NOT_EXCLUDED", got)
+	}
+	os.Setenv("NO_PROXY", "localhost,192.168.39.0/24")
+	if got := checkProxy(cfg); got.Status != Pass {
+		t.Errorf("checkProxy with NO_PROXY = %+v, want pass", got)
+	}
+
+	os.Setenv("HTTPS_PROXY", "http://")
+	if got := checkProxy(cfg); got.Status != Fail || got.ID != "INVALID_PROXY_HOSTNAME" {
+		t.Errorf("checkProxy with an invalid proxy = %+v, want INVALID_PROXY_HOSTNAME", got)
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	var tests = []struct {
+		repo string
+		want string
+	}{
+		{"", "k8s.gcr.io"},
+		{"registry.cn-hangzhou.aliyuncs.com/google_containers", "registry.cn-hangzhou.aliyuncs.com"},
+		{"localhost:5000", "localhost:5000"},
+	}
+	for _, tc := range tests {
+		if got := registryHost(tc.repo); got != tc.want {
+			t.Errorf("registryHost(%q) = %q, want %q", tc.repo, got, tc.want)
+		}
+	}
+}
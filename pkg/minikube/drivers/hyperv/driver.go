@@ -39,7 +39,7 @@ func init() {
 }
 
 func createHypervHost(config cfg.MachineConfig) interface{} {
-	d := hyperv.NewDriver(cfg.GetMachineName(), localpath.MiniPath())
+	d := hyperv.NewDriver(config.MachineName(), localpath.MiniPath())
 
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.VSwitch = config.HypervVirtualSwitch
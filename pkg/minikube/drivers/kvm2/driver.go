@@ -60,7 +60,7 @@ type kvmDriver struct {
 func createKVM2Host(config cfg.MachineConfig) interface{} {
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: cfg.GetMachineName(),
+			MachineName: config.MachineName(),
 			StorePath:   localpath.MiniPath(),
 			SSHUser:     "docker",
 		},
@@ -70,8 +70,8 @@ func createKVM2Host(config cfg.MachineConfig) interface{} {
 		PrivateNetwork: "minikube-net",
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
 		DiskSize:       config.DiskSize,
-		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", cfg.GetMachineName(), fmt.Sprintf("%s.rawdisk", cfg.GetMachineName())),
-		ISO:            filepath.Join(localpath.MiniPath(), "machines", cfg.GetMachineName(), "boot2docker.iso"),
+		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", config.MachineName(), fmt.Sprintf("%s.rawdisk", config.MachineName())),
+		ISO:            filepath.Join(localpath.MiniPath(), "machines", config.MachineName(), "boot2docker.iso"),
 		GPU:            config.KVMGPU,
 		Hidden:         config.KVMHidden,
 		ConnectionURI:  config.KVMQemuURI,
@@ -44,7 +44,7 @@ func init() {
 // createNoneHost creates a none Driver from a MachineConfig
 func createNoneHost(config cfg.MachineConfig) interface{} {
 	return none.NewDriver(none.Config{
-		MachineName:      cfg.GetMachineName(),
+		MachineName:      config.MachineName(),
 		StorePath:        localpath.MiniPath(),
 		ContainerRuntime: config.ContainerRuntime,
 	})
@@ -44,7 +44,7 @@ func init() {
 }
 
 func createVirtualboxHost(config cfg.MachineConfig) interface{} {
-	d := virtualbox.NewDriver(cfg.GetMachineName(), localpath.MiniPath())
+	d := virtualbox.NewDriver(config.MachineName(), localpath.MiniPath())
 
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.Memory = config.Memory
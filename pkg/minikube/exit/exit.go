@@ -69,6 +69,10 @@ func WithError(msg string, err error) {
 
 // WithProblem outputs info related to a known problem and exits.
 func WithProblem(msg string, p *problem.Problem) {
+	if out.JSON() {
+		out.ErrorT(out.Event{Message: msg, Error: fmt.Sprintf("%v", p.Err), ID: p.ID, Advice: translate.T(p.Advice), URL: p.URL, Issues: p.Issues})
+		os.Exit(Config)
+	}
 	out.ErrT(out.Empty, "")
 	out.FatalT(msg)
 	p.Display()
@@ -99,6 +103,10 @@ func WithLogEntries(msg string, err error, entries map[string][]string) {
 func displayError(msg string, err error) {
 	// use Warning because Error will display a duplicate message to stderr
 	glog.Warningf(fmt.Sprintf("%s: %v", msg, err))
+	if out.JSON() {
+		out.ErrorT(out.Event{Message: msg, Error: fmt.Sprintf("%v", err)})
+		return
+	}
 	out.ErrT(out.Empty, "")
 	out.FatalT("{{.msg}}: {{.err}}", out.V{"msg": translate.T(msg), "err": err})
 	out.ErrT(out.Empty, "")
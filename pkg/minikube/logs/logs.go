@@ -20,11 +20,13 @@ package logs
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
@@ -55,6 +57,10 @@ var importantPods = []string{
 // include usage messages from a failed binary, but small enough to not include irrelevant problems.
 const lookBackwardsCount = 200
 
+// problemsTimeout is how long to wait for each log when looking for problems, so that an unresponsive
+// runtime does not prevent the problems found in other logs from being reported.
+const problemsTimeout = 30 * time.Second
+
 // Follow follows logs from multiple files in tail(1) format
 func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner) error {
 	cs := []string{}
@@ -76,13 +82,14 @@ func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner comma
 	cmds := logCommands(r, bs, lookBackwardsCount, false)
 	for name, cmd := range cmds {
 		glog.Infof("Gathering logs for %s ...", name)
-		var b bytes.Buffer
-		err := runner.CombinedOutputTo(cmds[name], &b)
+		ctx, cancel := context.WithTimeout(context.Background(), problemsTimeout)
+		rr, err := runner.RunCmd(ctx, command.Cmd{Command: cmds[name]})
+		cancel()
 		if err != nil {
 			glog.Warningf("failed %s: %s: %v", name, cmd, err)
 			continue
 		}
-		scanner := bufio.NewScanner(&b)
+		scanner := bufio.NewScanner(strings.NewReader(rr.Output()))
 		problems := []string{}
 		for scanner.Scan() {
 			l := scanner.Text()
@@ -152,7 +159,12 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Run
 func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, length int, follow bool) map[string]string {
 	cmds := bs.LogCommands(bootstrapper.LogOptions{Lines: length, Follow: follow})
 	for _, pod := range importantPods {
-		ids, err := r.ListContainers(pod)
+		// Docker has always shown the logs of exited containers too, the CRI runtimes only those of running ones
+		o := cruntime.ListOptions{Name: pod, State: cruntime.Running}
+		if r.Name() == "Docker" {
+			o.State = cruntime.All
+		}
+		ids, err := r.ListContainers(o)
 		if err != nil {
 			glog.Errorf("Failed to list containers for %q: %v", pod, err)
 			continue
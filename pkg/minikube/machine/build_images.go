@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// buildRoot is where build contexts are unpacked within the guest VM
+var buildRoot = path.Join(constants.GuestPersistentDir, "build")
+
+// BuildImage builds an image inside the guest VM with the builder of the container runtime.
+// The build context is archived on the host, copied into the VM, and removed once the build is done.
+func BuildImage(cmd command.Runner, r cruntime.Manager, srcDir string, tag string) error {
+	fi, err := os.Stat(srcDir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", srcDir)
+	}
+
+	f, err := ioutil.TempFile("", "build-context.*.tar")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := tarDir(srcDir, f); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "archiving %s", srcDir)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("build.%d", time.Now().UnixNano())
+	archive := path.Join(buildRoot, name+".tar")
+	dir := path.Join(buildRoot, name)
+	a, err := assets.NewFileAsset(f.Name(), buildRoot, name+".tar", "0644")
+	if err != nil {
+		return errors.Wrap(err, "creating copyable file asset")
+	}
+	if err := cmd.Copy(a); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+	defer func() {
+		if err := cmd.Run(fmt.Sprintf("sudo rm -rf %s %s", dir, archive)); err != nil {
+			glog.Warningf("unable to remove build context %s: %v", dir, err)
+		}
+	}()
+	if err := cmd.Run(fmt.Sprintf("sudo mkdir -p %s && sudo tar -C %s -xf %s", dir, dir, archive)); err != nil {
+		return errors.Wrap(err, "unpacking build context")
+	}
+
+	if err := r.BuildImage(dir, tag); err != nil {
+		return errors.Wrapf(err, "%s build %s", r.Name(), tag)
+	}
+	glog.Infof("Successfully built image %s from %s", tag, srcDir)
+	return nil
+}
+
+// tarDir writes the regular files, directories and symbolic links within dir to w, with paths relative to dir.
+// Symbolic links are archived as links, as with docker build. Other kinds of files are rejected.
+func tarDir(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		link := ""
+		switch {
+		case fi.IsDir(), fi.Mode().IsRegular():
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s is not a regular file, directory or symbolic link", rel)
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTarDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "context")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"Dockerfile":      "FROM busybox\nCOPY app /app\n",
+		"app/main.sh":     "echo hello",
+		"app/data/a.conf": "a=1",
+	}
+	for name, contents := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	if err := os.Symlink("main.sh", filepath.Join(dir, "app", "current")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	var b bytes.Buffer
+	if err := tarDir(dir, &b); err != nil {
+		t.Fatalf("tarDir: %v", err)
+	}
+
+	got := map[string]string{}
+	tr := tar.NewReader(&b)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			got[hdr.Name] = ""
+			continue
+		}
+		if hdr.Typeflag == tar.TypeSymlink {
+			got[hdr.Name] = "-> " + hdr.Linkname
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got[hdr.Name] = string(data)
+	}
+
+	want := map[string]string{"app/": "", "app/data/": "", "app/current": "-> main.sh"}
+	for name, contents := range files {
+		want[name] = contents
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("archive differs: (-want +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// bundleManifest is the name of the manifest within a cache bundle, which is always its first entry
+const bundleManifest = "manifest.json"
+
+// BundleManifest describes the contents of a cache bundle
+type BundleManifest struct {
+	MinikubeVersion   string
+	KubernetesVersion string
+	Created           time.Time
+	// Files maps the paths of the bundled files, relative to the minikube home directory, to their SHA-256 checksums
+	Files map[string]string
+}
+
+// BundleFiles returns the cached files required to start a cluster offline, relative to the minikube home directory
+func BundleFiles(k8sVersion string, imageRepository string, clusterBootstrapper string, isoURL string) ([]string, error) {
+	var files []string
+	for _, image := range bootstrapper.GetCachedImageList(imageRepository, k8sVersion, clusterBootstrapper) {
+		files = append(files, sanitizeCacheDir(filepath.Join(constants.ImageCacheDir, image)))
+	}
+	for _, bin := range bootstrapper.GetCachedBinaryList(clusterBootstrapper) {
+		files = append(files, localpath.MakeMiniPath("cache", k8sVersion, bin))
+	}
+	files = append(files, util.DefaultDownloader{}.GetISOCacheFilepath(isoURL))
+
+	for i, f := range files {
+		rel, err := filepath.Rel(localpath.MiniPath(), f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "relative path of %s", f)
+		}
+		files[i] = filepath.ToSlash(rel)
+	}
+	return files, nil
+}
+
+// ExportCache writes files, relative to the minikube home directory, to a tar bundle along with a manifest of their checksums
+func ExportCache(dst string, m *BundleManifest, files []string) error {
+	m.Files = map[string]string{}
+	for _, f := range files {
+		sum, err := fileChecksum(filepath.Join(localpath.MiniPath(), filepath.FromSlash(f)))
+		if err != nil {
+			return errors.Wrapf(err, "checksum of %s", f)
+		}
+		m.Files[f] = sum
+	}
+	data, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal manifest")
+	}
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer os.Remove(tmp)
+
+	tw := tar.NewWriter(out)
+	if err := tw.WriteHeader(&tar.Header{Name: bundleManifest, Mode: 0644, Size: int64(len(data)), ModTime: m.Created}); err != nil {
+		out.Close()
+		return errors.Wrap(err, "write manifest")
+	}
+	if _, err := tw.Write(data); err != nil {
+		out.Close()
+		return errors.Wrap(err, "write manifest")
+	}
+	for _, f := range files {
+		if err := addToBundle(tw, f); err != nil {
+			out.Close()
+			return errors.Wrapf(err, "adding %s", f)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		out.Close()
+		return errors.Wrap(err, "close bundle")
+	}
+	if err := out.Close(); err != nil {
+		return errors.Wrap(err, "close bundle")
+	}
+	return os.Rename(tmp, dst)
+}
+
+// addToBundle adds a file, relative to the minikube home directory, to a cache bundle
+func addToBundle(tw *tar.Writer, name string) error {
+	f, err := os.Open(filepath.Join(localpath.MiniPath(), filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// ImportCache verifies the files of a cache bundle against its manifest, and extracts them into the minikube home directory.
+// The files are staged until all of them have been verified, so that an invalid bundle leaves the cache untouched.
+func ImportCache(src string) (*BundleManifest, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading bundle")
+	}
+	if hdr.Name != bundleManifest {
+		return nil, fmt.Errorf("%s is not a cache bundle: expected %s, found %s", src, bundleManifest, hdr.Name)
+	}
+	m := &BundleManifest{}
+	if err := json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "parsing manifest")
+	}
+
+	// Stage within the minikube home directory, so that files can be renamed into place
+	if err := os.MkdirAll(localpath.MiniPath(), 0755); err != nil {
+		return nil, err
+	}
+	staging, err := ioutil.TempDir(localpath.MiniPath(), ".import")
+	if err != nil {
+		return nil, errors.Wrap(err, "staging directory")
+	}
+	defer os.RemoveAll(staging)
+
+	var names []string
+	seen := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading bundle")
+		}
+		want, ok := m.Files[hdr.Name]
+		if !ok || !validBundlePath(hdr.Name) || seen[hdr.Name] {
+			return nil, fmt.Errorf("unexpected file in bundle: %s", hdr.Name)
+		}
+		if err := extractFromBundle(tr, hdr, want, filepath.Join(staging, "new", filepath.FromSlash(hdr.Name))); err != nil {
+			return nil, errors.Wrapf(err, "extracting %s", hdr.Name)
+		}
+		names = append(names, hdr.Name)
+		seen[hdr.Name] = true
+	}
+	for name := range m.Files {
+		if !seen[name] {
+			return nil, fmt.Errorf("bundle is missing %s", name)
+		}
+	}
+	if err := swapIntoPlace(staging, names); err != nil {
+		return nil, errors.Wrap(err, "importing")
+	}
+	return m, nil
+}
+
+// swapIntoPlace moves the files staged in staging/new into the minikube home directory, keeping the files they replace
+// in staging/old. If a file can not be moved, the files which were already moved are put back.
+func swapIntoPlace(staging string, names []string) error {
+	var moved []string
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			dst := filepath.Join(localpath.MiniPath(), filepath.FromSlash(moved[i]))
+			old := filepath.Join(staging, "old", filepath.FromSlash(moved[i]))
+			if _, err := os.Stat(old); err == nil {
+				if err := os.Rename(old, dst); err != nil {
+					glog.Errorf("unable to restore %s: %v", dst, err)
+				}
+				continue
+			}
+			if err := os.Remove(dst); err != nil {
+				glog.Errorf("unable to remove %s: %v", dst, err)
+			}
+		}
+	}
+
+	for _, name := range names {
+		dst := filepath.Join(localpath.MiniPath(), filepath.FromSlash(name))
+		old := filepath.Join(staging, "old", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			rollback()
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(old), 0700); err != nil {
+			rollback()
+			return err
+		}
+		if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) {
+			rollback()
+			return err
+		}
+		// Track the file before moving it, so that the file it replaced is restored if the move fails
+		moved = append(moved, name)
+		if err := os.Rename(filepath.Join(staging, "new", filepath.FromSlash(name)), dst); err != nil {
+			rollback()
+			return err
+		}
+		glog.Infof("imported %s", dst)
+	}
+	return nil
+}
+
+// validBundlePath returns whether a path within a bundle stays within the minikube home directory
+func validBundlePath(name string) bool {
+	clean := path.Clean(name)
+	return clean == name && !path.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// extractFromBundle writes a file of a bundle to dst, returning an error if its checksum does not match
+func extractFromBundle(r io.Reader, hdr *tar.Header, checksum string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode).Perm())
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != checksum {
+		return fmt.Errorf("checksum mismatch: got %s, expected %s", got, checksum)
+	}
+	return nil
+}
+
+// fileChecksum returns the SHA-256 checksum of a file, hex encoded
+func fileChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
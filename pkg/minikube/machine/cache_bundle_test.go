@@ -0,0 +1,203 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+// writeBundle writes a bundle containing a manifest and the given files, without computing checksums
+func writeBundle(t *testing.T, dst string, m *BundleManifest, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(dst)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	entries := []struct{ name, contents string }{{bundleManifest, string(data)}}
+	for name, contents := range files {
+		entries = append(entries, struct{ name, contents string }{name, contents})
+	}
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.contents))}); err != nil {
+			t.Fatalf("header: %v", err)
+		}
+		if _, err := tw.Write([]byte(e.contents)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestExportImportCache(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"cache/images/k8s.gcr.io/pause_3.1": "pause image",
+		"cache/v1.16.2/kubeadm":             "kubeadm binary",
+		"cache/iso/minikube-v1.5.0.iso":     "boot image",
+	}
+	var names []string
+	for name, contents := range files {
+		p := localpath.MakeMiniPath(filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	bundle := filepath.Join(tempDir, "bundle.tar")
+	if err := ExportCache(bundle, &BundleManifest{KubernetesVersion: "v1.16.2"}, names); err != nil {
+		t.Fatalf("ExportCache: %v", err)
+	}
+	if err := os.RemoveAll(localpath.MakeMiniPath("cache")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	m, err := ImportCache(bundle)
+	if err != nil {
+		t.Fatalf("ImportCache: %v", err)
+	}
+	if m.KubernetesVersion != "v1.16.2" || len(m.Files) != len(files) {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	for name, contents := range files {
+		data, err := ioutil.ReadFile(localpath.MakeMiniPath(filepath.FromSlash(name)))
+		if err != nil || string(data) != contents {
+			t.Errorf("%s = %q, %v, want %q", name, data, err, contents)
+		}
+	}
+
+	if err := ExportCache(bundle, &BundleManifest{}, []string{"cache/missing"}); err == nil {
+		t.Errorf("ExportCache of a missing file succeeded, expected error")
+	}
+}
+
+func TestImportCacheInvalid(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	bundle := filepath.Join(tempDir, "bundle.tar")
+
+	var cases = []struct {
+		description string
+		checksums   map[string]string
+		files       map[string]string
+	}{
+		{
+			description: "checksum mismatch",
+			checksums:   map[string]string{"cache/v1.16.2/kubelet": "0000"},
+			files:       map[string]string{"cache/v1.16.2/kubelet": "tampered"},
+		},
+		{
+			description: "file missing from bundle",
+			checksums:   map[string]string{"cache/v1.16.2/kubelet": "0000"},
+		},
+		{
+			description: "file missing from manifest",
+			checksums:   map[string]string{},
+			files:       map[string]string{"cache/v1.16.2/kubelet": "unexpected"},
+		},
+		{
+			description: "path outside of the minikube home directory",
+			checksums:   map[string]string{"../escape": "0000"},
+			files:       map[string]string{"../escape": "escaped"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			writeBundle(t, bundle, &BundleManifest{Files: tc.checksums}, tc.files)
+			if _, err := ImportCache(bundle); err == nil {
+				t.Errorf("ImportCache succeeded, expected error")
+			}
+		})
+	}
+	if _, err := os.Stat(localpath.MakeMiniPath("cache", "v1.16.2", "kubelet")); !os.IsNotExist(err) {
+		t.Errorf("file with a mismatched checksum was imported: %v", err)
+	}
+}
+
+func TestImportCacheLeavesCacheOnFailure(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	bundle := filepath.Join(tempDir, "bundle.tar")
+
+	kubeadm := localpath.MakeMiniPath("cache", "v1.16.2", "kubeadm")
+	if err := os.MkdirAll(filepath.Dir(kubeadm), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(kubeadm, []byte("old kubeadm"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// A valid file must not be imported when another file of the bundle is invalid, whichever comes first
+	sum := sha256.Sum256([]byte("new kubeadm"))
+	checksums := map[string]string{
+		"cache/v1.16.2/kubeadm": hex.EncodeToString(sum[:]),
+		"cache/v1.16.2/kubelet": "0000",
+	}
+	files := map[string]string{
+		"cache/v1.16.2/kubeadm": "new kubeadm",
+		"cache/v1.16.2/kubelet": "tampered",
+	}
+	writeBundle(t, bundle, &BundleManifest{Files: checksums}, files)
+	if _, err := ImportCache(bundle); err == nil {
+		t.Fatalf("ImportCache succeeded, expected error")
+	}
+	if data, err := ioutil.ReadFile(kubeadm); err != nil || string(data) != "old kubeadm" {
+		t.Errorf("kubeadm = %q, %v, want %q", data, err, "old kubeadm")
+	}
+	if _, err := os.Stat(localpath.MakeMiniPath("cache", "v1.16.2", "kubelet")); !os.IsNotExist(err) {
+		t.Errorf("kubelet was imported: %v", err)
+	}
+	staged, err := filepath.Glob(localpath.MakeMiniPath(".import*"))
+	if err != nil || len(staged) != 0 {
+		t.Errorf("staging directories left behind: %v, %v", staged, err)
+	}
+
+	// Once the bundle is valid, the files are replaced
+	sum = sha256.Sum256([]byte("new kubelet"))
+	checksums["cache/v1.16.2/kubelet"] = hex.EncodeToString(sum[:])
+	files["cache/v1.16.2/kubelet"] = "new kubelet"
+	writeBundle(t, bundle, &BundleManifest{Files: checksums}, files)
+	if _, err := ImportCache(bundle); err != nil {
+		t.Fatalf("ImportCache: %v", err)
+	}
+	if data, err := ioutil.ReadFile(kubeadm); err != nil || string(data) != "new kubeadm" {
+		t.Errorf("kubeadm = %q, %v, want %q", data, err, "new kubeadm")
+	}
+}
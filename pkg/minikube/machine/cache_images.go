@@ -90,26 +90,41 @@ func CacheImages(images []string, cacheDir string) error {
 	return nil
 }
 
-// LoadImages loads previously cached images into the container runtime
-func LoadImages(cmd command.Runner, images []string, cacheDir string) error {
+// LoadImages loads previously cached images into the container runtime.
+// Images which are already present in the runtime are skipped.
+func LoadImages(cmd command.Runner, k8s config.KubernetesConfig, images []string, cacheDir string) error {
 	glog.Infof("LoadImages start: %s", images)
 	defer glog.Infof("LoadImages end")
 
-	var g errgroup.Group
-	// Load profile cluster config from file
-	cc, err := config.Load()
-	if err != nil && !os.IsNotExist(err) {
-		glog.Errorln("Error loading profile config: ", err)
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cmd})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	present := map[string]bool{}
+	existing, err := r.ListImages()
+	if err != nil {
+		glog.Warningf("unable to list %s images, loading all of them: %v", r.Name(), err)
 	}
+	for _, image := range existing {
+		present[normalizeImageName(image)] = true
+	}
+
+	var g errgroup.Group
 	for _, image := range images {
 		image := image
+		if present[normalizeImageName(image)] {
+			glog.Infof("%s is already present in %s, skipping", image, r.Name())
+			touchCachedImage(sanitizeCacheDir(filepath.Join(cacheDir, image)))
+			continue
+		}
 		g.Go(func() error {
 			src := filepath.Join(cacheDir, image)
 			src = sanitizeCacheDir(src)
-			if err := transferAndLoadImage(cmd, cc.KubernetesConfig, src); err != nil {
+			if err := transferAndLoadImage(cmd, r, src); err != nil {
 				glog.Warningf("Failed to load %s: %v", src, err)
 				return errors.Wrapf(err, "loading image %s", src)
 			}
+			touchCachedImage(src)
 			return nil
 		})
 	}
@@ -120,6 +135,16 @@ func LoadImages(cmd command.Runner, images []string, cacheDir string) error {
 	return nil
 }
 
+// normalizeImageName returns the fully qualified name of an image, so that the names used by different runtimes,
+// such as busybox:latest and docker.io/library/busybox:latest, can be compared
+func normalizeImageName(image string) string {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return image
+	}
+	return ref.Name()
+}
+
 // CacheAndLoadImages caches and loads images
 func CacheAndLoadImages(images []string) error {
 	if err := CacheImages(images, constants.ImageCacheDir); err != nil {
@@ -139,7 +164,36 @@ func CacheAndLoadImages(images []string) error {
 	if err != nil {
 		return err
 	}
-	return LoadImages(runner, images, constants.ImageCacheDir)
+	// Load profile cluster config from file
+	cc, err := config.Load()
+	if err != nil && !os.IsNotExist(err) {
+		glog.Errorln("Error loading profile config: ", err)
+	}
+	var k8s config.KubernetesConfig
+	if cc != nil {
+		k8s = cc.KubernetesConfig
+	}
+	return LoadImages(runner, k8s, images, constants.ImageCacheDir)
+}
+
+// LoadImage loads an image into the container runtime, from either a tarball or an image name.
+// Unlike LoadImages, the image is always loaded, and it is not added to the cache.
+func LoadImage(cmd command.Runner, r cruntime.Manager, image string) error {
+	if fi, err := os.Stat(image); err == nil && !fi.IsDir() {
+		return transferAndLoadImage(cmd, r, image)
+	}
+
+	tmp, err := ioutil.TempDir("", "minikube-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := sanitizeCacheDir(filepath.Join(tmp, image))
+	if err := CacheImage(image, src); err != nil {
+		return errors.Wrapf(err, "retrieving image %s", image)
+	}
+	return transferAndLoadImage(cmd, r, src)
 }
 
 // # ParseReference cannot have a : in the directory path
@@ -206,7 +260,7 @@ func getWindowsVolumeNameCmd(d string) (string, error) {
 }
 
 // transferAndLoadImage transfers and loads a single image from the cache
-func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, src string) error {
+func transferAndLoadImage(cr command.Runner, r cruntime.Manager, src string) error {
 	glog.Infof("Loading image from cache: %s", src)
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
@@ -221,10 +275,6 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, src st
 		return errors.Wrap(err, "transferring cached image")
 	}
 
-	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
-	if err != nil {
-		return errors.Wrap(err, "runtime")
-	}
 	loadImageLock.Lock()
 	defer loadImageLock.Unlock()
 
@@ -237,6 +287,28 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, src st
 	return nil
 }
 
+// touchCachedImage records the use of a cached image, so that pruning the cache evicts the least recently used images first.
+// Paths outside of the image cache, such as tarballs of the user, are left alone.
+func touchCachedImage(src string) {
+	if !inImageCache(src) {
+		glog.Infof("%s is not in the image cache, not updating its modification time", src)
+		return
+	}
+	now := time.Now()
+	if err := os.Chtimes(src, now, now); err != nil && !os.IsNotExist(err) {
+		glog.Warningf("unable to update modification time of %s: %v", src, err)
+	}
+}
+
+// inImageCache returns whether path is within the image cache directory
+func inImageCache(path string) bool {
+	rel, err := filepath.Rel(constants.ImageCacheDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // DeleteFromImageCacheDir deletes images from the cache
 func DeleteFromImageCacheDir(images []string) error {
 	for _, image := range images {
@@ -19,8 +19,12 @@ package machine
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/constants"
 )
 
 func TestReplaceWinDriveLetterToVolumeName(t *testing.T) {
@@ -61,3 +65,59 @@ func TestHasWindowsDriveLetter(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeImageName(t *testing.T) {
+	cases := []struct {
+		a    string
+		b    string
+		same bool
+	}{
+		{"busybox", "busybox:latest", true},
+		{"busybox:latest", "docker.io/library/busybox:latest", true},
+		{"k8s.gcr.io/pause:3.1", "k8s.gcr.io/pause:3.1", true},
+		{"k8s.gcr.io/pause:3.1", "k8s.gcr.io/pause:3.0", false},
+		{"busybox:latest", "k8s.gcr.io/busybox:latest", false},
+	}
+
+	for _, tc := range cases {
+		a, b := normalizeImageName(tc.a), normalizeImageName(tc.b)
+		if (a == b) != tc.same {
+			t.Errorf("normalizeImageName(%q) = %q, normalizeImageName(%q) = %q, want same: %t", tc.a, a, tc.b, b, tc.same)
+		}
+	}
+}
+
+func TestTouchCachedImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "touchcache")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(old string) { constants.ImageCacheDir = old }(constants.ImageCacheDir)
+	constants.ImageCacheDir = filepath.Join(dir, "cache", "images")
+
+	cached := filepath.Join(constants.ImageCacheDir, "k8s.gcr.io", "pause_3.1")
+	own := filepath.Join(dir, "pause.tar")
+	old := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	for _, p := range []string{cached, own} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte("image"), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	touchCachedImage(cached)
+	touchCachedImage(own)
+
+	if fi, err := os.Stat(cached); err != nil || !fi.ModTime().After(old) {
+		t.Errorf("cached image %s was not touched", cached)
+	}
+	if fi, err := os.Stat(own); err != nil || !fi.ModTime().Equal(old) {
+		t.Errorf("image %s outside of the cache was touched", own)
+	}
+}
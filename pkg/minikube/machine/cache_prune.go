@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// CachedImage describes an image tarball within the image cache
+type CachedImage struct {
+	// Name is the name of the image, or the path of the tarball within the cache when nothing references it
+	Name string
+	// Path is the path of the tarball
+	Path string
+	Size int64
+	// LastUsed is when the image was cached, or last loaded into a cluster
+	LastUsed time.Time
+	// References describe the Kubernetes versions, config entries and flags requiring the image
+	References []string
+	// Kept is whether the image was explicitly kept, and may never be pruned
+	Kept bool
+}
+
+// ImageReferences maps image names to descriptions of what requires them
+type ImageReferences map[string][]string
+
+// Add records that an image is required by ref
+func (r ImageReferences) Add(image string, ref string) {
+	for _, existing := range r[image] {
+		if existing == ref {
+			return
+		}
+	}
+	r[image] = append(r[image], ref)
+}
+
+// AddProfiles records the images required by the Kubernetes version of each profile
+func (r ImageReferences) AddProfiles(profiles []*config.Profile, clusterBootstrapper string) {
+	for _, p := range profiles {
+		for _, image := range ProfileImages(p, clusterBootstrapper) {
+			r.Add(image, "kubernetes "+p.Config.KubernetesConfig.KubernetesVersion)
+		}
+	}
+}
+
+// ProfileImages returns the images required by the Kubernetes version of a profile
+func ProfileImages(p *config.Profile, clusterBootstrapper string) []string {
+	if p.Config == nil {
+		return nil
+	}
+	k8s := p.Config.KubernetesConfig
+	return bootstrapper.GetCachedImageList(k8s.ImageRepository, k8s.KubernetesVersion, clusterBootstrapper)
+}
+
+// ListCachedImages returns the image tarballs within the image cache, sorted by name.
+// The images in keep are marked as kept, in addition to being referenced.
+func ListCachedImages(refs ImageReferences, keep []string) ([]*CachedImage, error) {
+	for _, image := range keep {
+		refs.Add(image, "kept")
+	}
+	names := map[string]string{}
+	for image := range refs {
+		names[sanitizeCacheDir(filepath.Join(constants.ImageCacheDir, image))] = image
+	}
+	kept := map[string]bool{}
+	for _, image := range keep {
+		kept[sanitizeCacheDir(filepath.Join(constants.ImageCacheDir, image))] = true
+	}
+
+	var images []*CachedImage
+	err := filepath.Walk(constants.ImageCacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ci := &CachedImage{Path: path, Size: info.Size(), LastUsed: info.ModTime(), Kept: kept[path]}
+		if name, ok := names[path]; ok {
+			ci.Name = name
+			ci.References = refs[name]
+		} else {
+			rel, err := filepath.Rel(constants.ImageCacheDir, path)
+			if err != nil {
+				return err
+			}
+			ci.Name = filepath.ToSlash(rel)
+		}
+		images = append(images, ci)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "walking image cache")
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	return images, nil
+}
+
+// ImagesToPrune selects the images to remove from the cache: those without references, followed by the least
+// recently used images until the remaining images fit within maxSize bytes. Kept images are never selected.
+// A maxSize of 0 disables the size budget.
+func ImagesToPrune(images []*CachedImage, maxSize int64) []*CachedImage {
+	var prune, remaining []*CachedImage
+	var size int64
+	for _, ci := range images {
+		if len(ci.References) == 0 && !ci.Kept {
+			prune = append(prune, ci)
+			continue
+		}
+		remaining = append(remaining, ci)
+		size += ci.Size
+	}
+	if maxSize <= 0 || size <= maxSize {
+		return prune
+	}
+
+	sort.SliceStable(remaining, func(i, j int) bool { return remaining[i].LastUsed.Before(remaining[j].LastUsed) })
+	for _, ci := range remaining {
+		if size <= maxSize {
+			break
+		}
+		if ci.Kept {
+			continue
+		}
+		prune = append(prune, ci)
+		size -= ci.Size
+	}
+	return prune
+}
+
+// RemoveCachedImages removes image tarballs from the cache, along with the directories left empty
+func RemoveCachedImages(images []*CachedImage) error {
+	for _, ci := range images {
+		glog.Infof("Removing %s from the image cache", ci.Path)
+		if err := os.Remove(ci.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return cleanImageCacheDir()
+}
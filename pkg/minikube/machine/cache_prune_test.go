@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestListCachedImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(old string) { constants.ImageCacheDir = old }(constants.ImageCacheDir)
+	constants.ImageCacheDir = dir
+
+	for _, f := range []string{"k8s.gcr.io/pause_3.1", "k8s.gcr.io/pause_3.0", "busybox_latest"} {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(f), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	refs := ImageReferences{}
+	refs.Add("k8s.gcr.io/pause:3.1", "kubernetes v1.16.2")
+	refs.Add("k8s.gcr.io/pause:3.1", "kubernetes v1.16.2")
+	images, err := ListCachedImages(refs, []string{"busybox:latest"})
+	if err != nil {
+		t.Fatalf("ListCachedImages: %v", err)
+	}
+
+	got := map[string][]string{}
+	for _, ci := range images {
+		got[ci.Name] = ci.References
+	}
+	want := map[string][]string{
+		"busybox:latest":       {"kept"},
+		"k8s.gcr.io/pause:3.1": {"kubernetes v1.16.2"},
+		"k8s.gcr.io/pause_3.0": nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListCachedImages differs: (-want +got)\n%s", diff)
+	}
+
+	if err := RemoveCachedImages(ImagesToPrune(images, 0)); err != nil {
+		t.Fatalf("RemoveCachedImages: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "k8s.gcr.io", "pause_3.0")); !os.IsNotExist(err) {
+		t.Errorf("unreferenced image was not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "k8s.gcr.io", "pause_3.1")); err != nil {
+		t.Errorf("referenced image was removed: %v", err)
+	}
+}
+
+func TestImagesToPrune(t *testing.T) {
+	now := time.Now()
+	images := []*CachedImage{
+		{Name: "unreferenced", Size: 10, LastUsed: now},
+		{Name: "old", Size: 100, LastUsed: now.Add(-3 * time.Hour), References: []string{"kubernetes v1.15.0"}},
+		{Name: "kept", Size: 100, LastUsed: now.Add(-4 * time.Hour), References: []string{"kept"}, Kept: true},
+		{Name: "recent", Size: 100, LastUsed: now.Add(-1 * time.Hour), References: []string{"kubernetes v1.16.2"}},
+		{Name: "newest", Size: 100, LastUsed: now, References: []string{"kubernetes v1.16.2"}},
+	}
+
+	var tests = []struct {
+		maxSize int64
+		want    []string
+	}{
+		{maxSize: 0, want: []string{"unreferenced"}},
+		{maxSize: 400, want: []string{"unreferenced"}},
+		{maxSize: 300, want: []string{"unreferenced", "old"}},
+		{maxSize: 150, want: []string{"unreferenced", "old", "recent", "newest"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, ci := range ImagesToPrune(images, tc.maxSize) {
+			got = append(got, ci.Name)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("ImagesToPrune(%d) differs: (-want +got)\n%s", tc.maxSize, diff)
+		}
+	}
+}
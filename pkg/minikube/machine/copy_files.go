@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// guestEntry is a file or directory within a tree on the guest
+type guestEntry struct {
+	// Type is the file type reported by find: 'f' for regular files, 'd' for directories
+	Type byte
+	// Mode has the permissions, along with the setuid, setgid and sticky bits
+	Mode os.FileMode
+	// Path is relative to the root of the tree, and empty for the root itself
+	Path string
+}
+
+// listGuestTree lists a file or directory tree on the guest, parents first
+func listGuestTree(cmd command.Runner, root string) ([]guestEntry, error) {
+	rr, err := cmd.RunCmd(context.Background(), command.Cmd{Command: fmt.Sprintf(`sudo find %s -printf '%%y %%m %%P\0'`, command.ShellQuote(root))})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing %s", root)
+	}
+	return parseGuestTree(rr.Stdout.String())
+}
+
+// parseGuestTree parses the NUL separated output of find -printf '%y %m %P\0'
+func parseGuestTree(out string) ([]guestEntry, error) {
+	var entries []guestEntry
+	for _, line := range strings.Split(out, "\x00") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || len(fields[0]) != 1 {
+			return nil, fmt.Errorf("unexpected find output: %q", line)
+		}
+		mode, err := strconv.ParseUint(fields[1], 8, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing mode of %q", line)
+		}
+		entries = append(entries, guestEntry{Type: fields[0][0], Mode: command.FileMode(mode), Path: fields[2]})
+	}
+	return entries, nil
+}
+
+// chmodMode converts a FileMode to the octal mode of chmod, including the setuid, setgid and sticky bits
+func chmodMode(m os.FileMode) uint32 {
+	mode := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		mode |= 04000
+	}
+	if m&os.ModeSetgid != 0 {
+		mode |= 02000
+	}
+	if m&os.ModeSticky != 0 {
+		mode |= 01000
+	}
+	return mode
+}
+
+// CopyFromGuest copies a file or directory from the guest to the host, recursively and preserving permissions.
+// As with cp, if dst is an existing directory, the source is copied into it.
+func CopyFromGuest(cmd command.Runner, src string, dst string) error {
+	entries, err := listGuestTree(cmd, src)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, path.Base(src))
+	}
+
+	var dirs []guestEntry
+	for _, e := range entries {
+		from := path.Join(src, e.Path)
+		to := filepath.Join(dst, filepath.FromSlash(e.Path))
+		switch e.Type {
+		case 'd':
+			// Directories are writable until their contents are copied
+			if err := os.MkdirAll(to, 0755); err != nil {
+				return err
+			}
+			dirs = append(dirs, e)
+		case 'f':
+			if err := downloadFile(cmd, from, to, e.Mode); err != nil {
+				return err
+			}
+		default:
+			glog.Warningf("skipping %s, which is not a regular file or directory", from)
+		}
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(filepath.Join(dst, filepath.FromSlash(dirs[i].Path)), dirs[i].Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadFile copies a regular file from the guest to the host
+func downloadFile(cmd command.Runner, src string, dst string, mode os.FileMode) error {
+	glog.Infof("Downloading %s to %s", src, dst)
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if err := cmd.ReadFile(src, f); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "reading %s", src)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// The mode given to OpenFile is reduced by the umask, and not applied to existing files
+	return os.Chmod(dst, mode)
+}
+
+// CopyToGuest copies a file or directory from the host to the guest, recursively and preserving permissions.
+// As with cp, if dst is an existing directory, the source is copied into it.
+func CopyToGuest(cmd command.Runner, src string, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	if err := cmd.Run(fmt.Sprintf("sudo test -d %s", command.ShellQuote(dst))); err == nil {
+		dst = path.Join(dst, filepath.Base(src))
+	}
+
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		to := path.Join(dst, filepath.ToSlash(rel))
+		switch {
+		case info.IsDir():
+			return cmd.Run(fmt.Sprintf("sudo mkdir -p %s && sudo chmod %04o %s", command.ShellQuote(to), chmodMode(info.Mode()), command.ShellQuote(to)))
+		case info.Mode().IsRegular():
+			return uploadFile(cmd, p, to, info.Mode())
+		default:
+			glog.Warningf("skipping %s, which is not a regular file or directory", p)
+			return nil
+		}
+	})
+}
+
+// uploadFile copies a regular file from the host to the guest
+func uploadFile(cmd command.Runner, src string, dst string, mode os.FileMode) error {
+	f, err := assets.NewFileAsset(src, path.Dir(dst), path.Base(dst), fmt.Sprintf("%04o", chmodMode(mode)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := cmd.Copy(f); err != nil {
+		return errors.Wrapf(err, "copying %s", src)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestCopyFromGuest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := command.NewFakeCommandRunner()
+	cmd.SetCommandToOutput(map[string]string{
+		"sudo find '/var/log/app' -printf '%y %m %P\\0'": "d 750 \x00f 640 a.log\x00d 755 sub\x00f 600 sub/b.log\x00f 4750 tool\x00l 777 current\x00",
+	})
+	cmd.SetFileToContents(map[string]string{
+		"/var/log/app/a.log":     "a",
+		"/var/log/app/sub/b.log": "b",
+		"/var/log/app/tool":      "t",
+	})
+
+	// The destination is an existing directory, so the source is copied into it
+	if err := CopyFromGuest(cmd, "/var/log/app", dir); err != nil {
+		t.Fatalf("CopyFromGuest: %v", err)
+	}
+	var tests = []struct {
+		path     string
+		contents string
+		mode     os.FileMode
+	}{
+		{"app", "", 0750 | os.ModeDir},
+		{"app/a.log", "a", 0640},
+		{"app/sub", "", 0755 | os.ModeDir},
+		{"app/sub/b.log", "b", 0600},
+		{"app/tool", "t", 0750 | os.ModeSetuid},
+	}
+	for _, tc := range tests {
+		p := filepath.Join(dir, filepath.FromSlash(tc.path))
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Errorf("stat %s: %v", tc.path, err)
+			continue
+		}
+		if runtime.GOOS != "windows" && fi.Mode() != tc.mode {
+			t.Errorf("%s mode = %v, want %v", tc.path, fi.Mode(), tc.mode)
+		}
+		if fi.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Errorf("read %s: %v", tc.path, err)
+		}
+		if string(data) != tc.contents {
+			t.Errorf("%s = %q, want %q", tc.path, data, tc.contents)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "app", "current")); !os.IsNotExist(err) {
+		t.Errorf("symlink was copied: %v", err)
+	}
+}
+
+func TestChmodMode(t *testing.T) {
+	var tests = []struct {
+		mode os.FileMode
+		want uint32
+	}{
+		{0644, 0644},
+		{0755 | os.ModeSetuid, 04755},
+		{0750 | os.ModeSetgid | os.ModeDir, 02750},
+		{0777 | os.ModeSticky | os.ModeDir, 01777},
+		{0711 | os.ModeSetuid | os.ModeSetgid, 06711},
+	}
+	for _, tc := range tests {
+		if got := chmodMode(tc.mode); got != tc.want {
+			t.Errorf("chmodMode(%v) = %04o, want %04o", tc.mode, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror implements a pull-through cache of a container image registry, run on the host
+// so that images survive the deletion of the clusters which pulled them.
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DockerHub is the URL of the Docker Hub registry, which is mirrored by default
+	DockerHub = "https://registry-1.docker.io"
+
+	apiVersionHeader = "Docker-Distribution-API-Version"
+	digestHeader     = "Docker-Content-Digest"
+	// maxManifestSize is the largest manifest which is accepted from the upstream registry
+	maxManifestSize = 4 << 20
+)
+
+var (
+	validName   = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*)*$`)
+	validTag    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	validDigest = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Server is a read-only registry which serves images from a cache on disk, fetching them from an upstream registry on first use.
+// Blobs and manifests are content-addressed, so they are kept once cached. Tags are looked up upstream on every request,
+// and only resolved from the cache when the upstream registry is unavailable.
+type Server struct {
+	dir      string
+	upstream *upstream
+}
+
+// manifest is an image manifest, along with its media type
+type manifest struct {
+	digest    string
+	mediaType string
+	data      []byte
+}
+
+// NewServer returns a Server caching the images of the upstream registry within dir
+func NewServer(dir string, upstreamURL string) (*Server, error) {
+	u, err := newUpstream(upstreamURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{dir: dir, upstream: u}, nil
+}
+
+// Running returns whether a registry is serving requests at addr, such as 127.0.0.1:5050
+func Running(addr string) bool {
+	c := &http.Client{Timeout: time.Second}
+	resp, err := c.Get(fmt.Sprintf("http://%s/v2/", addr))
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK && resp.Header.Get(apiVersionHeader) != ""
+}
+
+// ServeHTTP implements the read-only subset of the registry API used to pull images
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	glog.Infof("%s %s", r.Method, r.URL.Path)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the registry mirror is read-only")
+		return
+	}
+	w.Header().Set(apiVersionHeader, "registry/2.0")
+
+	p := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case p == r.URL.Path:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "not found")
+	case p == "":
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{}")
+	case strings.Contains(p, "/manifests/"):
+		i := strings.LastIndex(p, "/manifests/")
+		s.serveManifest(w, r, p[:i], p[i+len("/manifests/"):])
+	case strings.Contains(p, "/blobs/"):
+		i := strings.LastIndex(p, "/blobs/")
+		s.serveBlob(w, r, p[:i], p[i+len("/blobs/"):])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "not found")
+	}
+}
+
+// serveManifest serves a manifest by tag or digest
+func (s *Server) serveManifest(w http.ResponseWriter, r *http.Request, name string, ref string) {
+	if !validName.MatchString(name) {
+		writeError(w, http.StatusNotFound, "NAME_INVALID", fmt.Sprintf("invalid repository name %q", name))
+		return
+	}
+	if !validDigest.MatchString(ref) && !validTag.MatchString(ref) {
+		writeError(w, http.StatusNotFound, "MANIFEST_INVALID", fmt.Sprintf("invalid reference %q", ref))
+		return
+	}
+
+	if validDigest.MatchString(ref) {
+		if m, err := s.cachedManifest(ref); err == nil {
+			writeManifest(w, r, m)
+			return
+		}
+	}
+
+	m, err := s.fetchManifest(r, name, ref)
+	if err != nil && unavailable(err) && !validDigest.MatchString(ref) {
+		cached, cerr := s.cachedTag(name, ref)
+		if cerr == nil {
+			glog.Warningf("serving cached %s:%s, as the upstream registry is unavailable: %v", name, ref, err)
+			writeManifest(w, r, cached)
+			return
+		}
+	}
+	if err != nil {
+		writeUpstreamError(w, err, "MANIFEST_UNKNOWN")
+		return
+	}
+	writeManifest(w, r, m)
+}
+
+// fetchManifest fetches a manifest from the upstream registry, and adds it to the cache
+func (s *Server) fetchManifest(r *http.Request, name string, ref string) (*manifest, error) {
+	resp, err := s.upstream.do(http.MethodGet, name, "manifests/"+ref, r.Header["Accept"])
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{resp.StatusCode}
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading manifest")
+	}
+	sum := sha256.Sum256(data)
+	m := &manifest{digest: "sha256:" + hex.EncodeToString(sum[:]), mediaType: resp.Header.Get("Content-Type"), data: data}
+	if validDigest.MatchString(ref) && m.digest != ref {
+		return nil, fmt.Errorf("manifest digest mismatch: got %s, want %s", m.digest, ref)
+	}
+
+	if err := s.storeManifest(m); err != nil {
+		glog.Warningf("unable to cache manifest %s: %v", m.digest, err)
+		return m, nil
+	}
+	if !validDigest.MatchString(ref) {
+		if err := writeFileAtomic(s.tagPath(name, ref), []byte(m.digest)); err != nil {
+			glog.Warningf("unable to cache tag %s:%s: %v", name, ref, err)
+		}
+	}
+	return m, nil
+}
+
+// cachedManifest returns a manifest from the cache
+func (s *Server) cachedManifest(digest string) (*manifest, error) {
+	data, err := ioutil.ReadFile(s.manifestPath(digest))
+	if err != nil {
+		return nil, err
+	}
+	mediaType, err := ioutil.ReadFile(s.manifestPath(digest) + ".type")
+	if err != nil {
+		return nil, err
+	}
+	return &manifest{digest: digest, mediaType: string(mediaType), data: data}, nil
+}
+
+// cachedTag returns the manifest which a tag last referred to
+func (s *Server) cachedTag(name string, tag string) (*manifest, error) {
+	digest, err := ioutil.ReadFile(s.tagPath(name, tag))
+	if err != nil {
+		return nil, err
+	}
+	return s.cachedManifest(string(digest))
+}
+
+// storeManifest adds a manifest to the cache
+func (s *Server) storeManifest(m *manifest) error {
+	if err := writeFileAtomic(s.manifestPath(m.digest)+".type", []byte(m.mediaType)); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.manifestPath(m.digest), m.data)
+}
+
+// serveBlob serves a blob from the cache, fetching it from the upstream registry if necessary
+func (s *Server) serveBlob(w http.ResponseWriter, r *http.Request, name string, digest string) {
+	if !validName.MatchString(name) {
+		writeError(w, http.StatusNotFound, "NAME_INVALID", fmt.Sprintf("invalid repository name %q", name))
+		return
+	}
+	if !validDigest.MatchString(digest) {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", fmt.Sprintf("invalid digest %q", digest))
+		return
+	}
+
+	if f, err := os.Open(s.blobPath(digest)); err == nil {
+		defer f.Close()
+		w.Header().Set(digestHeader, digest)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, "", time.Time{}, f)
+		return
+	}
+
+	resp, err := s.upstream.do(r.Method, name, "blobs/"+digest, nil)
+	if err == nil && resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		err = &statusError{resp.StatusCode}
+	}
+	if err != nil {
+		writeUpstreamError(w, err, "BLOB_UNKNOWN")
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set(digestHeader, digest)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if resp.ContentLength >= 0 {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", resp.ContentLength))
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := s.copyBlob(w, resp.Body, digest); err != nil {
+		glog.Warningf("unable to cache blob %s: %v", digest, err)
+	}
+}
+
+// copyBlob streams a blob to w, adding it to the cache if its content matches its digest
+func (s *Server) copyBlob(w io.Writer, body io.Reader, digest string) error {
+	dst := s.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(w, tmp, h), body)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != digest {
+		return fmt.Errorf("blob digest mismatch: got %s", got)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *Server) blobPath(digest string) string {
+	return filepath.Join(s.dir, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+func (s *Server) manifestPath(digest string) string {
+	return filepath.Join(s.dir, "manifests", "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+func (s *Server) tagPath(name string, tag string) string {
+	return filepath.Join(s.dir, "tags", filepath.FromSlash(name), tag)
+}
+
+// writeManifest writes a manifest, or only its headers for HEAD requests
+func writeManifest(w http.ResponseWriter, r *http.Request, m *manifest) {
+	w.Header().Set("Content-Type", m.mediaType)
+	w.Header().Set(digestHeader, m.digest)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(m.data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(m.data); err != nil {
+		glog.Warningf("writing manifest %s: %v", m.digest, err)
+	}
+}
+
+// writeError writes an error in the format of the registry API
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	type apiError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewEncoder(w).Encode(map[string][]apiError{"errors": {{Code: code, Message: message}}}); err != nil {
+		glog.Warningf("writing error: %v", err)
+	}
+}
+
+// writeUpstreamError writes an error for a request which could not be served by the upstream registry
+func writeUpstreamError(w http.ResponseWriter, err error, notFoundCode string) {
+	if se, ok := err.(*statusError); ok && !unavailable(err) {
+		code := "DENIED"
+		if se.status == http.StatusNotFound {
+			code = notFoundCode
+		}
+		writeError(w, se.status, code, err.Error())
+		return
+	}
+	writeError(w, http.StatusServiceUnavailable, "UNAVAILABLE", err.Error())
+}
+
+// writeFileAtomic writes a file within the cache, so that readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
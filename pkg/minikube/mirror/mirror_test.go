@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry is an upstream registry which requires an anonymous bearer token, and counts requests
+type fakeRegistry struct {
+	*httptest.Server
+	manifests map[string]string
+	blobs     map[string]string
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func digestOf(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	layer := "layer contents"
+	m := fmt.Sprintf(`{"schemaVersion": 2, "layers": [{"digest": %q}]}`, digestOf(layer))
+	f := &fakeRegistry{
+		manifests: map[string]string{"library/busybox:latest": m, "library/busybox@" + digestOf(m): m},
+		blobs:     map[string]string{digestOf(layer): layer, digestOf("expected"): "corrupted"},
+		requests:  map[string]int{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:library/busybox:pull" {
+				t.Errorf("unexpected token scope: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, f.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.mu.Lock()
+		f.requests[r.URL.Path]++
+		f.mu.Unlock()
+
+		p := strings.TrimPrefix(r.URL.Path, "/v2/library/busybox/")
+		var data string
+		var ok bool
+		switch {
+		case strings.HasPrefix(p, "manifests/sha256:"):
+			data, ok = f.manifests["library/busybox@"+strings.TrimPrefix(p, "manifests/")]
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+		case strings.HasPrefix(p, "manifests/"):
+			data, ok = f.manifests["library/busybox:"+strings.TrimPrefix(p, "manifests/")]
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+		case strings.HasPrefix(p, "blobs/"):
+			data, ok = f.blobs[strings.TrimPrefix(p, "blobs/")]
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, data)
+	}))
+	return f
+}
+
+func (f *fakeRegistry) count(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[path]
+}
+
+// get requests a path from the mirror, returning the status and body
+func get(t *testing.T, s *Server, path string) (int, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return w.Code, string(body)
+}
+
+func newTestServer(t *testing.T, upstream string) (*Server, func()) {
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	s, err := NewServer(dir, upstream)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	return s, func() { os.RemoveAll(dir) }
+}
+
+func TestServerCachesImages(t *testing.T) {
+	f := newFakeRegistry(t)
+	defer f.Close()
+	s, cleanup := newTestServer(t, f.URL)
+	defer cleanup()
+
+	if code, _ := get(t, s, "/v2/"); code != http.StatusOK {
+		t.Errorf("GET /v2/ = %d, want 200", code)
+	}
+
+	m := f.manifests["library/busybox:latest"]
+	layer := digestOf("layer contents")
+	for i := 0; i < 2; i++ {
+		if code, body := get(t, s, "/v2/library/busybox/manifests/latest"); code != http.StatusOK || body != m {
+			t.Errorf("GET manifest = %d %q, want 200 %q", code, body, m)
+		}
+		if code, body := get(t, s, "/v2/library/busybox/manifests/"+digestOf(m)); code != http.StatusOK || body != m {
+			t.Errorf("GET manifest by digest = %d %q, want 200 %q", code, body, m)
+		}
+		if code, body := get(t, s, "/v2/library/busybox/blobs/"+layer); code != http.StatusOK || body != "layer contents" {
+			t.Errorf("GET blob = %d %q, want 200 %q", code, body, "layer contents")
+		}
+	}
+
+	// Tags are looked up every time, while content-addressed data is only fetched once
+	if got := f.count("/v2/library/busybox/manifests/latest"); got != 2 {
+		t.Errorf("upstream tag requests = %d, want 2", got)
+	}
+	if got := f.count("/v2/library/busybox/manifests/" + digestOf(m)); got != 0 {
+		t.Errorf("upstream manifest digest requests = %d, want 0", got)
+	}
+	if got := f.count("/v2/library/busybox/blobs/" + layer); got != 1 {
+		t.Errorf("upstream blob requests = %d, want 1", got)
+	}
+
+	if code, _ := get(t, s, "/v2/library/busybox/manifests/missing"); code != http.StatusNotFound {
+		t.Errorf("GET missing manifest = %d, want 404", code)
+	}
+}
+
+func TestServerUpstreamUnavailable(t *testing.T) {
+	f := newFakeRegistry(t)
+	s, cleanup := newTestServer(t, f.URL)
+	defer cleanup()
+
+	m := f.manifests["library/busybox:latest"]
+	if code, _ := get(t, s, "/v2/library/busybox/manifests/latest"); code != http.StatusOK {
+		t.Fatalf("GET manifest = %d, want 200", code)
+	}
+	if code, _ := get(t, s, "/v2/library/busybox/blobs/"+digestOf("layer contents")); code != http.StatusOK {
+		t.Fatalf("GET blob = %d, want 200", code)
+	}
+	f.Close()
+
+	if code, body := get(t, s, "/v2/library/busybox/manifests/latest"); code != http.StatusOK || body != m {
+		t.Errorf("GET cached manifest = %d %q, want 200 %q", code, body, m)
+	}
+	if code, body := get(t, s, "/v2/library/busybox/blobs/"+digestOf("layer contents")); code != http.StatusOK || body != "layer contents" {
+		t.Errorf("GET cached blob = %d %q, want 200", code, body)
+	}
+	if code, _ := get(t, s, "/v2/library/busybox/manifests/other"); code != http.StatusServiceUnavailable {
+		t.Errorf("GET uncached manifest = %d, want 503", code)
+	}
+}
+
+func TestServerRejectsInvalidContent(t *testing.T) {
+	f := newFakeRegistry(t)
+	defer f.Close()
+	s, cleanup := newTestServer(t, f.URL)
+	defer cleanup()
+
+	digest := digestOf("expected")
+	for i := 0; i < 2; i++ {
+		get(t, s, "/v2/library/busybox/blobs/"+digest)
+	}
+	if got := f.count("/v2/library/busybox/blobs/" + digest); got != 2 {
+		t.Errorf("upstream requests for a corrupted blob = %d, want 2, as it must not be cached", got)
+	}
+	if _, err := os.Stat(s.blobPath(digest)); err == nil {
+		t.Errorf("corrupted blob was cached")
+	}
+
+	for _, path := range []string{
+		"/v2/../../etc/manifests/latest",
+		"/v2/library/busybox/manifests/../../x",
+		"/v2/library/busybox/blobs/sha256:abc",
+		"/v1/library/busybox",
+	} {
+		if code, _ := get(t, s, path); code != http.StatusNotFound && code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 404 or 400", path, code)
+		}
+	}
+}
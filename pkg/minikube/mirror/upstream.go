@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// challengeParam matches a parameter of a WWW-Authenticate challenge, such as realm="https://auth.docker.io/token"
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// statusError is returned when the upstream registry responds with an unexpected status
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("upstream registry returned %d %s", e.status, http.StatusText(e.status))
+}
+
+// unavailable returns whether an error means that the upstream registry can not currently serve requests,
+// as opposed to a definitive answer such as a missing image
+func unavailable(err error) bool {
+	se, ok := err.(*statusError)
+	if !ok {
+		return true
+	}
+	return se.status >= 500 || se.status == http.StatusTooManyRequests
+}
+
+// upstream is a client of the mirrored registry, which anonymously authenticates with bearer tokens when challenged
+type upstream struct {
+	base   *url.URL
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func newUpstream(rawURL string) (*upstream, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid upstream registry %q: the scheme must be http or https", rawURL)
+	}
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	}
+	return &upstream{base: u, client: &http.Client{Transport: t}, tokens: map[string]string{}}, nil
+}
+
+// do sends a request for a resource of a repository, such as manifests/latest, authenticating if required
+func (u *upstream) do(method string, name string, resource string, accept []string) (*http.Response, error) {
+	scope := fmt.Sprintf("repository:%s:pull", name)
+	endpoint := fmt.Sprintf("%s/v2/%s/%s", strings.TrimSuffix(u.base.String(), "/"), name, resource)
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequest(method, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accept {
+			req.Header.Add("Accept", a)
+		}
+		u.mu.Lock()
+		token := u.tokens[scope]
+		u.mu.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return u.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if err := u.authenticate(challenge, scope); err != nil {
+		return nil, errors.Wrap(err, "authenticating with the upstream registry")
+	}
+	return send()
+}
+
+// authenticate fetches an anonymous bearer token for a scope, as requested by a WWW-Authenticate challenge
+func (u *upstream) authenticate(challenge string, scope string) error {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return &statusError{http.StatusUnauthorized}
+	}
+	params := map[string]string{}
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid authentication realm in %q", challenge)
+	}
+	q := realm.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	q.Set("scope", scope)
+	realm.RawQuery = q.Encode()
+
+	resp, err := u.client.Get(realm.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{resp.StatusCode}
+	}
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return errors.Wrap(err, "decoding token")
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	u.mu.Lock()
+	u.tokens[scope] = t.Token
+	u.mu.Unlock()
+	return nil
+}
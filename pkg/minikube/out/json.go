@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package out
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/translate"
+)
+
+// Event types emitted in JSON mode
+const (
+	// StepEvent marks the beginning of a new step
+	StepEvent = "step"
+	// InfoEvent is a progress message within the current step
+	InfoEvent = "info"
+	// WarningEvent is a warning within the current step
+	WarningEvent = "warning"
+	// ErrorEvent is a failure, optionally with advice from a known problem
+	ErrorEvent = "error"
+)
+
+var (
+	// jsonMode is whether output is written as a stream of JSON events, updated by SetJSON.
+	jsonMode = false
+	// steps is the ordered list of step names, set using SetSteps()
+	steps []string
+	// currentStep is the name of the step in progress, set using SetStep()
+	currentStep string
+	// now returns the current time, and may be replaced for testing
+	now = time.Now
+)
+
+// Event is a single line of machine-readable output
+type Event struct {
+	Type      string    `json:"type"`
+	Step      string    `json:"step,omitempty"`
+	Index     int       `json:"index,omitempty"`
+	Total     int       `json:"total,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Style     string    `json:"style,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// The following fields are only set for error events
+	Error  string `json:"error,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Advice string `json:"advice,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Issues []int  `json:"issues,omitempty"`
+}
+
+// SetJSON configures whether output is written as a stream of JSON events, one per line.
+func SetJSON(enabled bool) {
+	glog.Infof("Setting JSON output to %v", enabled)
+	jsonMode = enabled
+}
+
+// JSON returns whether output is written as a stream of JSON events
+func JSON() bool {
+	return jsonMode
+}
+
+// SetSteps registers the ordered list of steps that make up a command, so that events may report progress.
+func SetSteps(names ...string) {
+	steps = names
+	currentStep = ""
+}
+
+// SetStep marks the beginning of the named step. In JSON mode, a step event is emitted.
+func SetStep(name string) {
+	currentStep = name
+	if !jsonMode {
+		glog.Infof("Step: %s", name)
+		return
+	}
+	emit(&Event{Type: StepEvent})
+}
+
+// ErrorT emits a typed error event in JSON mode. It is a no-op otherwise, as the caller
+// is expected to have displayed the error already.
+func ErrorT(e Event) {
+	if !jsonMode {
+		return
+	}
+	e.Type = ErrorEvent
+	e.Message = translate.T(e.Message)
+	emit(&e)
+}
+
+// stepIndex returns the 1-based position of a step, or 0 if it is not registered.
+func stepIndex(name string) int {
+	for i, s := range steps {
+		if s == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// message emits a templated message as an event of the given type
+func message(eventType string, style StyleEnum, format string, a ...V) {
+	msg := strings.TrimSpace(executeTemplate(translate.T(format), a...))
+	if msg == "" {
+		return
+	}
+	emit(&Event{Type: eventType, Message: msg, Style: style.String()})
+}
+
+// emit fills in the current step and writes the event as a single line of JSON to stdout
+func emit(e *Event) {
+	e.Step = currentStep
+	e.Index = stepIndex(currentStep)
+	e.Total = len(steps)
+	e.Timestamp = now()
+
+	if outFile == nil {
+		glog.Warningf("[unset outFile]: %+v", e)
+		return
+	}
+	if err := json.NewEncoder(outFile).Encode(e); err != nil {
+		glog.Errorf("Encode failed: %v", err)
+	}
+}
+
+// executeTemplate renders a message template with its parameters, returning the raw format on failure.
+func executeTemplate(format string, a ...V) string {
+	if a == nil {
+		a = []V{{}}
+	}
+	var buf bytes.Buffer
+	t, err := template.New(format).Parse(format)
+	if err != nil {
+		glog.Errorf("unable to parse %q: %v - returning raw string.", format, err)
+		return format
+	}
+	if err := t.Execute(&buf, a[0]); err != nil {
+		glog.Errorf("unable to execute %s: %v - returning raw string.", format, err)
+		return format
+	}
+	return buf.String()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package out
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestJSONEvents(t *testing.T) {
+	ts := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return ts }
+	SetJSON(true)
+	defer func() {
+		SetJSON(false)
+		SetSteps()
+		now = time.Now
+	}()
+
+	f := tests.NewFakeFile()
+	SetOutFile(f)
+	SetErrFile(f)
+
+	SetSteps("first", "second")
+	SetStep("first")
+	T(Happy, "Hello {{.name}}", V{"name": "world"})
+	ErrT(Empty, "")
+	String(" apiserver")
+	Ln("")
+	SetStep("second")
+	WarningT("careful")
+	FatalT("oh no")
+	ErrorT(Event{Message: "Failed", Error: "boom", ID: "KVM_UNAVAILABLE", Advice: "install kvm"})
+
+	want := []Event{
+		{Type: StepEvent, Step: "first", Index: 1, Total: 2, Timestamp: ts},
+		{Type: InfoEvent, Step: "first", Index: 1, Total: 2, Message: "Hello world", Style: "Happy", Timestamp: ts},
+		{Type: InfoEvent, Step: "first", Index: 1, Total: 2, Message: "apiserver", Timestamp: ts},
+		{Type: StepEvent, Step: "second", Index: 2, Total: 2, Timestamp: ts},
+		{Type: WarningEvent, Step: "second", Index: 2, Total: 2, Message: "careful", Style: "WarningType", Timestamp: ts},
+		{Type: ErrorEvent, Step: "second", Index: 2, Total: 2, Message: "oh no", Style: "FatalType", Timestamp: ts},
+		{Type: ErrorEvent, Step: "second", Index: 2, Total: 2, Message: "Failed", Timestamp: ts, Error: "boom", ID: "KVM_UNAVAILABLE", Advice: "install kvm"},
+	}
+
+	var got []Event
+	for _, line := range strings.Split(strings.TrimSpace(f.String()), "\n") {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStyleNames(t *testing.T) {
+	for s := range styles {
+		if name := s.String(); strings.HasPrefix(name, "StyleEnum(") {
+			t.Errorf("style %d has no name: run go generate", s)
+		}
+	}
+	if got := WaitingPods.String(); got != "WaitingPods" {
+		t.Errorf("WaitingPods.String() = %q, want %q", got, "WaitingPods")
+	}
+}
@@ -37,6 +37,8 @@ import (
 
 // out.SetErrFile(os.Stderr)
 // out.Fatal("Oh no, everything failed.")
+//
+// Calling out.SetJSON(true) replaces stylized output with a stream of JSON events on stdout.
 
 // NOTE: If you do not want colorized output, set MINIKUBE_IN_STYLE=false in your environment.
 
@@ -62,12 +64,22 @@ type V map[string]interface{}
 
 // T writes a stylized and templated message to stdout
 func T(style StyleEnum, format string, a ...V) {
+	if jsonMode {
+		message(InfoEvent, style, format, a...)
+		return
+	}
 	outStyled := applyTemplateFormatting(style, useColor, format, a...)
 	String(outStyled)
 }
 
-// String writes a basic formatted string to stdout
+// String writes a basic formatted string to stdout. In JSON mode, it is written as an info event instead.
 func String(format string, a ...interface{}) {
+	if jsonMode {
+		if msg := strings.TrimSpace(fmt.Sprintf(format, a...)); msg != "" {
+			emit(&Event{Type: InfoEvent, Message: msg})
+		}
+		return
+	}
 	if outFile == nil {
 		glog.Warningf("[unset outFile]: %s", fmt.Sprintf(format, a...))
 		return
@@ -85,6 +97,17 @@ func Ln(format string, a ...interface{}) {
 
 // ErrT writes a stylized and templated error message to stderr
 func ErrT(style StyleEnum, format string, a ...V) {
+	if jsonMode {
+		eventType := InfoEvent
+		switch style {
+		case WarningType:
+			eventType = WarningEvent
+		case FatalType:
+			eventType = ErrorEvent
+		}
+		message(eventType, style, format, a...)
+		return
+	}
 	errStyled := applyTemplateFormatting(style, useColor, format, a...)
 	Err(errStyled)
 }
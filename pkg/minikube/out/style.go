@@ -17,11 +17,8 @@ limitations under the License.
 package out
 
 import (
-	"bytes"
 	"strings"
-	"text/template"
 
-	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/minikube/translate"
 )
 
@@ -100,6 +97,7 @@ var styles = map[StyleEnum]style{
 	Docker:           {Prefix: "🐳  "},
 	CRIO:             {Prefix: "🎁  "}, // This should be a snow-flake, but the emoji has a strange width on macOS
 	Containerd:       {Prefix: "📦  "},
+	Podman:           {Prefix: "🎩  "},
 	Permissions:      {Prefix: "🔑  "},
 	Enabling:         {Prefix: "🔌  "},
 	Shutdown:         {Prefix: "🛑  "},
@@ -113,6 +111,8 @@ var styles = map[StyleEnum]style{
 	Unmount:          {Prefix: "🔥  "},
 	MountOptions:     {Prefix: "💾  "},
 	Fileserver:       {Prefix: "🚀  ", OmitNewline: true},
+	Pause:            {Prefix: "⏸️  "},
+	Unpause:          {Prefix: "⏯️  "},
 }
 
 // Add a prefix to a string
@@ -156,23 +156,7 @@ func applyStyle(style StyleEnum, useColor bool, format string) string {
 }
 
 func applyTemplateFormatting(style StyleEnum, useColor bool, format string, a ...V) string {
-	if a == nil {
-		a = []V{{}}
-	}
-	format = applyStyle(style, useColor, format)
-
-	var buf bytes.Buffer
-	t, err := template.New(format).Parse(format)
-	if err != nil {
-		glog.Errorf("unable to parse %q: %v - returning raw string.", format, err)
-		return format
-	}
-	err = t.Execute(&buf, a[0])
-	if err != nil {
-		glog.Errorf("unable to execute %s: %v - returning raw string.", format, err)
-		return format
-	}
-	outStyled := buf.String()
+	outStyled := executeTemplate(applyStyle(style, useColor, format), a...)
 
 	// escape any outstanding '%' signs so that they don't get interpreted
 	// as a formatting directive down the line
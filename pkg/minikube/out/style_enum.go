@@ -16,6 +16,8 @@ limitations under the License.
 
 package out
 
+//go:generate stringer -type=StyleEnum -output=styleenum_string.go
+
 // StyleEnum is an enumeration of Style
 type StyleEnum int
 
@@ -68,6 +70,7 @@ const (
 	Docker
 	CRIO
 	Containerd
+	Podman
 	Permissions
 	Enabling
 	Shutdown
@@ -83,4 +86,6 @@ const (
 	Fileserver
 	Empty
 	Workaround
+	Pause
+	Unpause
 )
@@ -75,16 +75,20 @@ func (p *Problem) Display() {
 	}
 }
 
-// FromError returns a known problem from an error on an OS
-func FromError(err error, os string) *Problem {
-	maps := []map[string]match{
+// problemMaps returns the maps of known problems, in the order they are matched
+func problemMaps() []map[string]match {
+	return []map[string]match{
 		osProblems,
 		vmProblems,
 		netProblems,
 		deployProblems,
 		stateProblems,
 	}
-	for _, m := range maps {
+}
+
+// FromError returns a known problem from an error on an OS
+func FromError(err error, os string) *Problem {
+	for _, m := range problemMaps() {
 		for k, v := range m {
 			if v.GOOS != "" && v.GOOS != os {
 				continue
@@ -103,3 +107,20 @@ func FromError(err error, os string) *Problem {
 	}
 	return nil
 }
+
+// Lookup returns the known problem with an ID, without an error, or nil if the ID is unknown.
+// It allows checks which detect a problem before it causes an error to give the same advice.
+func Lookup(id string) *Problem {
+	for _, m := range problemMaps() {
+		if v, ok := m[id]; ok {
+			return &Problem{
+				Advice:         v.Advice,
+				URL:            v.URL,
+				ID:             id,
+				Issues:         v.Issues,
+				HideCreateLink: v.HideCreateLink,
+			}
+		}
+	}
+	return nil
+}
@@ -148,3 +148,16 @@ VBoxManage.exe: error: Details: code E_FAIL (0x80004005), component MachineWrap,
 		})
 	}
 }
+
+func TestLookup(t *testing.T) {
+	p := Lookup("KVM_UNAVAILABLE")
+	if p == nil {
+		t.Fatalf("Lookup(KVM_UNAVAILABLE) = nil")
+	}
+	if p.ID != "KVM_UNAVAILABLE" || p.Advice == "" || p.Err != nil {
+		t.Errorf("Lookup(KVM_UNAVAILABLE) = %+v", p)
+	}
+	if p := Lookup("NOT_A_PROBLEM"); p != nil {
+		t.Errorf("Lookup(NOT_A_PROBLEM) = %+v, want nil", p)
+	}
+}
@@ -74,8 +74,12 @@ func (k *K8sClientGetter) GetCoreClient() (typed_core.CoreV1Interface, error) {
 
 // GetClientset returns a clientset
 func (*K8sClientGetter) GetClientset(timeout time.Duration) (*kubernetes.Clientset, error) {
+	return GetClientsetForProfile(viper.GetString(config.MachineProfile), timeout)
+}
+
+// GetClientsetForProfile returns a clientset for the cluster of a profile, which need not be the current profile
+func GetClientsetForProfile(profile string, timeout time.Duration) (*kubernetes.Clientset, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	profile := viper.GetString(config.MachineProfile)
 	configOverrides := &clientcmd.ConfigOverrides{
 		Context: clientcmdapi.Context{
 			Cluster:  profile,
@@ -327,6 +331,11 @@ func getServiceListFromServicesByLabel(services typed_core.ServiceInterface, key
 
 // CreateSecret creates or modifies secrets
 func CreateSecret(namespace, name string, dataValues map[string]string, labels map[string]string) error {
+	return CreateSecretOfType(namespace, name, core.SecretTypeOpaque, dataValues, labels)
+}
+
+// CreateSecretOfType creates or modifies secrets of a given type, such as kubernetes.io/dockerconfigjson
+func CreateSecretOfType(namespace, name string, secretType core.SecretType, dataValues map[string]string, labels map[string]string) error {
 	client, err := K8s.GetCoreClient()
 	if err != nil {
 		return &retry.RetriableError{Err: err}
@@ -355,7 +364,7 @@ func CreateSecret(namespace, name string, dataValues map[string]string, labels m
 			Labels: labels,
 		},
 		Data: data,
-		Type: core.SecretTypeOpaque,
+		Type: secretType,
 	}
 
 	_, err = secrets.Create(secretObj)
@@ -0,0 +1,255 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// ErrTunnelNotRunning is returned when there is no running tunnel to query or stop
+var ErrTunnelNotRunning = errors.New("no tunnel is running")
+
+// controlTimeout bounds the requests made to the control endpoint of a tunnel
+const controlTimeout = 2 * time.Second
+
+// ControlSocketPath returns the path of the unix socket on which the tunnel of a machine serves its status
+func ControlSocketPath(machineName string) string {
+	return filepath.Join(localpath.MiniPath(), "tunnels", machineName+".sock")
+}
+
+// StatusReport is the JSON representation of a Status, as served by the control endpoint
+type StatusReport struct {
+	MachineName               string   `json:"machineName"`
+	Pid                       int      `json:"pid"`
+	Route                     string   `json:"route"`
+	MinikubeState             string   `json:"minikubeState"`
+	MinikubeError             string   `json:"minikubeError,omitempty"`
+	RouteError                string   `json:"routeError,omitempty"`
+	PatchedServices           []string `json:"patchedServices"`
+	LoadBalancerEmulatorError string   `json:"loadBalancerEmulatorError,omitempty"`
+	// ServiceIPs are the ingress IPs assigned to the patched services, by namespace/name
+	ServiceIPs map[string]string `json:"serviceIPs,omitempty"`
+	// LoadBalancerRoute is the route to the LoadBalancer IP pool, if the tunnel allocates IPs from one
+	LoadBalancerRoute string `json:"loadBalancerRoute,omitempty"`
+}
+
+// NewStatusReport returns the JSON representation of a Status
+func NewStatusReport(s *Status) *StatusReport {
+	r := &StatusReport{
+		MachineName:               s.TunnelID.MachineName,
+		Pid:                       s.TunnelID.Pid,
+		MinikubeState:             s.MinikubeState.String(),
+		MinikubeError:             errorString(s.MinikubeError),
+		RouteError:                errorString(s.RouteError),
+		PatchedServices:           s.PatchedServices,
+		LoadBalancerEmulatorError: errorString(s.LoadBalancerEmulatorError),
+		ServiceIPs:                s.ServiceIPs,
+	}
+	if s.TunnelID.Route != nil {
+		r.Route = s.TunnelID.Route.String()
+	}
+	if s.TunnelID.LoadBalancerRoute != nil {
+		r.LoadBalancerRoute = s.TunnelID.LoadBalancerRoute.String()
+	}
+	if r.PatchedServices == nil {
+		r.PatchedServices = []string{}
+	}
+	return r
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// controlServer serves the latest status of a tunnel on a unix socket, and stops the tunnel on request.
+// It is a reporter, so that it receives each status update of the tunnel.
+type controlServer struct {
+	path     string
+	stop     func()
+	listener net.Listener
+	server   *http.Server
+
+	mu     sync.Mutex
+	status *Status
+}
+
+// newControlServer starts serving on a unix socket at path. A stale socket left behind by a
+// tunnel which was killed is replaced, but a socket which is still served is not.
+func newControlServer(path string, stop func()) (*controlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	if _, err := os.Stat(path); err == nil {
+		if c, err := net.DialTimeout("unix", path, controlTimeout); err == nil {
+			c.Close()
+			return nil, fmt.Errorf("another tunnel is already serving %s", path)
+		}
+		glog.Infof("removing stale control socket %s", path)
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "removing stale socket")
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen on %s", path)
+	}
+	s := &controlServer{path: path, stop: stop, listener: l}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/stop", s.handleStop)
+	s.server = &http.Server{Handler: mux}
+	go func() {
+		if err := s.server.Serve(l); err != nil && err != http.ErrServerClosed {
+			glog.Errorf("control endpoint %s: %v", path, err)
+		}
+	}()
+	glog.Infof("serving tunnel control endpoint on %s", path)
+	return s, nil
+}
+
+// Report records the latest status of the tunnel
+func (s *controlServer) Report(tunnelState *Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = tunnelState
+}
+
+func (s *controlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	st := s.status
+	s.mu.Unlock()
+	if st == nil {
+		http.Error(w, "tunnel is starting", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(NewStatusReport(st)); err != nil {
+		glog.Warningf("encoding tunnel status: %v", err)
+	}
+}
+
+func (s *controlServer) handleStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	glog.Infof("stop requested through the control endpoint")
+	s.stop()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Close stops serving and removes the socket
+func (s *controlServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+	err := s.server.Shutdown(ctx)
+	if rerr := os.Remove(s.path); rerr != nil && !os.IsNotExist(rerr) {
+		glog.Warningf("removing control socket: %v", rerr)
+	}
+	return err
+}
+
+// controlClient returns an HTTP client which connects to the control socket at path
+func controlClient(path string) *http.Client {
+	return &http.Client{
+		Timeout: controlTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// controlRequest makes a request to the control socket at path, returning ErrTunnelNotRunning if nothing serves it
+func controlRequest(path string, method string, endpoint string) (*http.Response, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, ErrTunnelNotRunning
+	}
+	req, err := http.NewRequest(method, "http://tunnel"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := controlClient(path).Do(req)
+	if err != nil {
+		glog.Infof("control endpoint %s unreachable: %v", path, err)
+		return nil, ErrTunnelNotRunning
+	}
+	return resp, nil
+}
+
+// readError returns the error sent by the control endpoint in an unsuccessful response
+func readError(resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("tunnel control endpoint: %s: %s", resp.Status, string(body))
+}
+
+func queryStatus(path string) (*StatusReport, error) {
+	resp, err := controlRequest(path, http.MethodGet, "/status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, readError(resp)
+	}
+	r := &StatusReport{}
+	if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+		return nil, errors.Wrap(err, "decoding tunnel status")
+	}
+	return r, nil
+}
+
+func requestStop(path string) error {
+	resp, err := controlRequest(path, http.MethodPost, "/stop")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return readError(resp)
+	}
+	return nil
+}
+
+// QueryStatus returns the status of the running tunnel of a machine, or ErrTunnelNotRunning
+func QueryStatus(machineName string) (*StatusReport, error) {
+	return queryStatus(ControlSocketPath(machineName))
+}
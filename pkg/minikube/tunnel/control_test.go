@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestControlServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tunnel")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "minikube.sock")
+
+	if _, err := queryStatus(path); err != ErrTunnelNotRunning {
+		t.Errorf("queryStatus() without a server returned %v, want ErrTunnelNotRunning", err)
+	}
+
+	stopped := make(chan bool, 1)
+	s, err := newControlServer(path, func() { stopped <- true })
+	if err != nil {
+		t.Fatalf("newControlServer: %v", err)
+	}
+
+	if _, err := newControlServer(path, func() {}); err == nil {
+		t.Errorf("newControlServer() on a served socket succeeded, want an error")
+	}
+
+	s.Report(&Status{
+		TunnelID: ID{
+			Route:       unsafeParseRoute("1.2.3.4", "10.96.0.0/12"),
+			MachineName: "testmachine",
+			Pid:         1234,
+		},
+		MinikubeState:   Running,
+		RouteError:      errors.New("route error"),
+		PatchedServices: []string{"svc1"},
+	})
+	got, err := queryStatus(path)
+	if err != nil {
+		t.Fatalf("queryStatus: %v", err)
+	}
+	want := &StatusReport{
+		MachineName:     "testmachine",
+		Pid:             1234,
+		Route:           "10.96.0.0/12 -> 1.2.3.4",
+		MinikubeState:   "Running",
+		RouteError:      "route error",
+		PatchedServices: []string{"svc1"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("status differs: (-want +got)\n%s", diff)
+	}
+
+	if err := requestStop(path); err != nil {
+		t.Fatalf("requestStop: %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Errorf("requestStop did not stop the tunnel")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket %s was not removed: %v", path, err)
+	}
+	if _, err := queryStatus(path); err != ErrTunnelNotRunning {
+		t.Errorf("queryStatus() after Close returned %v, want ErrTunnelNotRunning", err)
+	}
+}
+
+func TestControlServerReplacesStaleSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tunnel")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "minikube.sock")
+
+	if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	s, err := newControlServer(path, func() {})
+	if err != nil {
+		t.Fatalf("newControlServer: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := queryStatus(path); err == nil || err == ErrTunnelNotRunning {
+		t.Errorf("queryStatus() before the first report returned %v, want the tunnel to be starting", err)
+	}
+}
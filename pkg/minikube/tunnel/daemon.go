@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// LogPath returns the path of the file the background tunnel of a machine writes its output to
+func LogPath(machineName string) string {
+	return filepath.Join(localpath.MiniPath(), "tunnels", machineName+".log")
+}
+
+// StartBackground runs the tunnel command for a machine as a background process, and waits until
+// the tunnel serves its control endpoint. The output of the process is written to LogPath.
+func StartBackground(machineName string, command []string, timeout time.Duration) (*StatusReport, error) {
+	logPath := LogPath(machineName)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening tunnel log")
+	}
+	defer f.Close()
+
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdout = f
+	c.Stderr = f
+	detach(c)
+	glog.Infof("starting background tunnel: %v", c.Args)
+	if err := c.Start(); err != nil {
+		return nil, errors.Wrap(err, "starting tunnel process")
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- c.Wait()
+	}()
+
+	deadline := time.After(timeout)
+	for {
+		if report, err := QueryStatus(machineName); err == nil && report.Pid == c.Process.Pid {
+			return report, nil
+		}
+		select {
+		case err := <-exited:
+			return nil, fmt.Errorf("tunnel process exited (%v), see %s for details", err, logPath)
+		case <-deadline:
+			return nil, fmt.Errorf("tunnel process %d did not start within %s, see %s for details", c.Process.Pid, timeout, logPath)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// +build !windows
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach runs a command in a process group of its own, so that it does not receive the signals sent to the terminal.
+// The command keeps the controlling terminal, so that the sudo credentials cached for it can still be used.
+// Once they expire, the routes and DNS configuration of the tunnel are removed by 'minikube tunnel stop' instead.
+func detach(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,472 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typed_extensions "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
+)
+
+// hostResolver makes the host resolve domains through the DNS responder of the tunnels, implementations should cater
+// for OS specific methods
+type hostResolver interface {
+	// Listen opens the connection the DNS responder serves on, at an address the host can resolve through
+	Listen() (net.PacketConn, error)
+
+	// Link returns the part of the host configuration which resolves the domains of the route, which is shared by
+	// the routes with the same link
+	Link(route *Route) (string, error)
+
+	// Configure is an idempotent way to make the host resolve the given domains through the DNS responder at addr,
+	// for the routes with the given link
+	Configure(link string, addr net.Addr, domains []string) error
+
+	// Cleanup is an idempotent way to undo the configuration of the host for the routes with the given link
+	Cleanup(link string) error
+}
+
+const (
+	// dnsForwardTimeout is how long the DNS responder waits for an answer from the cluster DNS
+	dnsForwardTimeout = 2 * time.Second
+	// dnsBufferSize fits the UDP messages of clients which advertise a larger size than 512 bytes with EDNS
+	dnsBufferSize = 4096
+)
+
+// dnsResponder answers the DNS queries of the host for the domains of clusters, by forwarding them to the cluster
+// DNS, and for the hosts of Ingress objects, with their ingress IP. Other queries are refused, so that the resolver of
+// the host moves on to its next nameserver.
+type dnsResponder struct {
+	conn net.PacketConn
+
+	mu sync.RWMutex
+	// clusters are the clusters answered for, by the route of their tunnel
+	clusters map[string]*dnsCluster
+	// keys are the keys of clusters, in the order they were added
+	keys []string
+}
+
+// dnsCluster is a cluster a DNS responder answers for
+type dnsCluster struct {
+	domain   string
+	upstream string
+	hosts    map[string]net.IP
+}
+
+func newDNSResponder(conn net.PacketConn) *dnsResponder {
+	return &dnsResponder{
+		conn:     conn,
+		clusters: map[string]*dnsCluster{},
+	}
+}
+
+// canonicalName returns the lower case, fully qualified form of a DNS name
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+}
+
+// AddCluster makes the responder forward the queries for the domain of a cluster to the cluster DNS
+func (d *dnsResponder) AddCluster(key string, domain string, upstream net.IP) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.clusters[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+	d.clusters[key] = &dnsCluster{
+		domain:   canonicalName(domain),
+		upstream: net.JoinHostPort(upstream.String(), "53"),
+	}
+}
+
+// RemoveCluster stops answering for a cluster
+func (d *dnsResponder) RemoveCluster(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.clusters, key)
+	for i, k := range d.keys {
+		if k == key {
+			d.keys = append(d.keys[:i], d.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys of the clusters the responder answers for, in the order they were added
+func (d *dnsResponder) Keys() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]string{}, d.keys...)
+}
+
+// SetHosts sets the hosts the responder answers for within a cluster, along with their IPs
+func (d *dnsResponder) SetHosts(key string, hosts map[string]net.IP) {
+	canonical := map[string]net.IP{}
+	for host, ip := range hosts {
+		canonical[canonicalName(host)] = ip
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.clusters[key]; ok {
+		c.hosts = canonical
+	}
+}
+
+// Domains returns the domains the responder answers for within a cluster
+func (d *dnsResponder) Domains(key string) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	c, ok := d.clusters[key]
+	if !ok {
+		return nil
+	}
+	domains := []string{strings.TrimSuffix(c.domain, ".")}
+	for host := range c.hosts {
+		domains = append(domains, strings.TrimSuffix(host, "."))
+	}
+	sort.Strings(domains[1:])
+	return domains
+}
+
+// lookup returns the cluster DNS to forward a query for name to, or otherwise the IP of the Ingress host name.
+// Clusters are looked up in the order they were added, so that the first one answers for a domain several of them use.
+func (d *dnsResponder) lookup(name string) (string, net.IP, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, key := range d.keys {
+		domain := d.clusters[key].domain
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return d.clusters[key].upstream, nil, false
+		}
+	}
+	for _, key := range d.keys {
+		if ip, ok := d.clusters[key].hosts[name]; ok {
+			return "", ip, true
+		}
+	}
+	return "", nil, false
+}
+
+// Serve answers queries until the connection of the responder is closed
+func (d *dnsResponder) Serve() {
+	buf := make([]byte, dnsBufferSize)
+	for {
+		n, addr, err := d.conn.ReadFrom(buf)
+		if err != nil {
+			glog.V(3).Infof("DNS responder stopped: %v", err)
+			return
+		}
+		query := make([]byte, n)
+		copy(query, buf[:n])
+		// forwarding can take a while, so that each query is answered on its own
+		go func() {
+			response, err := d.respond(query)
+			if err != nil {
+				glog.V(3).Infof("unable to answer DNS query from %s: %v", addr, err)
+				return
+			}
+			if _, err := d.conn.WriteTo(response, addr); err != nil {
+				glog.V(3).Infof("unable to send DNS response to %s: %v", addr, err)
+			}
+		}()
+	}
+}
+
+// Close stops the responder
+func (d *dnsResponder) Close() error {
+	return d.conn.Close()
+}
+
+func (d *dnsResponder) respond(query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing header")
+	}
+	question, err := p.Question()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing question")
+	}
+	upstream, ip, ok := d.lookup(strings.ToLower(question.Name.String()))
+	if upstream != "" {
+		return forwardDNS(upstream, query)
+	}
+
+	response := dnsmessage.Header{
+		ID:               header.ID,
+		Response:         true,
+		OpCode:           header.OpCode,
+		RecursionDesired: header.RecursionDesired,
+		RCode:            dnsmessage.RCodeRefused,
+	}
+	if ok {
+		response.Authoritative = true
+		response.RCode = dnsmessage.RCodeSuccess
+	}
+	b := dnsmessage.NewBuilder(nil, response)
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(question); err != nil {
+		return nil, err
+	}
+	// hosts only have an A record, queries for other types get an empty answer
+	if ip4 := ip.To4(); ok && ip4 != nil && (question.Type == dnsmessage.TypeA || question.Type == dnsmessage.TypeALL) {
+		if err := b.StartAnswers(); err != nil {
+			return nil, err
+		}
+		rh := dnsmessage.ResourceHeader{
+			Name:  question.Name,
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+			TTL:   5,
+		}
+		var a dnsmessage.AResource
+		copy(a.A[:], ip4)
+		if err := b.AResource(rh, a); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+// forwardDNS sends the query to the cluster DNS at upstream, and returns its response
+func forwardDNS(upstream string, query []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("udp", upstream, dnsForwardTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to cluster DNS")
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(dnsForwardTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, errors.Wrap(err, "forwarding query to cluster DNS")
+	}
+	buf := make([]byte, dnsBufferSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response from cluster DNS")
+	}
+	return buf[:n], nil
+}
+
+// ingressHosts returns the hosts declared by the rules of Ingress objects, along with their ingress IP. Ingress
+// objects without an ingress IP yet are served by the ingress controller on the node, so their hosts get nodeIP.
+// Wildcard hosts are left out.
+func ingressHosts(ingresses typed_extensions.IngressesGetter, nodeIP net.IP) (map[string]net.IP, error) {
+	list, err := ingresses.Ingresses(meta.NamespaceAll).List(meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing ingresses")
+	}
+	hosts := map[string]net.IP{}
+	for _, ing := range list.Items {
+		ip := nodeIP
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if parsed := net.ParseIP(lb.IP); parsed != nil {
+				ip = parsed
+				break
+			}
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host == "" || strings.HasPrefix(rule.Host, "*") {
+				continue
+			}
+			hosts[rule.Host] = ip
+		}
+	}
+	return hosts, nil
+}
+
+// dnsServer runs the DNS responder shared by the tunnels of a Manager, and configures the host to resolve the domains
+// of their clusters through it. The tunnels can not each run a responder, as the host resolves through a single
+// address, and the routes of several tunnels can go through the same link.
+type dnsServer struct {
+	resolver hostResolver
+
+	mu        sync.Mutex
+	responder *dnsResponder
+	// links are the links of the routes of the tunnels using the responder, by route
+	links map[string]string
+	// configured are the domains the host was last configured to resolve through the responder, by link
+	configured map[string][]string
+}
+
+func newDNSServer(resolver hostResolver) *dnsServer {
+	if resolver == nil {
+		return nil
+	}
+	return &dnsServer{
+		resolver:   resolver,
+		links:      map[string]string{},
+		configured: map[string][]string{},
+	}
+}
+
+// add starts answering for the cluster of a route, starting the responder along with the first one
+func (s *dnsServer) add(route *Route) error {
+	link, err := s.resolver.Link(route)
+	if err != nil {
+		return errors.Wrap(err, "getting the link of the route")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.responder == nil {
+		conn, err := s.resolver.Listen()
+		if err != nil {
+			return errors.Wrap(err, "listening for DNS queries")
+		}
+		s.responder = newDNSResponder(conn)
+		go s.responder.Serve()
+	}
+	s.responder.AddCluster(route.String(), route.ClusterDomain, route.ClusterDNSIP)
+	s.links[route.String()] = link
+	return nil
+}
+
+// setHosts sets the Ingress hosts of the cluster of a route, and reconfigures the host if the domains resolved
+// through its link changed
+func (s *dnsServer) setHosts(route *Route, hosts map[string]net.IP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[route.String()]
+	if !ok {
+		return fmt.Errorf("no DNS for route %s", route)
+	}
+	s.responder.SetHosts(route.String(), hosts)
+	return s.configure(link)
+}
+
+// configure makes the host resolve the domains of the clusters of all the routes through link, if they changed
+func (s *dnsServer) configure(link string) error {
+	var domains []string
+	seen := map[string]bool{}
+	for _, key := range s.responder.Keys() {
+		if s.links[key] != link {
+			continue
+		}
+		for _, domain := range s.responder.Domains(key) {
+			if !seen[domain] {
+				seen[domain] = true
+				domains = append(domains, domain)
+			}
+		}
+	}
+	if current, ok := s.configured[link]; ok && strings.Join(domains, " ") == strings.Join(current, " ") {
+		return nil
+	}
+	glog.Infof("resolving %v through the tunnel DNS responder at %s", domains, s.responder.conn.LocalAddr())
+	if err := s.resolver.Configure(link, s.responder.conn.LocalAddr(), domains); err != nil {
+		return errors.Wrap(err, "configuring host DNS")
+	}
+	s.configured[link] = domains
+	return nil
+}
+
+// linkUsed returns whether the route of a tunnel using the responder goes through link
+func (s *dnsServer) linkUsed(link string) bool {
+	for _, l := range s.links {
+		if l == link {
+			return true
+		}
+	}
+	return false
+}
+
+// remove stops answering for the cluster of a route. The configuration of the host is undone once no other route
+// goes through its link, and the responder is stopped along with the last route.
+func (s *dnsServer) remove(route *Route) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[route.String()]
+	if !ok {
+		return nil
+	}
+	delete(s.links, route.String())
+	s.responder.RemoveCluster(route.String())
+
+	var err error
+	if s.linkUsed(link) {
+		err = s.configure(link)
+	} else {
+		delete(s.configured, link)
+		err = s.resolver.Cleanup(link)
+	}
+	if len(s.links) == 0 {
+		if cerr := s.responder.Close(); cerr != nil {
+			glog.Warningf("closing DNS responder: %v", cerr)
+		}
+		s.responder = nil
+	}
+	return err
+}
+
+// cleanupStale undoes the configuration of the host for the route of a tunnel which is no longer running, unless
+// the route of a running tunnel goes through the same link
+func (s *dnsServer) cleanupStale(route *Route) error {
+	link, err := s.resolver.Link(route)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.linkUsed(link) {
+		return nil
+	}
+	return s.resolver.Cleanup(link)
+}
+
+// tunnelDNS keeps the DNS of a tunnel up to date with the Ingress objects of its cluster
+type tunnelDNS struct {
+	route     *Route
+	server    *dnsServer
+	ingresses typed_extensions.IngressesGetter
+}
+
+func newTunnelDNS(route *Route, server *dnsServer, ingresses typed_extensions.IngressesGetter) (*tunnelDNS, error) {
+	if err := server.add(route); err != nil {
+		return nil, err
+	}
+	return &tunnelDNS{
+		route:     route,
+		server:    server,
+		ingresses: ingresses,
+	}, nil
+}
+
+// update refreshes the hosts of the responder, and reconfigures the host if they changed
+func (d *tunnelDNS) update() error {
+	hosts, err := ingressHosts(d.ingresses, d.route.Gateway)
+	if err != nil {
+		return err
+	}
+	return d.server.setHosts(d.route, hosts)
+}
+
+// cleanup stops answering for the cluster, and undoes the configuration of the host unless other tunnels use it
+func (d *tunnelDNS) cleanup() error {
+	return d.server.remove(d.route)
+}
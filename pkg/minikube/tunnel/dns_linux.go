@@ -0,0 +1,202 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dnsListenIP is the loopback address the DNS responder listens on, which is distinct from those of the
+	// resolvers commonly running on the host, such as systemd-resolved on 127.0.0.53 and dnsmasq on 127.0.0.1
+	dnsListenIP = "127.0.0.153"
+
+	resolvConf = "/etc/resolv.conf"
+	// resolvConfMarker marks the lines of /etc/resolv.conf added by the tunnel
+	resolvConfMarker = "# added by minikube tunnel"
+)
+
+// osResolver configures systemd-resolved to resolve the domains through the DNS responder, on the link the route
+// goes through. Without systemd-resolved, the responder is added as the first nameserver of /etc/resolv.conf, which
+// requires it to listen on port 53, and which is then the link of every route.
+type osResolver struct {
+	resolved bool
+}
+
+func newHostResolver() hostResolver {
+	resolved := false
+	if _, err := exec.LookPath("resolvectl"); err == nil {
+		resolved = exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run() == nil
+	}
+	glog.Infof("host resolves through systemd-resolved: %t", resolved)
+	return &osResolver{resolved: resolved}
+}
+
+func (r *osResolver) Listen() (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp4", net.JoinHostPort(dnsListenIP, "53"))
+	if err == nil {
+		return conn, nil
+	}
+	if !r.resolved {
+		return nil, errors.Wrapf(err, "nameservers of %s must listen on port 53", resolvConf)
+	}
+	glog.Infof("unable to listen on port 53, listening on another port: %v", err)
+	return net.ListenPacket("udp4", net.JoinHostPort(dnsListenIP, "0"))
+}
+
+func (r *osResolver) Link(route *Route) (string, error) {
+	if !r.resolved {
+		return resolvConf, nil
+	}
+	return routeLink(route)
+}
+
+func (r *osResolver) Configure(link string, addr net.Addr, domains []string) error {
+	if !r.resolved {
+		if udpAddr, ok := addr.(*net.UDPAddr); !ok || udpAddr.Port != 53 {
+			return fmt.Errorf("nameservers of %s must listen on port 53, not on %s", resolvConf, addr)
+		}
+		return updateResolvConf(func(content string) string {
+			return resolvConfWithNameserver(content, dnsListenIP)
+		})
+	}
+
+	if err := runResolvectl("dns", link, addr.String()); err != nil {
+		return err
+	}
+	// routing-only domains, so that the link is not used for other domains
+	args := []string{"domain", link}
+	for _, domain := range domains {
+		args = append(args, "~"+domain)
+	}
+	return runResolvectl(args...)
+}
+
+func (r *osResolver) Cleanup(link string) error {
+	if !r.resolved {
+		return updateResolvConf(resolvConfWithoutNameserver)
+	}
+
+	// once the cluster is gone, the route may go through another link, which is left alone
+	command := exec.Command("resolvectl", "dns", link)
+	out, err := command.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("running %v: %s, %v", command.Args, out, err)
+	}
+	if !strings.Contains(string(out), dnsListenIP) {
+		glog.V(3).Infof("DNS of link %s is not configured by the tunnel: %s", link, out)
+		return nil
+	}
+	return runResolvectl("revert", link)
+}
+
+func runResolvectl(args ...string) error {
+	command := exec.Command("sudo", append([]string{"resolvectl"}, args...)...)
+	glog.Infof("About to run command: %s", command.Args)
+	out, err := command.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("running %v: %s, %v", command.Args, out, err)
+	}
+	return nil
+}
+
+// routeLink returns the name of the link the route goes through
+func routeLink(route *Route) (string, error) {
+	command := exec.Command("ip", "route", "get", route.Gateway.String())
+	command.Env = append(command.Env, "LC_ALL=C")
+	out, err := command.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("running %v: %s, %v", command.Args, out, err)
+	}
+	return parseRouteLink(string(out))
+}
+
+// parseRouteLink parses the link from the output of 'ip route get', such as
+// "192.168.39.47 dev virbr1 src 192.168.39.1 uid 1000"
+func parseRouteLink(out string) (string, error) {
+	fields := strings.Fields(out)
+	for i := 0; i < len(fields)-1; i++ {
+		if fields[i] == "dev" {
+			return fields[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no link in route: %q", out)
+}
+
+// resolvConfWithNameserver returns the content of /etc/resolv.conf with the nameserver added before the others
+func resolvConfWithNameserver(content string, ip string) string {
+	lines := strings.Split(resolvConfWithoutNameserver(content), "\n")
+	nameserver := fmt.Sprintf("nameserver %s %s", ip, resolvConfMarker)
+	i := 0
+	for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "nameserver") {
+		i++
+	}
+	if i == len(lines) && lines[i-1] == "" {
+		i--
+	}
+	lines = append(lines[:i], append([]string{nameserver}, lines[i:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// resolvConfWithoutNameserver returns the content of /etc/resolv.conf without the lines added by the tunnel
+func resolvConfWithoutNameserver(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasSuffix(line, resolvConfMarker) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// updateResolvConf rewrites /etc/resolv.conf with the content returned by update, if it differs
+func updateResolvConf(update func(content string) string) error {
+	b, err := ioutil.ReadFile(resolvConf)
+	if err != nil {
+		return err
+	}
+	content := update(string(b))
+	if content == string(b) {
+		return nil
+	}
+	// write the content into tmpFile, then copy it over /etc/resolv.conf
+	tmpFile, err := ioutil.TempFile("", "minikube-tunnel-resolv-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.WriteString(content); err != nil {
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	command := exec.Command("sudo", "cp", "-f", tmpFile.Name(), resolvConf)
+	glog.Infof("About to run command: %s", command.Args)
+	if out, err := command.CombinedOutput(); err != nil {
+		return fmt.Errorf("running %v: %s, %v", command.Args, out, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"testing"
+)
+
+func TestParseRouteLink(t *testing.T) {
+	link, err := parseRouteLink("192.168.39.47 dev virbr1 src 192.168.39.1 uid 1000 \n    cache \n")
+	if err != nil {
+		t.Fatalf("parseRouteLink: %v", err)
+	}
+	if link != "virbr1" {
+		t.Errorf("parseRouteLink() = %q, want virbr1", link)
+	}
+
+	if _, err := parseRouteLink("RTNETLINK answers: Network is unreachable"); err == nil {
+		t.Errorf("parseRouteLink() without a link succeeded, want an error")
+	}
+}
+
+func TestResolvConfNameserver(t *testing.T) {
+	var tcs = []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "nameservers",
+			content: "search example.com\nnameserver 192.168.1.1\nnameserver 8.8.8.8\n",
+			want:    "search example.com\nnameserver 127.0.0.153 # added by minikube tunnel\nnameserver 192.168.1.1\nnameserver 8.8.8.8\n",
+		},
+		{
+			name:    "no nameservers",
+			content: "search example.com\n",
+			want:    "search example.com\nnameserver 127.0.0.153 # added by minikube tunnel\n",
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    "nameserver 127.0.0.153 # added by minikube tunnel\n",
+		},
+		{
+			name:    "already added",
+			content: "nameserver 127.0.0.153 # added by minikube tunnel\nnameserver 192.168.1.1\n",
+			want:    "nameserver 127.0.0.153 # added by minikube tunnel\nnameserver 192.168.1.1\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resolvConfWithNameserver(tc.content, dnsListenIP)
+			if got != tc.want {
+				t.Errorf("resolvConfWithNameserver() = %q, want %q", got, tc.want)
+			}
+			if removed := resolvConfWithoutNameserver(got); removed != resolvConfWithoutNameserver(tc.content) {
+				t.Errorf("resolvConfWithoutNameserver() = %q, want %q", removed, resolvConfWithoutNameserver(tc.content))
+			}
+		})
+	}
+}
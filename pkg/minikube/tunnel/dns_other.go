@@ -0,0 +1,25 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +build !linux
+
+package tunnel
+
+// newHostResolver returns nil, as the tunnel only runs a DNS responder on Linux. On macOS, the cluster domain is
+// resolved through the resolver file written along with the route.
+func newHostResolver() hostResolver {
+	return nil
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+	core "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typed_extensions "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
+	fake_extensions "k8s.io/client-go/kubernetes/typed/extensions/v1beta1/fake"
+)
+
+type stubExtensionsClient struct {
+	fake_extensions.FakeExtensionsV1beta1
+	ingressList *extensions.IngressList
+}
+
+func (c *stubExtensionsClient) Ingresses(namespace string) typed_extensions.IngressInterface {
+	return &stubIngresses{
+		fake_extensions.FakeIngresses{Fake: &c.FakeExtensionsV1beta1},
+		c.ingressList,
+	}
+}
+
+type stubIngresses struct {
+	fake_extensions.FakeIngresses
+	ingressList *extensions.IngressList
+}
+
+func (s *stubIngresses) List(opts meta.ListOptions) (*extensions.IngressList, error) {
+	return s.ingressList, nil
+}
+
+func newIngress(ip string, hosts ...string) extensions.Ingress {
+	ing := extensions.Ingress{}
+	for _, host := range hosts {
+		ing.Spec.Rules = append(ing.Spec.Rules, extensions.IngressRule{Host: host})
+	}
+	if ip != "" {
+		ing.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ip}}
+	}
+	return ing
+}
+
+type recordingResolver struct {
+	conn net.PacketConn
+	// links are the links of the routes, by gateway
+	links      map[string]string
+	listened   int
+	configured []string
+	cleanedUp  []string
+}
+
+func (r *recordingResolver) Listen() (net.PacketConn, error) {
+	r.listened++
+	return r.conn, nil
+}
+
+func (r *recordingResolver) Link(route *Route) (string, error) {
+	return r.links[route.Gateway.String()], nil
+}
+
+func (r *recordingResolver) Configure(link string, addr net.Addr, domains []string) error {
+	r.configured = append(r.configured, link+": "+strings.Join(domains, " "))
+	return nil
+}
+
+func (r *recordingResolver) Cleanup(link string) error {
+	r.cleanedUp = append(r.cleanedUp, link)
+	return nil
+}
+
+func dnsQuery(t *testing.T, name string, qtype dnsmessage.Type) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 42, RecursionDesired: true})
+	if err := b.StartQuestions(); err != nil {
+		t.Fatalf("StartQuestions: %v", err)
+	}
+	if err := b.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName(name),
+		Type:  qtype,
+		Class: dnsmessage.ClassINET,
+	}); err != nil {
+		t.Fatalf("Question: %v", err)
+	}
+	query, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	return query
+}
+
+// parseDNSResponse returns the response code and the IPs of the A records of a response
+func parseDNSResponse(t *testing.T, response []byte) (dnsmessage.RCode, []string) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(response); err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	if msg.ID != 42 || !msg.Response {
+		t.Errorf("response header = %+v, want a response to query 42", msg.Header)
+	}
+	var ips []string
+	for _, answer := range msg.Answers {
+		if a, ok := answer.Body.(*dnsmessage.AResource); ok {
+			ips = append(ips, net.IP(a.A[:]).String())
+		}
+	}
+	return msg.RCode, ips
+}
+
+func TestDNSResponder(t *testing.T) {
+	d := newDNSResponder(nil)
+	d.AddCluster("minikube", "cluster.local", net.ParseIP("10.96.0.10"))
+	d.SetHosts("minikube", map[string]net.IP{
+		"Foo.Example.com": net.ParseIP("192.168.39.47"),
+	})
+
+	if got, want := d.Domains("minikube"), []string{"cluster.local", "foo.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Domains() = %v, want %v", got, want)
+	}
+
+	var tcs = []struct {
+		name  string
+		qtype dnsmessage.Type
+		rcode dnsmessage.RCode
+		ips   []string
+	}{
+		{
+			name:  "foo.example.com.",
+			qtype: dnsmessage.TypeA,
+			rcode: dnsmessage.RCodeSuccess,
+			ips:   []string{"192.168.39.47"},
+		},
+		{
+			name:  "FOO.example.com.",
+			qtype: dnsmessage.TypeA,
+			rcode: dnsmessage.RCodeSuccess,
+			ips:   []string{"192.168.39.47"},
+		},
+		{
+			name:  "foo.example.com.",
+			qtype: dnsmessage.TypeAAAA,
+			rcode: dnsmessage.RCodeSuccess,
+		},
+		{
+			name:  "bar.example.com.",
+			qtype: dnsmessage.TypeA,
+			rcode: dnsmessage.RCodeRefused,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name+tc.qtype.String(), func(t *testing.T) {
+			response, err := d.respond(dnsQuery(t, tc.name, tc.qtype))
+			if err != nil {
+				t.Fatalf("respond: %v", err)
+			}
+			rcode, ips := parseDNSResponse(t, response)
+			if rcode != tc.rcode {
+				t.Errorf("response code = %v, want %v", rcode, tc.rcode)
+			}
+			if !reflect.DeepEqual(ips, tc.ips) {
+				t.Errorf("response IPs = %v, want %v", ips, tc.ips)
+			}
+		})
+	}
+}
+
+func TestDNSResponderForwardsClusterDomain(t *testing.T) {
+	upstream, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer upstream.Close()
+	// the cluster DNS echoes the query back
+	go func() {
+		buf := make([]byte, dnsBufferSize)
+		n, addr, err := upstream.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		upstream.WriteTo(buf[:n], addr)
+	}()
+
+	d := newDNSResponder(nil)
+	d.AddCluster("minikube", "cluster.local", net.ParseIP("127.0.0.1"))
+	d.clusters["minikube"].upstream = upstream.LocalAddr().String()
+
+	query := dnsQuery(t, "kubernetes.default.svc.cluster.local.", dnsmessage.TypeA)
+	response, err := d.respond(query)
+	if err != nil {
+		t.Fatalf("respond: %v", err)
+	}
+	if !reflect.DeepEqual(response, query) {
+		t.Errorf("respond() = %v, want the response of the cluster DNS %v", response, query)
+	}
+}
+
+func TestIngressHosts(t *testing.T) {
+	client := &stubExtensionsClient{
+		ingressList: &extensions.IngressList{
+			Items: []extensions.Ingress{
+				newIngress("", "foo.example.com", ""),
+				newIngress("10.0.0.1", "bar.example.com", "*.example.com"),
+			},
+		},
+	}
+
+	hosts, err := ingressHosts(client, net.ParseIP("192.168.39.47"))
+	if err != nil {
+		t.Fatalf("ingressHosts: %v", err)
+	}
+	want := map[string]net.IP{
+		"foo.example.com": net.ParseIP("192.168.39.47"),
+		"bar.example.com": net.ParseIP("10.0.0.1"),
+	}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("ingressHosts() = %v, want %v", hosts, want)
+	}
+}
+
+func TestTunnelDNSReconfiguresOnChange(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	resolver := &recordingResolver{conn: conn, links: map[string]string{"192.168.39.47": "virbr1"}}
+	client := &stubExtensionsClient{
+		ingressList: &extensions.IngressList{},
+	}
+	route := unsafeParseRoute("192.168.39.47", "10.96.0.0/12")
+	route.ClusterDomain = "cluster.local"
+	route.ClusterDNSIP = net.ParseIP("10.96.0.10")
+
+	d, err := newTunnelDNS(route, newDNSServer(resolver), client)
+	if err != nil {
+		t.Fatalf("newTunnelDNS: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := d.update(); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+	client.ingressList.Items = []extensions.Ingress{newIngress("", "foo.example.com")}
+	if err := d.update(); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	want := []string{
+		"virbr1: cluster.local",
+		"virbr1: cluster.local foo.example.com",
+	}
+	if !reflect.DeepEqual(resolver.configured, want) {
+		t.Errorf("configured domains = %v, want %v", resolver.configured, want)
+	}
+
+	if err := d.cleanup(); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if !reflect.DeepEqual(resolver.cleanedUp, []string{"virbr1"}) {
+		t.Errorf("cleaned up links = %v, want [virbr1]", resolver.cleanedUp)
+	}
+}
+
+func TestTunnelDNSSharedByProfiles(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	// both routes go through the same link, as for two profiles on the same libvirt network
+	resolver := &recordingResolver{conn: conn, links: map[string]string{"192.168.39.47": "virbr1", "192.168.39.48": "virbr1"}}
+	server := newDNSServer(resolver)
+
+	first := unsafeParseRoute("192.168.39.47", "10.96.0.0/12")
+	first.ClusterDomain = "cluster.local"
+	first.ClusterDNSIP = net.ParseIP("10.96.0.10")
+	second := unsafeParseRoute("192.168.39.48", "10.112.0.0/12")
+	second.ClusterDomain = "second.local"
+	second.ClusterDNSIP = net.ParseIP("10.112.0.10")
+
+	d1, err := newTunnelDNS(first, server, &stubExtensionsClient{ingressList: &extensions.IngressList{}})
+	if err != nil {
+		t.Fatalf("newTunnelDNS: %v", err)
+	}
+	client := &stubExtensionsClient{
+		ingressList: &extensions.IngressList{Items: []extensions.Ingress{newIngress("", "bar.example.com")}},
+	}
+	d2, err := newTunnelDNS(second, server, client)
+	if err != nil {
+		t.Fatalf("newTunnelDNS: %v", err)
+	}
+	if resolver.listened != 1 {
+		t.Errorf("listened %d times, want the tunnels to share a single responder", resolver.listened)
+	}
+	for _, d := range []*tunnelDNS{d1, d2} {
+		if err := d.update(); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+
+	// the first cleanup keeps the host resolving the domains of the second cluster
+	if err := d1.cleanup(); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if len(resolver.cleanedUp) != 0 {
+		t.Errorf("cleaned up links %v while the second tunnel uses them", resolver.cleanedUp)
+	}
+	response, err := server.responder.respond(dnsQuery(t, "bar.example.com.", dnsmessage.TypeA))
+	if err != nil {
+		t.Fatalf("respond: %v", err)
+	}
+	if rcode, ips := parseDNSResponse(t, response); rcode != dnsmessage.RCodeSuccess || !reflect.DeepEqual(ips, []string{"192.168.39.48"}) {
+		t.Errorf("response after the first cleanup = %v %v, want the IP of the second cluster", rcode, ips)
+	}
+
+	if err := d2.cleanup(); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	want := []string{
+		"virbr1: cluster.local second.local",
+		"virbr1: cluster.local second.local bar.example.com",
+		"virbr1: second.local bar.example.com",
+	}
+	if !reflect.DeepEqual(resolver.configured, want) {
+		t.Errorf("configured domains = %v, want %v", resolver.configured, want)
+	}
+	if !reflect.DeepEqual(resolver.cleanedUp, []string{"virbr1"}) {
+		t.Errorf("cleaned up links = %v, want [virbr1]", resolver.cleanedUp)
+	}
+	if server.responder != nil {
+		t.Errorf("responder still running after the last cleanup")
+	}
+}
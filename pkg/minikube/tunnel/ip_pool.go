@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/golang/glog"
+)
+
+// ipPool allocates a stable LoadBalancer IP from a CIDR to each service
+type ipPool struct {
+	cidr *net.IPNet
+	// allocated are the IPs allocated to services, by namespace/name
+	allocated map[string]string
+	// save persists the allocations whenever they change
+	save func(allocated map[string]string) error
+}
+
+// newIPPool returns a pool of the IPv4 addresses in cidr, starting with previous allocations
+func newIPPool(cidr *net.IPNet, allocated map[string]string, save func(map[string]string) error) (*ipPool, error) {
+	if cidr.IP.To4() == nil {
+		return nil, fmt.Errorf("LoadBalancer IP pool %s is not an IPv4 CIDR", cidr)
+	}
+	p := &ipPool{cidr: cidr, allocated: map[string]string{}, save: save}
+	for svc, ip := range allocated {
+		if parsed := net.ParseIP(ip); parsed != nil && cidr.Contains(parsed) {
+			p.allocated[svc] = ip
+		} else {
+			glog.Warningf("dropping allocation of %s to %s, which is outside of the pool %s", ip, svc, cidr)
+		}
+	}
+	return p, nil
+}
+
+// addresses returns the first and last address which can be allocated, skipping the network and broadcast
+// addresses of pools which have them
+func (p *ipPool) addresses() (first uint32, last uint32) {
+	ones, bits := p.cidr.Mask.Size()
+	first = binary.BigEndian.Uint32(p.cidr.IP.To4())
+	last = first | ^binary.BigEndian.Uint32(net.IP(p.cidr.Mask).To4())
+	if bits-ones > 1 {
+		first++
+		last--
+	}
+	return first, last
+}
+
+// allocate returns the IP allocated to a service, allocating one if there is none yet.
+// The preferred IP, such as the current ingress IP of the service, is used if it is free.
+func (p *ipPool) allocate(svc string, preferred string) (string, error) {
+	if ip, ok := p.allocated[svc]; ok {
+		return ip, nil
+	}
+	used := map[string]bool{}
+	for _, ip := range p.allocated {
+		used[ip] = true
+	}
+
+	ip := ""
+	if parsed := net.ParseIP(preferred); parsed != nil && p.cidr.Contains(parsed) && !used[preferred] {
+		ip = preferred
+	} else {
+		first, last := p.addresses()
+		for i := uint64(first); i <= uint64(last); i++ {
+			candidate := make(net.IP, 4)
+			binary.BigEndian.PutUint32(candidate, uint32(i))
+			if !used[candidate.String()] {
+				ip = candidate.String()
+				break
+			}
+		}
+	}
+	if ip == "" {
+		return "", fmt.Errorf("LoadBalancer IP pool %s is exhausted", p.cidr)
+	}
+
+	glog.Infof("allocated %s from %s to %s", ip, p.cidr, svc)
+	p.allocated[svc] = ip
+	return ip, p.save(p.allocated)
+}
+
+// release frees the IPs allocated to services which are not in use anymore
+func (p *ipPool) release(inUse map[string]bool) error {
+	var released []string
+	for svc := range p.allocated {
+		if !inUse[svc] {
+			released = append(released, svc)
+		}
+	}
+	if len(released) == 0 {
+		return nil
+	}
+	sort.Strings(released)
+	for _, svc := range released {
+		glog.Infof("releasing %s allocated to %s", p.allocated[svc], svc)
+		delete(p.allocated, svc)
+	}
+	return p.save(p.allocated)
+}
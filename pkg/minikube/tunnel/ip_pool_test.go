@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%s): %v", cidr, err)
+	}
+	return ipNet
+}
+
+func TestIPPoolAllocate(t *testing.T) {
+	var saved map[string]string
+	save := func(ips map[string]string) error {
+		saved = map[string]string{}
+		for k, v := range ips {
+			saved[k] = v
+		}
+		return nil
+	}
+	previous := map[string]string{"ns/kept": "10.200.0.1", "ns/outside": "10.100.0.1"}
+	pool, err := newIPPool(mustParseCIDR(t, "10.200.0.0/30"), previous, save)
+	if err != nil {
+		t.Fatalf("newIPPool: %v", err)
+	}
+
+	allocations := []struct {
+		svc       string
+		preferred string
+		want      string
+	}{
+		{svc: "ns/kept", want: "10.200.0.1"},
+		{svc: "ns/new", preferred: "10.96.0.10", want: "10.200.0.2"},
+		{svc: "ns/new", want: "10.200.0.2"},
+	}
+	for _, a := range allocations {
+		got, err := pool.allocate(a.svc, a.preferred)
+		if err != nil || got != a.want {
+			t.Errorf("allocate(%s, %q) = %s, %v, want %s", a.svc, a.preferred, got, err, a.want)
+		}
+	}
+	if _, err := pool.allocate("ns/third", ""); err == nil {
+		t.Errorf("allocate() from an exhausted pool succeeded")
+	}
+
+	if err := pool.release(map[string]bool{"ns/new": true}); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	want := map[string]string{"ns/new": "10.200.0.2"}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved allocations = %v, want %v", saved, want)
+	}
+
+	// the current ingress IP of a service is kept if it is free
+	if got, err := pool.allocate("ns/third", "10.200.0.1"); err != nil || got != "10.200.0.1" {
+		t.Errorf("allocate() with a free preferred IP = %s, %v, want 10.200.0.1", got, err)
+	}
+}
+
+func TestIPPoolAddresses(t *testing.T) {
+	tcs := []struct {
+		cidr  string
+		first string
+		last  string
+	}{
+		{cidr: "10.200.0.0/24", first: "10.200.0.1", last: "10.200.0.254"},
+		{cidr: "10.200.0.8/31", first: "10.200.0.8", last: "10.200.0.9"},
+		{cidr: "10.200.0.8/32", first: "10.200.0.8", last: "10.200.0.8"},
+	}
+	for _, tc := range tcs {
+		pool, err := newIPPool(mustParseCIDR(t, tc.cidr), nil, nil)
+		if err != nil {
+			t.Fatalf("newIPPool: %v", err)
+		}
+		first, last := pool.addresses()
+		gotFirst, gotLast := make(net.IP, 4), make(net.IP, 4)
+		binary.BigEndian.PutUint32(gotFirst, first)
+		binary.BigEndian.PutUint32(gotLast, last)
+		if gotFirst.String() != tc.first || gotLast.String() != tc.last {
+			t.Errorf("%s: addresses() = %s-%s, want %s-%s", tc.cidr, gotFirst, gotLast, tc.first, tc.last)
+		}
+	}
+
+	if _, err := newIPPool(mustParseCIDR(t, "fd00::/120"), nil, nil); err == nil {
+		t.Errorf("newIPPool() with an IPv6 CIDR succeeded")
+	}
+}
@@ -37,18 +37,62 @@ type patchConverter interface {
 	convert(restClient rest.Interface, patch *Patch) *rest.Request
 }
 
-// loadBalancerEmulator is the main struct for emulating the loadbalancer behavior. it sets the ingress to the cluster IP
+// loadBalancerEmulator is the main struct for emulating the loadbalancer behavior. it sets the ingress to the cluster IP,
+// or to an IP allocated from a pool if there is one
 type loadBalancerEmulator struct {
 	coreV1Client   typed_core.CoreV1Interface
 	requestSender  requestSender
 	patchConverter patchConverter
+	pool           *ipPool
+
+	// ingressIPs are the ingress IPs of the services patched by the last call to PatchServices, by namespace/name
+	ingressIPs map[string]string
 }
 
 func (l *loadBalancerEmulator) PatchServices() ([]string, error) {
-	return l.applyOnLBServices(l.updateService)
+	ips := map[string]string{}
+	inUse := map[string]bool{}
+	managedServices, err := l.applyOnLBServices(func(restClient rest.Interface, svc core.Service) ([]byte, error) {
+		inUse[serviceKey(svc)] = true
+		ip, err := l.ingressIP(svc)
+		if err != nil {
+			return nil, err
+		}
+		result, err := l.updateService(restClient, svc, ip)
+		if err == nil {
+			ips[serviceKey(svc)] = ip
+		}
+		return result, err
+	})
+	l.ingressIPs = nil
+	if len(ips) > 0 {
+		l.ingressIPs = ips
+	}
+	if err == nil && l.pool != nil {
+		err = l.pool.release(inUse)
+	}
+	return managedServices, err
+}
+
+// serviceKey identifies a service across namespaces
+func serviceKey(svc core.Service) string {
+	return svc.Namespace + "/" + svc.Name
+}
+
+// ingressIP returns the IP to set as the LoadBalancer ingress of a service
+func (l *loadBalancerEmulator) ingressIP(svc core.Service) (string, error) {
+	if l.pool == nil {
+		return svc.Spec.ClusterIP, nil
+	}
+	current := ""
+	if ingresses := svc.Status.LoadBalancer.Ingress; len(ingresses) == 1 {
+		current = ingresses[0].IP
+	}
+	return l.pool.allocate(serviceKey(svc), current)
 }
 
 func (l *loadBalancerEmulator) Cleanup() ([]string, error) {
+	l.ingressIPs = nil
 	return l.applyOnLBServices(l.cleanupService)
 }
 
@@ -79,14 +123,13 @@ func (l *loadBalancerEmulator) applyOnLBServices(action func(restClient rest.Int
 	}
 	return managedServices, nil
 }
-func (l *loadBalancerEmulator) updateService(restClient rest.Interface, svc core.Service) ([]byte, error) {
-	clusterIP := svc.Spec.ClusterIP
+func (l *loadBalancerEmulator) updateService(restClient rest.Interface, svc core.Service, ip string) ([]byte, error) {
 	ingresses := svc.Status.LoadBalancer.Ingress
-	if len(ingresses) == 1 && ingresses[0].IP == clusterIP {
+	if len(ingresses) == 1 && ingresses[0].IP == ip {
 		return nil, nil
 	}
-	glog.V(3).Infof("[%s] setting ClusterIP as the LoadBalancer Ingress", svc.Name)
-	jsonPatch := fmt.Sprintf(`[{"op": "add", "path": "/status/loadBalancer/ingress", "value":  [ { "ip": "%s" } ] }]`, clusterIP)
+	glog.V(3).Infof("[%s] setting %s as the LoadBalancer Ingress", svc.Name, ip)
+	jsonPatch := fmt.Sprintf(`[{"op": "add", "path": "/status/loadBalancer/ingress", "value":  [ { "ip": "%s" } ] }]`, ip)
 	patch := &Patch{
 		Type:         types.JSONPatchType,
 		ResourceName: svc.Name,
@@ -99,9 +142,9 @@ func (l *loadBalancerEmulator) updateService(restClient rest.Interface, svc core
 	request := l.patchConverter.convert(restClient, patch)
 	result, err := l.requestSender.send(request)
 	if err != nil {
-		glog.Errorf("error patching %s with IP %s: %s", svc.Name, clusterIP, err)
+		glog.Errorf("error patching %s with IP %s: %s", svc.Name, ip, err)
 	} else {
-		glog.Infof("Patched %s with IP %s", svc.Name, clusterIP)
+		glog.Infof("Patched %s with IP %s", svc.Name, ip)
 	}
 	return result, err
 }
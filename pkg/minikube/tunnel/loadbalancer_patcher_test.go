@@ -234,6 +234,10 @@ func TestServicesWithLoadbalancerType(t *testing.T) {
 		t.Errorf("error in number of requests sent.\nExpected: %v, <nil>\nGot: %v", 2, requestSender.requests)
 	}
 
+	expectedIPs := map[string]string{"ns1/svc1-up-to-date": "10.96.0.3", "ns2/svc2-out-of-date": "10.96.0.4", "ns3/svc3-empty-ingress": "10.96.0.2"}
+	if !reflect.DeepEqual(patcher.ingressIPs, expectedIPs) {
+		t.Errorf("error in ingress IPs.\nExpected: %v\nGot: %v", expectedIPs, patcher.ingressIPs)
+	}
 }
 
 func TestCleanupPatchedIPs(t *testing.T) {
@@ -345,3 +349,66 @@ func TestCleanupPatchedIPs(t *testing.T) {
 		t.Errorf("error in number of requests sent.\nExpected: %v, <nil>\nGot: %v", 2, requestSender.requests)
 	}
 }
+
+func TestPatchServicesFromIPPool(t *testing.T) {
+	lbService := func(name string, ingressIP string) core.Service {
+		svc := core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec:       core.ServiceSpec{Type: "LoadBalancer", ClusterIP: "10.96.0.10"},
+		}
+		if ingressIP != "" {
+			svc.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ingressIP}}
+		}
+		return svc
+	}
+	client := newStubCoreClient(&core.ServiceList{
+		Items: []core.Service{
+			lbService("web", ""),
+			lbService("api", "10.200.0.1"),
+		},
+	})
+
+	var saved map[string]string
+	pool, err := newIPPool(mustParseCIDR(t, "10.200.0.0/24"), map[string]string{"ns/api": "10.200.0.1", "ns/deleted": "10.200.0.2"}, func(ips map[string]string) error {
+		saved = ips
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("newIPPool: %v", err)
+	}
+
+	patchConverter := &recordingPatchConverter{}
+	patcher := newLoadBalancerEmulator(client)
+	patcher.requestSender = &countingRequestSender{}
+	patcher.patchConverter = patchConverter
+	patcher.pool = pool
+
+	if _, err := patcher.PatchServices(); err != nil {
+		t.Fatalf("PatchServices: %v", err)
+	}
+
+	// the IP of the deleted service is only released once the other services have their IP
+	expectedPatches := []*Patch{
+		{
+			Type:         "application/json-patch+json",
+			NameSpace:    "ns",
+			NameSpaceSet: true,
+			Resource:     "services",
+			Subresource:  "status",
+			ResourceName: "web",
+			BodyContent:  `[{"op": "add", "path": "/status/loadBalancer/ingress", "value":  [ { "ip": "10.200.0.3" } ] }]`,
+		},
+	}
+	if !reflect.DeepEqual(patchConverter.patches, expectedPatches) {
+		t.Errorf("error in patches.\nExpected: %v\nGot: %v", expectedPatches, patchConverter.patches)
+	}
+
+	expectedIPs := map[string]string{"ns/web": "10.200.0.3", "ns/api": "10.200.0.1"}
+	if !reflect.DeepEqual(patcher.ingressIPs, expectedIPs) {
+		t.Errorf("error in ingress IPs.\nExpected: %v\nGot: %v", expectedIPs, patcher.ingressIPs)
+	}
+	expectedSaved := map[string]string{"ns/web": "10.200.0.3", "ns/api": "10.200.0.1"}
+	if !reflect.DeepEqual(saved, expectedSaved) {
+		t.Errorf("error in saved allocations.\nExpected: %v\nGot: %v", expectedSaved, saved)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// remapCandidates is the range the CIDRs suggested for remapping the services of a profile are taken from
+const remapCandidates = "10.200.0.0/13"
+
+// CIDROverlapError is returned when the tunnels of two profiles would route overlapping CIDRs
+type CIDROverlapError struct {
+	Profile      string
+	CIDR         *net.IPNet
+	OtherProfile string
+	OtherCIDR    *net.IPNet
+	// Suggestion is a free CIDR the services of OtherProfile can be remapped to
+	Suggestion *net.IPNet
+}
+
+func (e *CIDROverlapError) Error() string {
+	return fmt.Sprintf("the CIDR %s routed for profile %s overlaps with the CIDR %s routed for profile %s", e.CIDR, e.Profile, e.OtherCIDR, e.OtherProfile)
+}
+
+// routedCIDRs returns the CIDR the tunnel of each profile routes: its service CIDR, unless its services are remapped
+func routedCIDRs(serviceCIDRs map[string]*net.IPNet, remaps map[string]*net.IPNet) map[string]*net.IPNet {
+	routed := map[string]*net.IPNet{}
+	for profile, cidr := range serviceCIDRs {
+		routed[profile] = cidr
+		if remap, ok := remaps[profile]; ok {
+			routed[profile] = remap
+		}
+	}
+	return routed
+}
+
+// CheckServiceCIDRs checks up front that the tunnels of several profiles can run along each other, as the CIDRs
+// they route do not overlap. Besides the service CIDR or remapped CIDR of each profile, the LoadBalancer IP pools
+// of the profiles which are not remapped are routed as well. A CIDROverlapError is returned for the first overlap found.
+func CheckServiceCIDRs(serviceCIDRs map[string]*net.IPNet, remaps map[string]*net.IPNet, pools map[string]*net.IPNet) error {
+	for profile := range remaps {
+		if _, ok := serviceCIDRs[profile]; !ok {
+			return fmt.Errorf("cannot remap the services of profile %s, which is not tunneled", profile)
+		}
+	}
+	for profile := range pools {
+		if _, ok := serviceCIDRs[profile]; !ok {
+			return fmt.Errorf("cannot allocate LoadBalancer IPs for profile %s, which is not tunneled", profile)
+		}
+	}
+	routed := routedCIDRs(serviceCIDRs, remaps)
+	var profiles []string
+	for profile := range routed {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	// a remapped profile allocates its LoadBalancer IPs from the remapped CIDR instead of the pool
+	cidrsOf := func(profile string) []*net.IPNet {
+		cidrs := []*net.IPNet{routed[profile]}
+		if _, remapped := remaps[profile]; !remapped && pools[profile] != nil {
+			cidrs = append(cidrs, pools[profile])
+		}
+		return cidrs
+	}
+	for i, p := range profiles {
+		for _, other := range profiles[i+1:] {
+			for _, cidr := range cidrsOf(p) {
+				for _, otherCIDR := range cidrsOf(other) {
+					if cidrsOverlap(cidr, otherCIDR) {
+						return &CIDROverlapError{
+							Profile:      p,
+							CIDR:         cidr,
+							OtherProfile: other,
+							OtherCIDR:    otherCIDR,
+							Suggestion:   freeCIDR(routed, serviceCIDRs, pools),
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func cidrsOverlap(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// freeCIDR returns a /24 within remapCandidates which does not overlap with any of the given CIDRs,
+// or nil if there is none
+func freeCIDR(cidrs ...map[string]*net.IPNet) *net.IPNet {
+	_, candidates, _ := net.ParseCIDR(remapCandidates)
+	first := binary.BigEndian.Uint32(candidates.IP.To4())
+	ones, _ := candidates.Mask.Size()
+	for i := uint32(0); i < 1<<uint(24-ones); i++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, first+i<<8)
+		candidate := &net.IPNet{IP: ip, Mask: net.CIDRMask(24, 32)}
+		if !overlapsAny(candidate, cidrs...) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func overlapsAny(cidr *net.IPNet, cidrs ...map[string]*net.IPNet) bool {
+	for _, m := range cidrs {
+		for _, other := range m {
+			if cidrsOverlap(cidr, other) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCheckServiceCIDRs(t *testing.T) {
+	tcs := []struct {
+		name         string
+		serviceCIDRs map[string]string
+		remaps       map[string]string
+		pools        map[string]string
+		wantOverlap  [2]string
+		wantErr      bool
+		suggestion   string
+	}{
+		{
+			name:         "distinct",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "172.16.0.0/16"},
+		},
+		{
+			name:         "overlapping",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "10.96.0.0/12", "c": "172.16.0.0/16"},
+			wantOverlap:  [2]string{"a", "b"},
+			suggestion:   "10.200.0.0/24",
+		},
+		{
+			name:         "remapped",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "10.96.0.0/12"},
+			remaps:       map[string]string{"b": "10.200.0.0/24"},
+		},
+		{
+			name:         "remapped onto another profile",
+			serviceCIDRs: map[string]string{"a": "10.200.0.0/16", "b": "10.96.0.0/12", "c": "10.96.0.0/12"},
+			remaps:       map[string]string{"c": "10.200.1.0/24"},
+			wantOverlap:  [2]string{"a", "c"},
+			suggestion:   "10.201.0.0/24",
+		},
+		{
+			name:         "pool",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "172.16.0.0/16"},
+			pools:        map[string]string{"a": "10.200.0.0/24"},
+		},
+		{
+			name:         "pool overlapping another profile",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "172.16.0.0/16"},
+			pools:        map[string]string{"a": "172.16.1.0/24"},
+			wantOverlap:  [2]string{"a", "b"},
+			suggestion:   "10.200.0.0/24",
+		},
+		{
+			name:         "pools overlapping each other",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "172.16.0.0/16"},
+			pools:        map[string]string{"a": "10.200.0.0/24", "b": "10.200.0.0/16"},
+			wantOverlap:  [2]string{"a", "b"},
+			suggestion:   "10.201.0.0/24",
+		},
+		{
+			name:         "pool of a remapped profile",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12", "b": "10.96.0.0/12"},
+			remaps:       map[string]string{"b": "10.200.0.0/24"},
+			pools:        map[string]string{"b": "10.96.1.0/24"},
+		},
+		{
+			name:         "pool of a profile not tunneled",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12"},
+			pools:        map[string]string{"b": "10.200.0.0/24"},
+			wantErr:      true,
+		},
+		{
+			name:         "remap of a profile not tunneled",
+			serviceCIDRs: map[string]string{"a": "10.96.0.0/12"},
+			remaps:       map[string]string{"b": "10.200.0.0/24"},
+			wantErr:      true,
+		},
+	}
+	parse := func(cidrs map[string]string) map[string]*net.IPNet {
+		parsed := map[string]*net.IPNet{}
+		for p, c := range cidrs {
+			parsed[p] = mustParseCIDR(t, c)
+		}
+		return parsed
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckServiceCIDRs(parse(tc.serviceCIDRs), parse(tc.remaps), parse(tc.pools))
+			if tc.wantOverlap[0] == "" {
+				if (err != nil) != tc.wantErr {
+					t.Errorf("CheckServiceCIDRs() = %v, want error: %v", err, tc.wantErr)
+				}
+				return
+			}
+			oe, ok := err.(*CIDROverlapError)
+			if !ok {
+				t.Fatalf("CheckServiceCIDRs() = %v, want a CIDROverlapError", err)
+			}
+			if oe.Profile != tc.wantOverlap[0] || oe.OtherProfile != tc.wantOverlap[1] {
+				t.Errorf("overlap between %s and %s, want %s and %s", oe.Profile, oe.OtherProfile, tc.wantOverlap[0], tc.wantOverlap[1])
+			}
+			if oe.Suggestion == nil || oe.Suggestion.String() != tc.suggestion {
+				t.Errorf("suggestion = %v, want %s", oe.Suggestion, tc.suggestion)
+			}
+		})
+	}
+}
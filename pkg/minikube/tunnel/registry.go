@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -36,6 +38,8 @@ type ID struct {
 	// the rest is metadata
 	MachineName string
 	Pid         int
+	// LoadBalancerRoute is the route to the LoadBalancer IP pool, if the tunnel allocates IPs from one
+	LoadBalancerRoute *Route `json:",omitempty"`
 }
 
 // Equal checks if two ID are equal
@@ -196,3 +200,56 @@ func (r *persistentRegistry) List() ([]*ID, error) {
 
 	return tunnels, nil
 }
+
+// loadBalancerIPsPath returns the path of the file where the LoadBalancer IPs allocated by tunnels are kept
+func (r *persistentRegistry) loadBalancerIPsPath() string {
+	return strings.TrimSuffix(r.path, filepath.Ext(r.path)) + "_ips.json"
+}
+
+// loadBalancerIPs returns the LoadBalancer IPs allocated to services, by machine and namespace/name.
+// Unlike tunnels, they are kept when a tunnel is stopped, so that services get the same IP from the next tunnel.
+func (r *persistentRegistry) loadBalancerIPs() (map[string]map[string]string, error) {
+	ips := map[string]map[string]string{}
+	data, err := ioutil.ReadFile(r.loadBalancerIPsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ips, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return ips, nil
+	}
+	if err := json.Unmarshal(data, &ips); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return ips, nil
+}
+
+// LoadBalancerIPs returns the LoadBalancer IPs allocated to the services of a machine, by namespace/name
+func (r *persistentRegistry) LoadBalancerIPs(machineName string) (map[string]string, error) {
+	ips, err := r.loadBalancerIPs()
+	if err != nil {
+		return nil, err
+	}
+	return ips[machineName], nil
+}
+
+// SaveLoadBalancerIPs replaces the LoadBalancer IPs allocated to the services of a machine
+func (r *persistentRegistry) SaveLoadBalancerIPs(machineName string, machineIPs map[string]string) error {
+	glog.V(3).Infof("saving LoadBalancer IPs of %s: %v", machineName, machineIPs)
+	ips, err := r.loadBalancerIPs()
+	if err != nil {
+		return err
+	}
+	if len(machineIPs) == 0 {
+		delete(ips, machineName)
+	} else {
+		ips[machineName] = machineIPs
+	}
+	data, err := json.Marshal(ips)
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	return ioutil.WriteFile(r.loadBalancerIPsPath(), data, 0600)
+}
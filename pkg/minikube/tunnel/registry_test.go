@@ -266,3 +266,48 @@ func createTestRegistry(t *testing.T) (reg *persistentRegistry, cleanup func())
 	}
 	return registry, func() { os.Remove(f.Name()) }
 }
+
+func TestLoadBalancerIPs(t *testing.T) {
+	registry, cleanup := createTestRegistry(t)
+	defer cleanup()
+	defer os.Remove(registry.loadBalancerIPsPath())
+
+	ips, err := registry.LoadBalancerIPs("testmachine")
+	if err != nil || len(ips) != 0 {
+		t.Errorf("expected no IPs, got %v, %v", ips, err)
+	}
+
+	machineIPs := map[string]string{"ns/svc1": "10.200.0.1"}
+	if err := registry.SaveLoadBalancerIPs("testmachine", machineIPs); err != nil {
+		t.Fatalf("SaveLoadBalancerIPs: %v", err)
+	}
+	if err := registry.SaveLoadBalancerIPs("othermachine", map[string]string{"ns/svc2": "10.200.0.2"}); err != nil {
+		t.Fatalf("SaveLoadBalancerIPs: %v", err)
+	}
+
+	ips, err = registry.LoadBalancerIPs("testmachine")
+	if err != nil || !reflect.DeepEqual(ips, machineIPs) {
+		t.Errorf("expected %v, nil error, got %v, %v", machineIPs, ips, err)
+	}
+
+	// the IPs are kept when the tunnel is removed from the registry
+	route := unsafeParseRoute("1.2.3.4", "10.96.0.0/12")
+	if err := registry.Register(&ID{Route: route, MachineName: "testmachine"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Remove(route); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	ips, err = registry.LoadBalancerIPs("testmachine")
+	if err != nil || !reflect.DeepEqual(ips, machineIPs) {
+		t.Errorf("expected %v after removing the tunnel, got %v, %v", machineIPs, ips, err)
+	}
+
+	if err := registry.SaveLoadBalancerIPs("testmachine", nil); err != nil {
+		t.Fatalf("SaveLoadBalancerIPs: %v", err)
+	}
+	ips, err = registry.LoadBalancerIPs("testmachine")
+	if err != nil || len(ips) != 0 {
+		t.Errorf("expected no IPs after saving none, got %v, %v", ips, err)
+	}
+}
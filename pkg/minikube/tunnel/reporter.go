@@ -17,7 +17,10 @@ limitations under the License.
 package tunnel
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"io"
 	"strings"
@@ -30,6 +33,21 @@ type reporter interface {
 	Report(tunnelState *Status)
 }
 
+// stoppedReporter is implemented by reporters which report the final status of a tunnel, once it is cleaned up
+type stoppedReporter interface {
+	ReportStopped(tunnelState *Status)
+}
+
+// ReportFormat is the format in which a tunnel reports its status
+type ReportFormat string
+
+const (
+	// TextFormat reports the whole status of the tunnel in a human readable form, whenever it is checked
+	TextFormat ReportFormat = "text"
+	// JSONFormat reports each change in the status of the tunnel as a JSON event
+	JSONFormat ReportFormat = "json"
+)
+
 type simpleReporter struct {
 	out       io.Writer
 	lastState *Status
@@ -42,47 +60,195 @@ func (r *simpleReporter) Report(tunnelState *Status) {
 		return
 	}
 	r.lastState = tunnelState
-	minikubeState := tunnelState.MinikubeState.String()
-
-	managedServices := fmt.Sprintf("[%s]", strings.Join(tunnelState.PatchedServices, ", "))
-
-	lbError := noErrors
-	if tunnelState.LoadBalancerEmulatorError != nil {
-		lbError = tunnelState.LoadBalancerEmulatorError.Error()
+	if err := WriteReport(r.out, NewStatusReport(tunnelState)); err != nil {
+		glog.Errorf("failed to report state %s", err)
 	}
+}
 
-	minikubeError := noErrors
-	if tunnelState.MinikubeError != nil {
-		minikubeError = tunnelState.MinikubeError.Error()
+// orNoErrors returns the message of an error, or noErrors if there is none
+func orNoErrors(message string) string {
+	if message == "" {
+		return noErrors
 	}
+	return message
+}
 
-	routerError := noErrors
-	if tunnelState.RouteError != nil {
-		routerError = tunnelState.RouteError.Error()
-	}
+// WriteReport writes the human readable form of a tunnel status
+func WriteReport(out io.Writer, report *StatusReport) error {
+	managedServices := fmt.Sprintf("[%s]", strings.Join(report.PatchedServices, ", "))
 
 	errors := fmt.Sprintf(`    errors: 
 		minikube: %s
 		router: %s
 		loadbalancer emulator: %s
-`, minikubeError, routerError, lbError)
+`, orNoErrors(report.MinikubeError), orNoErrors(report.RouteError), orNoErrors(report.LoadBalancerEmulatorError))
 
-	_, err := r.out.Write([]byte(fmt.Sprintf(
+	_, err := out.Write([]byte(fmt.Sprintf(
 		`Status:	
 	machine: %s
 	pid: %d
 	route: %s
 	minikube: %s
 	services: %s
-%s`, tunnelState.TunnelID.MachineName,
-		tunnelState.TunnelID.Pid,
-		tunnelState.TunnelID.Route,
-		minikubeState,
+%s`, report.MachineName,
+		report.Pid,
+		report.Route,
+		report.MinikubeState,
 		managedServices,
 		errors)))
-	if err != nil {
-		glog.Errorf("failed to report state %s", err)
+	return err
+}
+
+// multiReporter reports the status of a tunnel to several reporters
+type multiReporter []reporter
+
+func (m multiReporter) Report(tunnelState *Status) {
+	for _, r := range m {
+		r.Report(tunnelState)
+	}
+}
+
+func (m multiReporter) ReportStopped(tunnelState *Status) {
+	for _, r := range m {
+		if sr, ok := r.(stoppedReporter); ok {
+			sr.ReportStopped(tunnelState)
+		}
+	}
+}
+
+// Event types reported by the JSON reporter
+const (
+	EventMinikubeState    = "minikube-state"
+	EventRouteAdded       = "route-added"
+	EventRouteRemoved     = "route-removed"
+	EventServicePatched   = "service-patched"
+	EventServiceUnpatched = "service-unpatched"
+	EventError            = "error"
+	EventTunnelStopped    = "tunnel-stopped"
+)
+
+// Event is a change in the status of a tunnel, as reported in JSON
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Machine string    `json:"machine"`
+	State   string    `json:"state,omitempty"`
+	Route   string    `json:"route,omitempty"`
+	Service string    `json:"service,omitempty"`
+	IP      string    `json:"ip,omitempty"`
+	// Component is the part of the tunnel an error comes from: minikube, router or loadbalancer
+	Component string `json:"component,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// jsonReporter writes a JSON event, one per line, for each change between the statuses it is reported
+type jsonReporter struct {
+	out  io.Writer
+	last *StatusReport
+	now  func() time.Time
+}
+
+func newJSONReporter(out io.Writer) *jsonReporter {
+	return &jsonReporter{
+		out: out,
+		now: time.Now,
+	}
+}
+
+func (r *jsonReporter) Report(tunnelState *Status) {
+	r.reportChanges(NewStatusReport(tunnelState))
+}
+
+// ReportStopped reports the removal of the route and the services unpatched by the cleanup of the tunnel
+func (r *jsonReporter) ReportStopped(tunnelState *Status) {
+	final := NewStatusReport(tunnelState)
+	final.ServiceIPs = nil
+	events := changes(r.last, final)
+	if routeActive(r.last) && routeActive(final) {
+		// the route was removed without errors
+		events = append(events, Event{Type: EventRouteRemoved, Machine: final.MachineName, Route: final.Route})
+	}
+	events = append(events, Event{Type: EventTunnelStopped, Machine: final.MachineName})
+	r.write(events)
+	r.last = nil
+}
+
+// reportChanges writes the events between the last status reported and the next one
+func (r *jsonReporter) reportChanges(next *StatusReport) {
+	r.write(changes(r.last, next))
+	r.last = next
+}
+
+func (r *jsonReporter) write(events []Event) {
+	enc := json.NewEncoder(r.out)
+	for _, e := range events {
+		e.Time = r.now()
+		if err := enc.Encode(e); err != nil {
+			glog.Errorf("failed to report event %s", err)
+		}
+	}
+}
+
+// routeActive returns whether the route of a tunnel is in place
+func routeActive(s *StatusReport) bool {
+	return s != nil && s.MinikubeState == Running.String() && s.RouteError == ""
+}
+
+// changes returns the events between two statuses of a tunnel, where prev is nil for the first status
+func changes(prev *StatusReport, next *StatusReport) []Event {
+	var events []Event
+	event := func(e Event) {
+		e.Machine = next.MachineName
+		events = append(events, e)
+	}
+
+	if prev == nil || prev.MinikubeState != next.MinikubeState {
+		event(Event{Type: EventMinikubeState, State: next.MinikubeState})
+	}
+
+	wasActive, isActive := routeActive(prev), routeActive(next)
+	if !wasActive && isActive {
+		event(Event{Type: EventRouteAdded, Route: next.Route})
+	}
+	if wasActive && !isActive {
+		event(Event{Type: EventRouteRemoved, Route: prev.Route})
+	}
+
+	var prevIPs map[string]string
+	if prev != nil {
+		prevIPs = prev.ServiceIPs
+	}
+	for _, svc := range sortedKeys(prevIPs) {
+		if _, ok := next.ServiceIPs[svc]; !ok {
+			event(Event{Type: EventServiceUnpatched, Service: svc, IP: prevIPs[svc]})
+		}
+	}
+	for _, svc := range sortedKeys(next.ServiceIPs) {
+		if ip := next.ServiceIPs[svc]; prevIPs[svc] != ip {
+			event(Event{Type: EventServicePatched, Service: svc, IP: ip})
+		}
+	}
+
+	var prevErrors [3]string
+	if prev != nil {
+		prevErrors = [3]string{prev.MinikubeError, prev.RouteError, prev.LoadBalancerEmulatorError}
+	}
+	nextErrors := [3]string{next.MinikubeError, next.RouteError, next.LoadBalancerEmulatorError}
+	for i, component := range []string{"minikube", "router", "loadbalancer"} {
+		if nextErrors[i] != "" && nextErrors[i] != prevErrors[i] {
+			event(Event{Type: EventError, Component: component, Error: nextErrors[i]})
+		}
+	}
+	return events
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
 }
 
 func newReporter(out io.Writer) reporter {
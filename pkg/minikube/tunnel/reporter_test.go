@@ -17,10 +17,15 @@ limitations under the License.
 package tunnel
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestReporter(t *testing.T) {
@@ -121,6 +126,102 @@ Got:	  "%s"`, expectedOutput, out.output)
 	}
 }
 
+func TestJSONReporter(t *testing.T) {
+	route := unsafeParseRoute("1.2.3.4", "10.96.0.0/12")
+	routeError := errors.New("conflicting route")
+	status := func(state HostState, err error, ips map[string]string) *Status {
+		return &Status{
+			TunnelID:      ID{Route: route, MachineName: "testmachine", Pid: 1234},
+			MinikubeState: state,
+			RouteError:    err,
+			ServiceIPs:    ips,
+		}
+	}
+
+	var out bytes.Buffer
+	r := newJSONReporter(&out)
+	r.now = func() time.Time { return time.Time{} }
+
+	steps := []struct {
+		name    string
+		status  *Status
+		stopped bool
+		want    []Event
+	}{
+		{
+			name:   "first status",
+			status: status(Running, nil, map[string]string{"svc1": "10.96.0.3"}),
+			want: []Event{
+				{Type: EventMinikubeState, Machine: "testmachine", State: "Running"},
+				{Type: EventRouteAdded, Machine: "testmachine", Route: "10.96.0.0/12 -> 1.2.3.4"},
+				{Type: EventServicePatched, Machine: "testmachine", Service: "svc1", IP: "10.96.0.3"},
+			},
+		},
+		{
+			name:   "no change",
+			status: status(Running, nil, map[string]string{"svc1": "10.96.0.3"}),
+		},
+		{
+			name:   "service replaced",
+			status: status(Running, nil, map[string]string{"svc2": "10.96.0.4"}),
+			want: []Event{
+				{Type: EventServiceUnpatched, Machine: "testmachine", Service: "svc1", IP: "10.96.0.3"},
+				{Type: EventServicePatched, Machine: "testmachine", Service: "svc2", IP: "10.96.0.4"},
+			},
+		},
+		{
+			name:   "route error",
+			status: status(Running, routeError, map[string]string{"svc2": "10.96.0.4"}),
+			want: []Event{
+				{Type: EventRouteRemoved, Machine: "testmachine", Route: "10.96.0.0/12 -> 1.2.3.4"},
+				{Type: EventError, Machine: "testmachine", Component: "router", Error: "conflicting route"},
+			},
+		},
+		{
+			name:   "route error repeated",
+			status: status(Running, routeError, map[string]string{"svc2": "10.96.0.4"}),
+		},
+		{
+			name:   "route restored",
+			status: status(Running, nil, map[string]string{"svc2": "10.96.0.4"}),
+			want: []Event{
+				{Type: EventRouteAdded, Machine: "testmachine", Route: "10.96.0.0/12 -> 1.2.3.4"},
+			},
+		},
+		{
+			name:    "stopped",
+			status:  status(Running, nil, nil),
+			stopped: true,
+			want: []Event{
+				{Type: EventServiceUnpatched, Machine: "testmachine", Service: "svc2", IP: "10.96.0.4"},
+				{Type: EventRouteRemoved, Machine: "testmachine", Route: "10.96.0.0/12 -> 1.2.3.4"},
+				{Type: EventTunnelStopped, Machine: "testmachine"},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		out.Reset()
+		if step.stopped {
+			r.ReportStopped(step.status)
+		} else {
+			r.Report(step.status)
+		}
+		var got []Event
+		dec := json.NewDecoder(&out)
+		for dec.More() {
+			var e Event
+			if err := dec.Decode(&e); err != nil {
+				t.Fatalf("%s: decode: %v", step.name, err)
+			}
+			got = append(got, e)
+		}
+		if diff := cmp.Diff(step.want, got); diff != "" {
+			t.Errorf("%s: events differ: (-want +got)\n%s", step.name, diff)
+		}
+	}
+}
+
 type recordingWriter struct {
 	output string
 }
@@ -36,8 +36,11 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	if exists {
 		return nil
 	}
-	if err := writeResolverFile(route); err != nil {
-		return fmt.Errorf("could not write /etc/resolver/{cluster_domain} file: %s", err)
+	// routes to a LoadBalancer IP pool have no cluster domain to resolve
+	if route.ClusterDomain != "" {
+		if err := writeResolverFile(route); err != nil {
+			return fmt.Errorf("could not write /etc/resolver/{cluster_domain} file: %s", err)
+		}
 	}
 
 	serviceCIDR := route.DestCIDR.String()
@@ -167,6 +170,9 @@ func (router *osRouter) Cleanup(route *Route) error {
 	if !re.MatchString(message) {
 		return fmt.Errorf("error deleting route: %s, %d", message, len(strings.Split(message, "\n")))
 	}
+	if route.ClusterDomain == "" {
+		return nil
+	}
 	// idempotent removal of cluster domain dns
 	resolverFile := fmt.Sprintf("/etc/resolver/%s", route.ClusterDomain)
 	command = exec.Command("sudo", "rm", "-f", resolverFile)
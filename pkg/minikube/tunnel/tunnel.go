@@ -18,6 +18,7 @@ package tunnel
 
 import (
 	"fmt"
+	"net"
 	"os"
 
 	"os/exec"
@@ -28,6 +29,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
+	typed_extensions "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -91,24 +93,91 @@ type tunnel struct {
 	loadBalancerEmulator loadBalancerEmulator
 	reporter             reporter
 	registry             *persistentRegistry
+	dns                  *tunnelDNS
 
 	status *Status
 }
 
+// useIPPool makes the tunnel allocate the LoadBalancer IPs of services from a pool, which is routed to the cluster.
+// The IPs allocated are kept in the registry, so that services get the same IP from the next tunnel.
+func (t *tunnel) useIPPool(cidr *net.IPNet) error {
+	route := t.status.TunnelID.Route
+	if route.DestCIDR.Contains(cidr.IP) || cidr.Contains(route.DestCIDR.IP) {
+		return fmt.Errorf("LoadBalancer IP pool %s overlaps with the service CIDR %s", cidr, route.DestCIDR)
+	}
+	machineName := t.status.TunnelID.MachineName
+	allocated, err := t.registry.LoadBalancerIPs(machineName)
+	if err != nil {
+		return errors.Wrap(err, "loading allocated LoadBalancer IPs")
+	}
+	pool, err := newIPPool(cidr, allocated, func(ips map[string]string) error {
+		return t.registry.SaveLoadBalancerIPs(machineName, ips)
+	})
+	if err != nil {
+		return err
+	}
+	t.loadBalancerEmulator.pool = pool
+	t.status.TunnelID.LoadBalancerRoute = &Route{
+		Gateway:  route.Gateway,
+		DestCIDR: cidr,
+	}
+	return nil
+}
+
+// remapServices makes the tunnel route another CIDR than the service CIDR, from which the LoadBalancer IPs of services
+// are allocated. This keeps the LoadBalancers of clusters with overlapping service CIDRs reachable, as kube-proxy
+// forwards the traffic for the LoadBalancer IP of a service within the cluster. The ClusterIPs of services are not
+// reachable from the host, and neither is the cluster DNS, which the tunnel of the other cluster already resolves.
+func (t *tunnel) remapServices(cidr *net.IPNet) error {
+	if err := t.useIPPool(cidr); err != nil {
+		return err
+	}
+	t.status.TunnelID.Route = t.status.TunnelID.LoadBalancerRoute
+	t.status.TunnelID.LoadBalancerRoute = nil
+	return nil
+}
+
+// useDNS makes the DNS responder of the tunnels answer for the cluster, so that the host resolves the cluster domain
+// and the hosts of Ingress objects through it.
+func (t *tunnel) useDNS(server *dnsServer, ingresses typed_extensions.IngressesGetter) error {
+	route := t.status.TunnelID.Route
+	if route.ClusterDomain == "" || route.ClusterDNSIP == nil {
+		glog.V(3).Infof("no cluster DNS to resolve through for %s", route)
+		return nil
+	}
+	dns, err := newTunnelDNS(route, server, ingresses)
+	if err != nil {
+		return err
+	}
+	t.dns = dns
+	return nil
+}
+
 func (t *tunnel) cleanup() *Status {
 	glog.V(3).Infof("cleaning up %s", t.status.TunnelID.Route)
 	err := t.router.Cleanup(t.status.TunnelID.Route)
+	if err == nil && t.status.TunnelID.LoadBalancerRoute != nil {
+		err = t.router.Cleanup(t.status.TunnelID.LoadBalancerRoute)
+	}
 	if err != nil {
 		t.status.RouteError = errors.Errorf("error cleaning up route: %v", err)
 		glog.V(3).Infof(t.status.RouteError.Error())
-	} else {
-		err = t.registry.Remove(t.status.TunnelID.Route)
-		if err != nil {
+	}
+	if t.dns != nil {
+		if dnsErr := t.dns.cleanup(); dnsErr != nil {
+			glog.Warningf("error cleaning up tunnel DNS: %v", dnsErr)
+			err = dnsErr
+		}
+	}
+	// the registry entry is kept when cleaning up failed, so that 'minikube tunnel stop' can finish the job
+	if err == nil {
+		if err := t.registry.Remove(t.status.TunnelID.Route); err != nil {
 			glog.V(3).Infof("error removing route from registry: %v", err)
 		}
 	}
 	if t.status.MinikubeState == Running {
 		t.status.PatchedServices, t.status.LoadBalancerEmulatorError = t.loadBalancerEmulator.Cleanup()
+		t.status.ServiceIPs = nil
 	}
 	return t.status
 }
@@ -121,8 +190,17 @@ func (t *tunnel) update() *Status {
 	if t.status.MinikubeState == Running {
 		glog.V(3).Infof("minikube is running, trying to add route%s", t.status.TunnelID.Route)
 		setupRoute(t, h)
+		if lbRoute := t.status.TunnelID.LoadBalancerRoute; t.status.RouteError == nil && lbRoute != nil {
+			t.status.RouteError = t.router.EnsureRouteIsAdded(lbRoute)
+		}
 		if t.status.RouteError == nil {
 			t.status.PatchedServices, t.status.LoadBalancerEmulatorError = t.loadBalancerEmulator.PatchServices()
+			t.status.ServiceIPs = t.loadBalancerEmulator.ingressIPs
+		}
+		if t.dns != nil && t.status.RouteError == nil {
+			if err := t.dns.update(); err != nil {
+				glog.Warningf("error updating tunnel DNS: %v", err)
+			}
 		}
 	}
 	glog.V(3).Infof("sending report %s", t.status)
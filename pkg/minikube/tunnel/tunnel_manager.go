@@ -17,6 +17,9 @@ limitations under the License.
 package tunnel
 
 import (
+	"io"
+	"net"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -25,7 +28,8 @@ import (
 
 	"github.com/docker/machine/libmachine"
 	"github.com/golang/glog"
-	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
 )
@@ -37,6 +41,10 @@ type Manager struct {
 	delay    time.Duration
 	registry *persistentRegistry
 	router   router
+	format   ReportFormat
+	ipPool   *net.IPNet
+	remaps   map[string]*net.IPNet
+	dns      *dnsServer
 }
 
 // stateCheckInterval defines how frequently the cluster and route states are checked
@@ -55,18 +63,200 @@ func NewManager() *Manager {
 			path: RegistryPath(),
 		},
 		router: &osRouter{},
+		format: TextFormat,
+		dns:    newDNSServer(newHostResolver()),
 	}
 }
 
+// SetReportFormat sets the format in which tunnels report their status
+func (mgr *Manager) SetReportFormat(format ReportFormat) {
+	mgr.format = format
+}
+
+// SetIPPool makes tunnels allocate a distinct LoadBalancer IP to each service from a CIDR, instead of using its ClusterIP
+func (mgr *Manager) SetIPPool(cidr *net.IPNet) {
+	mgr.ipPool = cidr
+}
+
+// SetRemap makes the tunnel of a machine route a CIDR other than its service CIDR, from which the LoadBalancer IPs
+// of its services are allocated, so that it can run along a tunnel to a cluster with an overlapping service CIDR
+func (mgr *Manager) SetRemap(machineName string, cidr *net.IPNet) {
+	if mgr.remaps == nil {
+		mgr.remaps = map[string]*net.IPNet{}
+	}
+	mgr.remaps[machineName] = cidr
+}
+
 // StartTunnel starts the tunnel
-func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface) (done chan bool, err error) {
-	tunnel, err := newTunnel(machineName, machineAPI, configLoader, v1Core, mgr.registry, mgr.router)
+func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, clientset kubernetes.Interface) (done chan bool, err error) {
+	tunnel, err := newTunnel(machineName, machineAPI, configLoader, clientset.CoreV1(), mgr.registry, mgr.router)
 	if err != nil {
 		return nil, fmt.Errorf("error creating tunnel: %s", err)
 	}
-	return mgr.startTunnel(ctx, tunnel)
+	if remap, ok := mgr.remaps[machineName]; ok {
+		if err := tunnel.remapServices(remap); err != nil {
+			return nil, fmt.Errorf("error remapping services to %s: %s", remap, err)
+		}
+	} else if mgr.ipPool != nil {
+		if err := tunnel.useIPPool(mgr.ipPool); err != nil {
+			return nil, fmt.Errorf("error setting up LoadBalancer IP pool: %s", err)
+		}
+	}
 
+	// the tunnel can also be stopped through its control endpoint
+	ctx, cancel := context.WithCancel(ctx)
+	control, err := newControlServer(ControlSocketPath(machineName), cancel)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("error serving tunnel control endpoint: %s", err)
+	}
+	control.Report(tunnel.status.Clone())
+	if mgr.dns != nil {
+		if err := tunnel.useDNS(mgr.dns, clientset.ExtensionsV1beta1()); err != nil {
+			glog.Warningf("unable to run the tunnel DNS responder, cluster names will not resolve from the host: %v", err)
+		}
+	}
+	if mgr.format == JSONFormat {
+		tunnel.reporter = newJSONReporter(os.Stdout)
+	}
+	reporter := multiReporter{tunnel.reporter, control}
+	tunnel.reporter = reporter
+
+	tunnelDone, err := mgr.startTunnel(ctx, tunnel)
+	if err != nil {
+		cancel()
+		if err := control.Close(); err != nil {
+			glog.Warningf("closing tunnel control endpoint: %v", err)
+		}
+		return nil, err
+	}
+	done = make(chan bool, 1)
+	go func() {
+		<-tunnelDone
+		reporter.ReportStopped(tunnel.status.Clone())
+		if err := control.Close(); err != nil {
+			glog.Warningf("closing tunnel control endpoint: %v", err)
+		}
+		cancel()
+		done <- true
+	}()
+	return done, nil
 }
+
+// AttachTunnel reports the status of the tunnel already running for a machine, until the context is cancelled
+// or the tunnel stops. Cancelling the context detaches from the tunnel, without stopping it.
+func (mgr *Manager) AttachTunnel(ctx context.Context, machineName string, out io.Writer) (done chan bool) {
+	done = make(chan bool, 1)
+	jr := newJSONReporter(out)
+	go func() {
+		defer func() {
+			done <- true
+		}()
+		for {
+			report, err := QueryStatus(machineName)
+			if err != nil {
+				glog.Infof("tunnel for %s is gone: %v", machineName, err)
+				return
+			}
+			if mgr.format == JSONFormat {
+				jr.reportChanges(report)
+			} else if err := WriteReport(out, report); err != nil {
+				glog.Errorf("failed to report state %s", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(mgr.delay):
+			}
+		}
+	}()
+	return done
+}
+
+// StopTunnel stops the tunnel running for a machine through its control endpoint, and waits for it to clean up.
+// A tunnel which does not answer is killed instead. Either way, the routes and host DNS configuration left behind
+// are then cleaned up by the calling process, as a background tunnel can no longer use sudo once the credentials
+// cached when it was started have expired.
+func (mgr *Manager) StopTunnel(machineName string, timeout time.Duration) error {
+	err := requestStop(ControlSocketPath(machineName))
+	if err == nil {
+		deadline := time.Now().Add(timeout)
+		for time.Now().Before(deadline) {
+			if _, err := QueryStatus(machineName); err == ErrTunnelNotRunning {
+				mgr.waitForExit(machineName, time.Until(deadline))
+				return mgr.CleanupNotRunningTunnels()
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+		glog.Warningf("tunnel for %s did not stop within %s, killing it", machineName, timeout)
+	} else if err != ErrTunnelNotRunning {
+		glog.Warningf("unable to stop the tunnel for %s through its control endpoint: %v", machineName, err)
+	}
+
+	tunnels, err := mgr.registry.List()
+	if err != nil {
+		return fmt.Errorf("error listing tunnels from registry: %s", err)
+	}
+	killed := false
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		isRunning, err := checkIfRunning(t.Pid)
+		if err != nil || !isRunning {
+			continue
+		}
+		p, err := os.FindProcess(t.Pid)
+		if err != nil {
+			return errors.Wrapf(err, "finding tunnel process %d", t.Pid)
+		}
+		glog.Infof("killing tunnel process %d", t.Pid)
+		if err := p.Kill(); err != nil {
+			return errors.Wrapf(err, "killing tunnel process %d", t.Pid)
+		}
+		// the route can only be cleaned up once the process is gone
+		for i := 0; i < 10; i++ {
+			if isRunning, err := checkIfRunning(t.Pid); err == nil && !isRunning {
+				break
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+		killed = true
+	}
+	if !killed {
+		if err := mgr.CleanupNotRunningTunnels(); err != nil {
+			glog.Warningf("unable to clean up stale tunnels: %v", err)
+		}
+		return ErrTunnelNotRunning
+	}
+	if err := os.Remove(ControlSocketPath(machineName)); err != nil && !os.IsNotExist(err) {
+		glog.Warningf("removing control socket: %v", err)
+	}
+	return mgr.CleanupNotRunningTunnels()
+}
+
+// waitForExit waits for the processes registered as tunnels for a machine to exit, as they close their control
+// endpoint shortly before exiting, and the entries of running processes are not cleaned up
+func (mgr *Manager) waitForExit(machineName string, timeout time.Duration) {
+	tunnels, err := mgr.registry.List()
+	if err != nil {
+		glog.Warningf("error listing tunnels from registry: %v", err)
+		return
+	}
+	deadline := time.Now().Add(timeout)
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		for time.Now().Before(deadline) {
+			if isRunning, err := checkIfRunning(t.Pid); err != nil || !isRunning {
+				break
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+	}
+}
+
 func (mgr *Manager) startTunnel(ctx context.Context, tunnel controller) (done chan bool, err error) {
 	glog.Info("Setting up tunnel...")
 
@@ -144,6 +334,16 @@ func (mgr *Manager) CleanupNotRunningTunnels() error {
 			if err != nil {
 				return err
 			}
+			if tunnel.LoadBalancerRoute != nil {
+				if err := mgr.router.Cleanup(tunnel.LoadBalancerRoute); err != nil {
+					return err
+				}
+			}
+			if mgr.dns != nil {
+				if err := mgr.dns.cleanupStale(tunnel.Route); err != nil {
+					glog.Warningf("unable to clean up host DNS of %v: %v", tunnel, err)
+				}
+			}
 			err = mgr.registry.Remove(tunnel.Route)
 			if err != nil {
 				return err
@@ -20,11 +20,13 @@ import (
 	"testing"
 
 	"context"
+	"io/ioutil"
 	"os"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/localpath"
 )
 
 func TestTunnelManagerEventHandling(t *testing.T) {
@@ -283,6 +285,63 @@ func TestTunnelManagerCleanup(t *testing.T) {
 
 }
 
+// TestStopTunnelCleansUp checks that the routes of a tunnel which exited without cleaning them up,
+// such as a background tunnel whose sudo credentials expired, are cleaned up by StopTunnel
+func TestStopTunnelCleansUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tunnel")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(old string) { os.Setenv(localpath.MinikubeHome, old) }(os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, dir)
+
+	reg, cleanup := createTestRegistry(t)
+	defer cleanup()
+	notRunningTunnel1, notRunningTunnel2, err := registerNotRunningTunnels(reg)
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	router := &fakeRouter{}
+	for _, id := range []*ID{notRunningTunnel1, notRunningTunnel2} {
+		if err := router.EnsureRouteIsAdded(id.Route); err != nil {
+			t.Fatalf("expected no error got: %v", err)
+		}
+	}
+
+	var s *controlServer
+	s, err = newControlServer(ControlSocketPath("minikube"), func() {
+		// the server can not be closed from within the request it is serving
+		go s.Close()
+	})
+	if err != nil {
+		t.Fatalf("newControlServer: %v", err)
+	}
+	s.Report(&Status{TunnelID: *notRunningTunnel1, MinikubeState: Running})
+
+	manager := NewManager()
+	manager.router = router
+	manager.registry = reg
+
+	if err := manager.StopTunnel("minikube", 5*time.Second); err != nil {
+		t.Fatalf("StopTunnel: %v", err)
+	}
+	if len(router.rt) != 0 {
+		t.Errorf("routes are not cleaned up, got: %s", router.rt.String())
+	}
+	tunnels, err := reg.List()
+	if err != nil {
+		t.Errorf("expected no error got: %v", err)
+	}
+	if len(tunnels) != 0 {
+		t.Errorf("tunnels are not cleaned up, got: %v", tunnels)
+	}
+
+	if err := manager.StopTunnel("minikube", 5*time.Second); err != ErrTunnelNotRunning {
+		t.Errorf("StopTunnel() of a stopped tunnel returned %v, want ErrTunnelNotRunning", err)
+	}
+}
+
 type tunnelStub struct {
 	mockClusterInfo *Status
 	tunnelExists    bool
@@ -26,6 +26,7 @@ import (
 
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"reflect"
 	"strings"
@@ -500,3 +501,52 @@ func TestErrorCreatingTunnel(t *testing.T) {
 		t.Errorf("expected error containing 'error loading machine', got %s", err)
 	}
 }
+
+func TestRemapServices(t *testing.T) {
+	machineName := "testmachine"
+	store := &tests.MockAPI{
+		FakeStore: tests.FakeStore{
+			Hosts: map[string]*host.Host{
+				machineName: {
+					Driver: &tests.MockDriver{
+						CurrentState: state.Running,
+						IP:           "1.2.3.5",
+					},
+				},
+			},
+		},
+	}
+	configLoader := &stubConfigLoader{
+		c: &config.Config{
+			KubernetesConfig: config.KubernetesConfig{
+				ServiceCIDR: "10.96.0.0/12",
+				DNSDomain:   "cluster.local",
+			}},
+	}
+	registry, cleanup := createTestRegistry(t)
+	defer cleanup()
+	defer os.Remove(registry.loadBalancerIPsPath())
+
+	tunnel, err := newTunnel(machineName, store, configLoader, newStubCoreClient(nil), registry, &fakeRouter{})
+	if err != nil {
+		t.Fatalf("error creating tunnel: %s", err)
+	}
+
+	if err := tunnel.remapServices(mustParseCIDR(t, "10.100.0.0/24")); err == nil {
+		t.Errorf("remapping services to a CIDR within the service CIDR should fail")
+	}
+
+	if err := tunnel.remapServices(mustParseCIDR(t, "10.200.0.0/24")); err != nil {
+		t.Fatalf("remapServices: %v", err)
+	}
+	expectedRoute := &Route{Gateway: net.ParseIP("1.2.3.5"), DestCIDR: mustParseCIDR(t, "10.200.0.0/24")}
+	if !tunnel.status.TunnelID.Route.Equal(expectedRoute) || tunnel.status.TunnelID.Route.ClusterDomain != "" {
+		t.Errorf("expected route %s without a cluster domain, got %+v", expectedRoute, tunnel.status.TunnelID.Route)
+	}
+	if tunnel.status.TunnelID.LoadBalancerRoute != nil {
+		t.Errorf("expected no separate LoadBalancer route, got %s", tunnel.status.TunnelID.LoadBalancerRoute)
+	}
+	if tunnel.loadBalancerEmulator.pool == nil {
+		t.Errorf("expected LoadBalancer IPs to be allocated from a pool")
+	}
+}
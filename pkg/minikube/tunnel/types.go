@@ -34,6 +34,9 @@ type Status struct {
 
 	PatchedServices           []string
 	LoadBalancerEmulatorError error
+
+	// ServiceIPs are the ingress IPs assigned to the patched services, by namespace/name
+	ServiceIPs map[string]string
 }
 
 // Clone clones an existing Status
@@ -45,6 +48,7 @@ func (t *Status) Clone() *Status {
 		RouteError:                t.RouteError,
 		PatchedServices:           t.PatchedServices,
 		LoadBalancerEmulatorError: t.LoadBalancerEmulatorError,
+		ServiceIPs:                t.ServiceIPs,
 	}
 }
 